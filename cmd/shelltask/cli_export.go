@@ -0,0 +1,69 @@
+// cmd/shelltask/cli_export.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/UserLeeZJ/shell-task/storage"
+)
+
+// exportTasks 将所有任务导出到 JSON 文件
+func exportTasks(in *cliInput, s storage.Storage) {
+	fmt.Print("导出文件路径 [tasks.json]: ")
+	if !in.Scan() {
+		return
+	}
+	path := strings.TrimSpace(in.Text())
+	if path == "" {
+		path = "tasks.json"
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("创建文件失败: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	if err := storage.ExportTasks(s, file); err != nil {
+		fmt.Printf("导出任务失败: %v\n", err)
+		return
+	}
+
+	fmt.Printf("任务已导出到 %s\n", path)
+}
+
+// importTasks 从 JSON 文件导入任务
+func importTasks(in *cliInput, s storage.Storage) {
+	fmt.Print("导入文件路径 [tasks.json]: ")
+	if !in.Scan() {
+		return
+	}
+	path := strings.TrimSpace(in.Text())
+	if path == "" {
+		path = "tasks.json"
+	}
+
+	fmt.Print("是否覆盖同名任务? (y/n): ")
+	if !in.Scan() {
+		return
+	}
+	overwrite := strings.ToLower(strings.TrimSpace(in.Text())) == "y"
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("打开文件失败: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	n, err := storage.ImportTasks(s, file, overwrite)
+	if err != nil {
+		fmt.Printf("导入任务失败（已导入 %d 个）: %v\n", n, err)
+		return
+	}
+
+	fmt.Printf("已导入 %d 个任务\n", n)
+}
@@ -2,9 +2,7 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
@@ -12,7 +10,7 @@ import (
 )
 
 // listTasks 列出所有任务
-func listTasks(storage *storage.SQLiteStorage) {
+func listTasks(storage storage.Storage) {
 	tasks, err := storage.ListTasks()
 	if err != nil {
 		fmt.Printf("获取任务列表失败: %v\n", err)
@@ -35,11 +33,12 @@ func listTasks(storage *storage.SQLiteStorage) {
 }
 
 // viewTask 查看任务详情
-func viewTask(storage *storage.SQLiteStorage) {
+func viewTask(in *cliInput, storage storage.Storage) {
 	fmt.Print("请输入任务 ID: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	idStr := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	idStr := in.Text()
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
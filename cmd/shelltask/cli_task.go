@@ -2,34 +2,35 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/UserLeeZJ/shell-task/scheduler"
 	"github.com/UserLeeZJ/shell-task/storage"
 )
 
 // createTask 创建新任务
-func createTask(s *storage.SQLiteStorage) {
-	scanner := bufio.NewScanner(os.Stdin)
-
+func createTask(in *cliInput, s storage.Storage) {
 	// 创建任务
 	task := new(storage.TaskInfo)
 	task.Status = "idle"
 
 	fmt.Print("任务名称: ")
-	scanner.Scan()
-	task.Name = scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	task.Name = in.Text()
 	if task.Name == "" {
 		fmt.Println("任务名称不能为空")
 		return
 	}
 
 	fmt.Print("任务类型 (lua/shell): ")
-	scanner.Scan()
-	taskType := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	taskType := in.Text()
 	switch taskType {
 	case "lua":
 		task.Type = "lua"
@@ -41,16 +42,26 @@ func createTask(s *storage.SQLiteStorage) {
 	}
 
 	fmt.Print("任务内容 (脚本内容或命令): ")
-	scanner.Scan()
-	task.Content = scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	task.Content = in.Text()
 	if task.Content == "" {
 		fmt.Println("任务内容不能为空")
 		return
 	}
 
+	fmt.Print("任务参数 (用空格分隔，留空表示没有参数): ")
+	if !in.Scan() {
+		return
+	}
+	task.Args = strings.Fields(in.Text())
+
 	fmt.Print("重复间隔 (秒): ")
-	scanner.Scan()
-	interval, err := strconv.ParseInt(scanner.Text(), 10, 64)
+	if !in.Scan() {
+		return
+	}
+	interval, err := strconv.ParseInt(in.Text(), 10, 64)
 	if err != nil {
 		fmt.Printf("无效的间隔: %v\n", err)
 		return
@@ -58,8 +69,10 @@ func createTask(s *storage.SQLiteStorage) {
 	task.Interval = interval
 
 	fmt.Print("最大运行次数 (0表示无限): ")
-	scanner.Scan()
-	maxRuns, err := strconv.Atoi(scanner.Text())
+	if !in.Scan() {
+		return
+	}
+	maxRuns, err := strconv.Atoi(in.Text())
 	if err != nil {
 		fmt.Printf("无效的最大运行次数: %v\n", err)
 		return
@@ -67,8 +80,10 @@ func createTask(s *storage.SQLiteStorage) {
 	task.MaxRuns = maxRuns
 
 	fmt.Print("重试次数: ")
-	scanner.Scan()
-	retryTimes, err := strconv.Atoi(scanner.Text())
+	if !in.Scan() {
+		return
+	}
+	retryTimes, err := strconv.Atoi(in.Text())
 	if err != nil {
 		fmt.Printf("无效的重试次数: %v\n", err)
 		return
@@ -76,21 +91,52 @@ func createTask(s *storage.SQLiteStorage) {
 	task.RetryTimes = retryTimes
 
 	fmt.Print("超时 (秒): ")
-	scanner.Scan()
-	timeout, err := strconv.ParseInt(scanner.Text(), 10, 64)
+	if !in.Scan() {
+		return
+	}
+	timeout, err := strconv.ParseInt(in.Text(), 10, 64)
 	if err != nil {
 		fmt.Printf("无效的超时: %v\n", err)
 		return
 	}
 	task.Timeout = timeout
 
+	fmt.Print("优先级 (1-10，留空使用默认值5): ")
+	if !in.Scan() {
+		return
+	}
+	if priorityStr := in.Text(); priorityStr != "" {
+		priority, err := strconv.Atoi(priorityStr)
+		if err != nil || priority < 1 || priority > 10 {
+			fmt.Println("无效的优先级，应为 1-10 之间的整数")
+			return
+		}
+		task.Priority = priority
+	}
+
+	fmt.Print("Cron 表达式 (留空表示不使用): ")
+	if !in.Scan() {
+		return
+	}
+	if cron := strings.TrimSpace(in.Text()); cron != "" {
+		if _, err := scheduler.ParseCron(cron); err != nil {
+			fmt.Printf("无效的 Cron 表达式: %v\n", err)
+			return
+		}
+		task.Cron = cron
+	}
+
 	fmt.Print("描述: ")
-	scanner.Scan()
-	task.Description = scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	task.Description = in.Text()
 
 	fmt.Print("标签 (用逗号分隔): ")
-	scanner.Scan()
-	tagsStr := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	tagsStr := in.Text()
 	if tagsStr != "" {
 		task.Tags = strings.Split(tagsStr, ",")
 		for i := range task.Tags {
@@ -107,11 +153,12 @@ func createTask(s *storage.SQLiteStorage) {
 }
 
 // editTask 编辑任务
-func editTask(storage *storage.SQLiteStorage) {
+func editTask(in *cliInput, storage storage.Storage) {
 	fmt.Print("请输入任务 ID: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	idStr := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	idStr := in.Text()
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -129,14 +176,18 @@ func editTask(storage *storage.SQLiteStorage) {
 	fmt.Println("(直接按回车保持原值不变)")
 
 	fmt.Printf("任务名称 [%s]: ", task.Name)
-	scanner.Scan()
-	if name := scanner.Text(); name != "" {
+	if !in.Scan() {
+		return
+	}
+	if name := in.Text(); name != "" {
 		task.Name = name
 	}
 
 	fmt.Printf("任务类型 [%s]: ", task.Type)
-	scanner.Scan()
-	if taskType := scanner.Text(); taskType != "" {
+	if !in.Scan() {
+		return
+	}
+	if taskType := in.Text(); taskType != "" {
 		switch taskType {
 		case "lua":
 			task.Type = "lua"
@@ -148,14 +199,18 @@ func editTask(storage *storage.SQLiteStorage) {
 	}
 
 	fmt.Printf("任务内容 [%s...]: ", truncateString(task.Content, 20))
-	scanner.Scan()
-	if content := scanner.Text(); content != "" {
+	if !in.Scan() {
+		return
+	}
+	if content := in.Text(); content != "" {
 		task.Content = content
 	}
 
 	fmt.Printf("重复间隔 [%d]: ", task.Interval)
-	scanner.Scan()
-	if intervalStr := scanner.Text(); intervalStr != "" {
+	if !in.Scan() {
+		return
+	}
+	if intervalStr := in.Text(); intervalStr != "" {
 		interval, err := strconv.ParseInt(intervalStr, 10, 64)
 		if err != nil {
 			fmt.Printf("无效的间隔: %v，保持原值不变\n", err)
@@ -165,8 +220,10 @@ func editTask(storage *storage.SQLiteStorage) {
 	}
 
 	fmt.Printf("最大运行次数 [%d]: ", task.MaxRuns)
-	scanner.Scan()
-	if maxRunsStr := scanner.Text(); maxRunsStr != "" {
+	if !in.Scan() {
+		return
+	}
+	if maxRunsStr := in.Text(); maxRunsStr != "" {
 		maxRuns, err := strconv.Atoi(maxRunsStr)
 		if err != nil {
 			fmt.Printf("无效的最大运行次数: %v，保持原值不变\n", err)
@@ -176,8 +233,10 @@ func editTask(storage *storage.SQLiteStorage) {
 	}
 
 	fmt.Printf("重试次数 [%d]: ", task.RetryTimes)
-	scanner.Scan()
-	if retryTimesStr := scanner.Text(); retryTimesStr != "" {
+	if !in.Scan() {
+		return
+	}
+	if retryTimesStr := in.Text(); retryTimesStr != "" {
 		retryTimes, err := strconv.Atoi(retryTimesStr)
 		if err != nil {
 			fmt.Printf("无效的重试次数: %v，保持原值不变\n", err)
@@ -187,8 +246,10 @@ func editTask(storage *storage.SQLiteStorage) {
 	}
 
 	fmt.Printf("超时 [%d]: ", task.Timeout)
-	scanner.Scan()
-	if timeoutStr := scanner.Text(); timeoutStr != "" {
+	if !in.Scan() {
+		return
+	}
+	if timeoutStr := in.Text(); timeoutStr != "" {
 		timeout, err := strconv.ParseInt(timeoutStr, 10, 64)
 		if err != nil {
 			fmt.Printf("无效的超时: %v，保持原值不变\n", err)
@@ -197,15 +258,46 @@ func editTask(storage *storage.SQLiteStorage) {
 		}
 	}
 
+	fmt.Printf("优先级 [%d]: ", task.Priority)
+	if !in.Scan() {
+		return
+	}
+	if priorityStr := in.Text(); priorityStr != "" {
+		priority, err := strconv.Atoi(priorityStr)
+		if err != nil || priority < 1 || priority > 10 {
+			fmt.Println("无效的优先级，保持原值不变")
+		} else {
+			task.Priority = priority
+		}
+	}
+
+	fmt.Printf("Cron 表达式 [%s] (输入 - 清除): ", task.Cron)
+	if !in.Scan() {
+		return
+	}
+	if cron := strings.TrimSpace(in.Text()); cron == "-" {
+		task.Cron = ""
+	} else if cron != "" {
+		if _, err := scheduler.ParseCron(cron); err != nil {
+			fmt.Printf("无效的 Cron 表达式: %v，保持原值不变\n", err)
+		} else {
+			task.Cron = cron
+		}
+	}
+
 	fmt.Printf("描述 [%s]: ", task.Description)
-	scanner.Scan()
-	if description := scanner.Text(); description != "" {
+	if !in.Scan() {
+		return
+	}
+	if description := in.Text(); description != "" {
 		task.Description = description
 	}
 
 	fmt.Printf("标签 [%s]: ", strings.Join(task.Tags, ", "))
-	scanner.Scan()
-	if tagsStr := scanner.Text(); tagsStr != "" {
+	if !in.Scan() {
+		return
+	}
+	if tagsStr := in.Text(); tagsStr != "" {
 		task.Tags = strings.Split(tagsStr, ",")
 		for i := range task.Tags {
 			task.Tags[i] = strings.TrimSpace(task.Tags[i])
@@ -2,9 +2,7 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
@@ -13,12 +11,13 @@ import (
 	"github.com/UserLeeZJ/shell-task/storage"
 )
 
-// deleteTask 删除任务
-func deleteTask(storage *storage.SQLiteStorage) {
+// deleteTask 删除任务，正在运行的任务会先被停止
+func deleteTask(in *cliInput, manager *manager.TaskManager) {
 	fmt.Print("请输入任务 ID: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	idStr := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	idStr := in.Text()
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -27,14 +26,16 @@ func deleteTask(storage *storage.SQLiteStorage) {
 	}
 
 	fmt.Print("确认删除? (y/n): ")
-	scanner.Scan()
-	confirm := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	confirm := in.Text()
 	if confirm != "y" && confirm != "Y" {
 		fmt.Println("已取消")
 		return
 	}
 
-	if err := storage.DeleteTask(id); err != nil {
+	if err := manager.DeleteTask(id); err != nil {
 		fmt.Printf("删除任务失败: %v\n", err)
 		return
 	}
@@ -43,11 +44,12 @@ func deleteTask(storage *storage.SQLiteStorage) {
 }
 
 // runTask 运行任务
-func runTask(storage *storage.SQLiteStorage, manager *manager.TaskManager) {
+func runTask(in *cliInput, storage storage.Storage, manager *manager.TaskManager) {
 	fmt.Print("请输入任务 ID: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	idStr := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	idStr := in.Text()
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -69,11 +71,12 @@ func runTask(storage *storage.SQLiteStorage, manager *manager.TaskManager) {
 }
 
 // stopTask 停止任务
-func stopTask(storage *storage.SQLiteStorage, manager *manager.TaskManager) {
+func stopTask(in *cliInput, storage storage.Storage, manager *manager.TaskManager) {
 	fmt.Print("请输入任务 ID: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	idStr := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	idStr := in.Text()
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -114,12 +117,12 @@ func listScripts(executor *lua.Executor) {
 }
 
 // createScript 创建 Lua 脚本
-func createScript(executor *lua.Executor) {
-	scanner := bufio.NewScanner(os.Stdin)
-
+func createScript(in *cliInput, executor *lua.Executor) {
 	fmt.Print("脚本名称: ")
-	scanner.Scan()
-	name := scanner.Text()
+	if !in.Scan() {
+		return
+	}
+	name := in.Text()
 	if name == "" {
 		fmt.Println("脚本名称不能为空")
 		return
@@ -132,8 +135,10 @@ func createScript(executor *lua.Executor) {
 	fmt.Println("请输入脚本内容 (输入 EOF 结束):")
 	var contentBuilder strings.Builder
 	for {
-		scanner.Scan()
-		line := scanner.Text()
+		if !in.Scan() {
+			return
+		}
+		line := in.Text()
 		if line == "EOF" {
 			break
 		}
@@ -147,6 +152,11 @@ func createScript(executor *lua.Executor) {
 		return
 	}
 
+	if err := executor.CheckSyntax(content); err != nil {
+		fmt.Printf("脚本语法错误: %v\n", err)
+		return
+	}
+
 	if err := executor.SaveScript(name, content); err != nil {
 		fmt.Printf("保存脚本失败: %v\n", err)
 		return
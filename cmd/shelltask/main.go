@@ -5,11 +5,13 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/UserLeeZJ/shell-task/lua"
 	"github.com/UserLeeZJ/shell-task/manager"
@@ -25,6 +27,7 @@ func main() {
 		dbPath    string
 		scriptDir string
 		noUI      bool
+		watch     time.Duration
 		help      bool
 		version   bool
 	)
@@ -32,6 +35,7 @@ func main() {
 	flag.StringVar(&dbPath, "db", "", "SQLite 数据库路径")
 	flag.StringVar(&scriptDir, "scripts", "", "Lua 脚本目录")
 	flag.BoolVar(&noUI, "no-ui", false, "不启动 UI 界面")
+	flag.DurationVar(&watch, "watch", 0, "定期从数据库重新加载任务的间隔，0 表示不重新加载")
 	flag.BoolVar(&help, "help", false, "显示帮助信息")
 	flag.BoolVar(&version, "version", false, "显示版本信息")
 	flag.Parse()
@@ -78,7 +82,11 @@ func main() {
 	luaExecutor := lua.NewExecutor(scriptDir)
 
 	// 创建任务管理器
-	taskManager := manager.NewTaskManager(sqliteStorage, luaExecutor)
+	taskManager := manager.NewTaskManager(sqliteStorage, luaExecutor,
+		manager.WithReloadErrorHandler(func(err error) {
+			log.Printf("重新加载任务失败: %v", err)
+		}),
+	)
 
 	// 启动任务管理器
 	if err := taskManager.Start(); err != nil {
@@ -86,13 +94,19 @@ func main() {
 	}
 	defer taskManager.Stop()
 
+	// 定期加载其他进程对任务的修改
+	taskManager.Watch(watch)
+
+	// 监听中断信号，保证退出时执行上面的清理逻辑
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
 	// 如果不启动 UI 界面，则进入守护模式
 	if noUI {
 		log.Println("进入守护模式，按 Ctrl+C 退出")
 
 		// 等待中断信号
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 		<-sigCh
 
 		log.Println("收到中断信号，正在退出...")
@@ -100,12 +114,91 @@ func main() {
 	}
 
 	// 使用简单的命令行界面
-	runCLI(sqliteStorage, taskManager, luaExecutor)
+	runCLI(os.Stdin, sigCh, sqliteStorage, taskManager, luaExecutor)
+}
+
+// lineReader 按需从输入中读取一行，使读取操作可以与信号一起 select
+type lineReader struct {
+	scanner *bufio.Scanner
+	lines   chan string
+	next    chan struct{}
+}
+
+// newLineReader 创建按需读取的行读取器
+func newLineReader(in io.Reader) *lineReader {
+	r := &lineReader{
+		scanner: bufio.NewScanner(in),
+		lines:   make(chan string),
+		next:    make(chan struct{}),
+	}
+	go r.loop()
+	return r
+}
+
+// loop 每收到一次读取请求就读取一行，输入结束时关闭 lines 通道
+func (r *lineReader) loop() {
+	defer close(r.lines)
+	for range r.next {
+		if !r.scanner.Scan() {
+			return
+		}
+		r.lines <- r.scanner.Text()
+	}
+}
+
+// readLine 读取一行输入，收到中断信号或输入结束时返回 false
+func (r *lineReader) readLine(sigCh <-chan os.Signal) (string, bool) {
+	select {
+	case r.next <- struct{}{}:
+	case <-sigCh:
+		return "", false
+	}
+
+	select {
+	case line, ok := <-r.lines:
+		return line, ok
+	case <-sigCh:
+		return "", false
+	}
+}
+
+// cliInput 是命令行界面及其子菜单共用的输入，用法与 bufio.Scanner 相同
+// 收到中断信号或输入结束后 Scan 始终返回 false，子菜单随之返回，由 runCLI 退出
+type cliInput struct {
+	reader *lineReader
+	sigCh  <-chan os.Signal
+	line   string
+	closed bool
+}
+
+// newCLIInput 创建从 in 读取、收到 sigCh 中的信号时结束的输入
+func newCLIInput(in io.Reader, sigCh <-chan os.Signal) *cliInput {
+	return &cliInput{reader: newLineReader(in), sigCh: sigCh}
 }
 
-// runCLI 运行命令行界面
-func runCLI(storage *storage.SQLiteStorage, manager *manager.TaskManager, executor *lua.Executor) {
-	scanner := bufio.NewScanner(os.Stdin)
+// Scan 读取下一行输入，收到中断信号或输入结束时返回 false
+func (in *cliInput) Scan() bool {
+	if in.closed {
+		return false
+	}
+
+	line, ok := in.reader.readLine(in.sigCh)
+	if !ok {
+		in.closed = true
+		return false
+	}
+	in.line = line
+	return true
+}
+
+// Text 返回最近一次读取的行
+func (in *cliInput) Text() string {
+	return in.line
+}
+
+// runCLI 运行命令行界面，在主菜单或子菜单中收到中断信号或输入结束时返回，由调用方执行清理
+func runCLI(r io.Reader, sigCh <-chan os.Signal, storage storage.Storage, manager *manager.TaskManager, executor *lua.Executor) {
+	in := newCLIInput(r, sigCh)
 
 	for {
 		fmt.Println("\n=== Shell Task 命令行界面 ===")
@@ -118,36 +211,50 @@ func runCLI(storage *storage.SQLiteStorage, manager *manager.TaskManager, execut
 		fmt.Println("7. 停止任务")
 		fmt.Println("8. 列出 Lua 脚本")
 		fmt.Println("9. 创建 Lua 脚本")
+		fmt.Println("10. 导出任务")
+		fmt.Println("11. 导入任务")
 		fmt.Println("0. 退出")
 		fmt.Print("\n请选择操作: ")
 
-		scanner.Scan()
-		choice := scanner.Text()
+		if !in.Scan() {
+			fmt.Println("\n正在退出...")
+			return
+		}
 
-		switch choice {
+		switch in.Text() {
 		case "1":
 			listTasks(storage)
 		case "2":
-			viewTask(storage)
+			viewTask(in, storage)
 		case "3":
-			createTask(storage)
+			createTask(in, storage)
 		case "4":
-			editTask(storage)
+			editTask(in, storage)
 		case "5":
-			deleteTask(storage)
+			deleteTask(in, manager)
 		case "6":
-			runTask(storage, manager)
+			runTask(in, storage, manager)
 		case "7":
-			stopTask(storage, manager)
+			stopTask(in, storage, manager)
 		case "8":
 			listScripts(executor)
 		case "9":
-			createScript(executor)
+			createScript(in, executor)
+		case "10":
+			exportTasks(in, storage)
+		case "11":
+			importTasks(in, storage)
 		case "0":
 			fmt.Println("正在退出...")
 			return
 		default:
 			fmt.Println("无效的选择，请重试")
 		}
+
+		// 子菜单中收到中断信号或输入结束
+		if in.closed {
+			fmt.Println("\n正在退出...")
+			return
+		}
 	}
 }
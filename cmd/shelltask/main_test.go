@@ -0,0 +1,108 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureStdout 将标准输出重定向到管道，返回的通道在输出中出现 want 时关闭
+func captureStdout(t *testing.T, want string) <-chan struct{} {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() {
+		os.Stdout = stdout
+		w.Close()
+	})
+
+	found := make(chan struct{})
+	go func() {
+		var output strings.Builder
+		buf := make([]byte, 256)
+		for {
+			n, err := r.Read(buf)
+			output.Write(buf[:n])
+			if strings.Contains(output.String(), want) {
+				close(found)
+				io.Copy(io.Discard, r)
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return found
+}
+
+// TestRunCLIInterrupt 测试在主菜单和子菜单中等待输入时收到中断信号，命令行界面都会退出
+func TestRunCLIInterrupt(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string // 发送中断信号前的输入
+		prompt string // 等待输入时显示的提示
+	}{
+		{"main menu", "", "请选择操作: "},
+		{"view task", "2\n", "请输入任务 ID: "},
+		{"create task", "3\n", "任务名称: "},
+		{"export tasks", "10\n", "导出文件路径 [tasks.json]: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waiting := captureStdout(t, tt.prompt)
+
+			// 使用写入初始输入后不再产生输入的管道模拟阻塞的终端
+			pr, pw := io.Pipe()
+			defer pw.Close()
+			go io.WriteString(pw, tt.input)
+
+			sigCh := make(chan os.Signal, 1)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				runCLI(pr, sigCh, nil, nil, nil)
+			}()
+
+			select {
+			case <-waiting:
+			case <-time.After(time.Second):
+				t.Fatalf("Expected prompt %q to be shown", tt.prompt)
+			}
+			sigCh <- os.Interrupt
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Expected runCLI to return after interrupt, but it didn't")
+			}
+		})
+	}
+}
+
+// TestRunCLIExit 测试输入退出命令和输入结束时命令行界面正常返回
+func TestRunCLIExit(t *testing.T) {
+	inputs := []string{"0\n", ""}
+
+	for _, input := range inputs {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			runCLI(strings.NewReader(input), make(chan os.Signal), nil, nil, nil)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected runCLI to return for input %q, but it didn't", input)
+		}
+	}
+}
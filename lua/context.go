@@ -0,0 +1,153 @@
+// lua/context.go
+package lua
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/UserLeeZJ/shell-task/scheduler"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ctxGlobalName 是脚本中访问任务上下文的全局表名
+const ctxGlobalName = "ctx"
+
+// argGlobalName 是脚本中访问任务参数的全局表名
+const argGlobalName = "arg"
+
+// ExecuteStringWithTaskContext 执行 Lua 脚本字符串，并将任务上下文作为全局表 ctx 暴露给脚本
+// 脚本执行成功后，ctx 中新增或修改的值会写回任务上下文，被设置为 nil 的键会从任务上下文中删除
+func (e *Executor) ExecuteStringWithTaskContext(ctx context.Context, script string, tc *scheduler.TaskContext) error {
+	_, err := e.execute(ctx, script, tc, nil)
+	return err
+}
+
+// execute 执行 Lua 脚本字符串，返回脚本通过 return 返回的第一个值
+// args 不为 nil 时作为全局表 arg 暴露给脚本
+func (e *Executor) execute(ctx context.Context, script string, tc *scheduler.TaskContext, args []string) (interface{}, error) {
+	L, release := e.acquireState()
+	ok := false
+	defer func() { release(ok) }()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// 限制指令数
+	var limitCtx *instructionLimitContext
+	if e.maxInsts > 0 {
+		limitCtx = newInstructionLimitContext(ctx, e.maxInsts)
+		ctx = limitCtx
+	}
+	L.SetContext(ctx)
+
+	var snapshot map[string]interface{}
+	if tc != nil {
+		snapshot = exposeTaskContext(L, tc)
+	}
+	if args != nil {
+		exposeArgs(L, args)
+	}
+
+	fn, err := L.LoadString(script)
+	if err != nil {
+		return nil, err
+	}
+	L.Push(fn)
+	if err := L.PCall(0, 1, nil); err != nil {
+		if limitCtx != nil && limitCtx.Exceeded() {
+			return nil, fmt.Errorf("%w (limit %d)", ErrInstructionLimit, e.maxInsts)
+		}
+		return nil, err
+	}
+
+	result, err := toGoValue(L.Get(-1), make(map[*lua.LTable]bool))
+	L.Pop(1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert script result: %w", err)
+	}
+
+	if tc != nil {
+		mergeTaskContext(L, tc, snapshot)
+	}
+	ok = true
+	return result, nil
+}
+
+// taskContextFrom 从 context.Context 中获取正在执行的任务的上下文
+func taskContextFrom(ctx context.Context) *scheduler.TaskContext {
+	if ctx == nil {
+		return nil
+	}
+	if task := scheduler.TaskFromContext(ctx); task != nil {
+		return task.GetContext()
+	}
+	return nil
+}
+
+// exposeTaskContext 将任务上下文中可以编码为 JSON 的值写入全局表 ctx，返回写入时各键对应的值
+func exposeTaskContext(L *lua.LState, tc *scheduler.TaskContext) map[string]interface{} {
+	table := L.NewTable()
+	snapshot := make(map[string]interface{})
+
+	for key, value := range tc.GetAll() {
+		lv, err := fromGoValue(L, value)
+		if err != nil {
+			continue // 无法表示为 Lua 值的数据不暴露给脚本
+		}
+
+		table.RawSetString(key, lv)
+		snapshot[key], _ = toGoValue(lv, make(map[*lua.LTable]bool))
+	}
+
+	L.SetGlobal(ctxGlobalName, table)
+	return snapshot
+}
+
+// exposeArgs 将任务参数按顺序写入全局表 arg，与 Lua 命令行一致，第一个参数为 arg[1]
+func exposeArgs(L *lua.LState, args []string) {
+	table := L.CreateTable(len(args), 0)
+	for _, arg := range args {
+		table.Append(lua.LString(arg))
+	}
+	L.SetGlobal(argGlobalName, table)
+}
+
+// mergeTaskContext 将脚本对全局表 ctx 的修改写回任务上下文，未修改的值保持原有的 Go 类型
+func mergeTaskContext(L *lua.LState, tc *scheduler.TaskContext, snapshot map[string]interface{}) {
+	table, ok := L.GetGlobal(ctxGlobalName).(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	seen := make(map[string]bool)
+	table.ForEach(func(key, value lua.LValue) {
+		name, ok := key.(lua.LString)
+		if !ok {
+			return
+		}
+		seen[string(name)] = true
+
+		goValue, err := toGoValue(value, make(map[*lua.LTable]bool))
+		if err != nil {
+			return
+		}
+		if old, exists := snapshot[string(name)]; exists && reflect.DeepEqual(old, goValue) {
+			return
+		}
+
+		// 整数值写回为 int，便于使用 GetInt 读取
+		if f, ok := goValue.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			goValue = int(f)
+		}
+		tc.Set(string(name), goValue)
+	})
+
+	for key := range snapshot {
+		if !seen[key] {
+			tc.Delete(key)
+		}
+	}
+}
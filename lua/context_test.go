@@ -0,0 +1,61 @@
+package lua
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/UserLeeZJ/shell-task/scheduler"
+)
+
+// TestLuaTaskContext 测试在 Lua 中读写任务上下文
+func TestLuaTaskContext(t *testing.T) {
+	e := NewExecutor(t.TempDir())
+
+	task := scheduler.NewTask(
+		scheduler.WithName("lua-ctx"),
+		scheduler.WithContextValue("host", "example.com"),
+		scheduler.WithContextValue("port", 8080),
+		scheduler.WithContextValue("tags", []string{"a"}),
+		scheduler.WithContextValue("obsolete", true),
+		scheduler.WithContextValue("callback", func() {}),
+	)
+
+	job := e.CreateLuaJob(`
+		assert(ctx.host == "example.com", "unexpected host")
+		assert(ctx.callback == nil, "functions should not be exposed")
+		ctx.url = "http://" .. ctx.host .. ":" .. ctx.port
+		ctx.attempts = 2
+		table.insert(ctx.tags, "b")
+		ctx.obsolete = nil
+	`)
+	if err := job(scheduler.WithTaskInContext(context.Background(), task)); err != nil {
+		t.Fatalf("Failed to execute script: %v", err)
+	}
+
+	tc := task.GetContext()
+	if url, _ := tc.GetString("url"); url != "http://example.com:8080" {
+		t.Errorf("Expected url to be written back, got %q", url)
+	}
+	if attempts, ok := tc.GetInt("attempts"); !ok || attempts != 2 {
+		t.Errorf("Expected attempts 2 as int, got %v", attempts)
+	}
+	if tags, _ := tc.Get("tags"); !reflect.DeepEqual(tags, []interface{}{"a", "b"}) {
+		t.Errorf("Expected modified tags, got %#v", tags)
+	}
+	// 未修改的值保持原有类型
+	if port, ok := tc.GetInt("port"); !ok || port != 8080 {
+		t.Errorf("Expected port to keep int type, got %v", port)
+	}
+	if tc.Has("obsolete") {
+		t.Error("Expected obsolete key to be deleted")
+	}
+	if !tc.Has("callback") {
+		t.Error("Expected unexposed values to be kept")
+	}
+
+	// 没有任务时脚本中不存在 ctx
+	if err := e.ExecuteString(context.Background(), `assert(ctx == nil)`); err != nil {
+		t.Errorf("Expected ctx to be nil without a task: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,17 +13,47 @@ import (
 	"time"
 
 	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
 )
 
+// ResultKey 是 CreateLuaJob 在任务上下文中保存脚本返回值的键
+const ResultKey = "lua.result"
+
 // Executor 是 Lua 脚本执行器
 type Executor struct {
-	scriptDir string
-	modules   map[string]lua.LGFunction
-	mutex     sync.Mutex
+	scriptDir   string
+	modules     map[string]lua.LGFunction
+	functions   map[string]Function
+	httpClient  *http.Client
+	httpTimeout time.Duration
+	maxInsts    int  // 单次执行的最大指令数，0 表示不限制
+	pooling     bool // 是否复用 Lua 状态
+	checkOnSave bool // 保存脚本前是否检查语法
+	statePool   sync.Pool
+	generation  uint64 // 配置版本，注册模块等操作会使已缓存的状态失效
+	mutex       sync.Mutex
+}
+
+// ExecutorOption 定义执行器的配置选项
+type ExecutorOption func(*Executor)
+
+// WithInstructionLimit 限制单次脚本执行的最大指令数，超过时脚本以 ErrInstructionLimit 中止
+// 用于防止死循环的脚本长时间占用工作协程，n <= 0 表示不限制
+func WithInstructionLimit(n int) ExecutorOption {
+	return func(e *Executor) {
+		e.maxInsts = n
+	}
+}
+
+// WithSyntaxCheckOnSave 设置 SaveScript 保存脚本前是否检查语法，检查失败时不保存
+func WithSyntaxCheckOnSave(check bool) ExecutorOption {
+	return func(e *Executor) {
+		e.checkOnSave = check
+	}
 }
 
 // NewExecutor 创建一个新的 Lua 执行器
-func NewExecutor(scriptDir string) *Executor {
+func NewExecutor(scriptDir string, opts ...ExecutorOption) *Executor {
 	if scriptDir == "" {
 		// 如果未指定脚本目录，使用默认目录
 		homeDir, err := os.UserHomeDir()
@@ -36,10 +67,20 @@ func NewExecutor(scriptDir string) *Executor {
 	// 确保脚本目录存在
 	os.MkdirAll(scriptDir, 0755)
 
-	return &Executor{
-		scriptDir: scriptDir,
-		modules:   make(map[string]lua.LGFunction),
+	e := &Executor{
+		scriptDir:   scriptDir,
+		modules:     make(map[string]lua.LGFunction),
+		functions:   make(map[string]Function),
+		httpClient:  &http.Client{},
+		httpTimeout: DefaultHTTPTimeout,
 	}
+
+	// 应用选项
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
 }
 
 // RegisterModule 注册一个 Lua 模块
@@ -47,18 +88,19 @@ func (e *Executor) RegisterModule(name string, loader lua.LGFunction) {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 	e.modules[name] = loader
+	e.generation++
 }
 
 // ExecuteString 执行 Lua 脚本字符串
+// 如果 ctx 中包含正在执行的任务（见 scheduler.TaskFromContext），任务上下文会作为全局表 ctx 暴露给脚本
 func (e *Executor) ExecuteString(ctx context.Context, script string) error {
-	L := e.newState()
-	defer L.Close()
-
-	// 设置上下文
-	L.SetContext(ctx)
+	return e.ExecuteStringWithTaskContext(ctx, script, taskContextFrom(ctx))
+}
 
-	// 执行脚本
-	return L.DoString(script)
+// ExecuteStringWithResult 执行 Lua 脚本字符串，返回脚本通过 return 返回的值
+// 数字被转换为 float64，表被转换为 []interface{} 或 map[string]interface{}，没有返回值时返回 nil
+func (e *Executor) ExecuteStringWithResult(ctx context.Context, script string) (interface{}, error) {
+	return e.execute(ctx, script, taskContextFrom(ctx), nil)
 }
 
 // ExecuteFile 执行 Lua 脚本文件
@@ -100,12 +142,30 @@ func (e *Executor) ListScripts() ([]string, error) {
 	return scripts, nil
 }
 
+// CheckSyntax 编译脚本以检查语法，不执行脚本
+// 脚本存在语法错误时返回的错误包含出错的位置
+func (e *Executor) CheckSyntax(script string) error {
+	chunk, err := parse.Parse(strings.NewReader(script), "<string>")
+	if err != nil {
+		return err
+	}
+	_, err = lua.Compile(chunk, "<string>")
+	return err
+}
+
 // SaveScript 保存 Lua 脚本到文件
+// 设置了 WithSyntaxCheckOnSave 时先检查脚本语法，存在语法错误时不保存
 func (e *Executor) SaveScript(name string, content string) error {
 	if !strings.HasSuffix(name, ".lua") {
 		name = name + ".lua"
 	}
 
+	if e.checkOnSave {
+		if err := e.CheckSyntax(content); err != nil {
+			return fmt.Errorf("syntax error in %s: %w", name, err)
+		}
+	}
+
 	filename := filepath.Join(e.scriptDir, name)
 	return os.WriteFile(filename, []byte(content), 0644)
 }
@@ -122,17 +182,32 @@ func (e *Executor) DeleteScript(name string) error {
 
 // newState 创建一个新的 Lua 状态
 func (e *Executor) newState() *lua.LState {
+	L, _ := e.newStateWithGeneration()
+	return L
+}
+
+// newStateWithGeneration 创建一个新的 Lua 状态，并返回创建时的配置版本
+func (e *Executor) newStateWithGeneration() (*lua.LState, uint64) {
 	L := lua.NewState()
 
+	// 注册内置模块，可以被同名的自定义模块覆盖
+	L.PreloadModule("json", jsonLoader)
+
+	e.mutex.Lock()
+	L.PreloadModule("http", httpLoader(e.httpClient, e.httpTimeout))
+
 	// 注册模块
 	for name, loader := range e.modules {
 		L.PreloadModule(name, loader)
 	}
+	generation := e.generation
 
-	// 注册全局函数
+	// 注册全局函数，自定义函数可以覆盖内置函数
 	e.registerGlobalFunctions(L)
+	e.registerFunctions(L)
+	e.mutex.Unlock()
 
-	return L
+	return L, generation
 }
 
 // registerGlobalFunctions 注册全局函数
@@ -155,11 +230,8 @@ func (e *Executor) registerGlobalFunctions(L *lua.LState) {
 		// 获取参数
 		seconds := L.CheckNumber(1)
 
-		// 获取上下文
-		ctx := L.Context()
-		if ctx == nil {
-			ctx = context.Background()
-		}
+		// 获取上下文，等待不消耗指令预算
+		ctx := hostContext(L)
 
 		// 创建定时器
 		timer := time.NewTimer(time.Duration(seconds) * time.Second)
@@ -179,15 +251,25 @@ func (e *Executor) registerGlobalFunctions(L *lua.LState) {
 }
 
 // CreateLuaJob 创建一个执行 Lua 脚本的任务函数
+// 脚本的返回值会以 ResultKey 为键保存到任务上下文中
 func (e *Executor) CreateLuaJob(script string) func(ctx context.Context) error {
+	return e.CreateLuaJobWithArgs(script, nil)
+}
+
+// CreateLuaJobWithArgs 创建一个带参数的 Lua 任务函数，参数通过全局表 arg 传给脚本（arg[1] 为第一个参数）
+func (e *Executor) CreateLuaJobWithArgs(script string, args []string) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
-		err := e.ExecuteString(ctx, script)
+		result, err := e.execute(ctx, script, taskContextFrom(ctx), args)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
 				return err
 			}
 			return fmt.Errorf("lua script error: %w", err)
 		}
+
+		if tc := taskContextFrom(ctx); tc != nil && result != nil {
+			tc.Set(ResultKey, result)
+		}
 		return nil
 	}
 }
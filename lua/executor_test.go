@@ -0,0 +1,222 @@
+package lua
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/UserLeeZJ/shell-task/scheduler"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// TestInstructionLimit 测试死循环脚本在超过指令数限制时中止
+func TestInstructionLimit(t *testing.T) {
+	e := NewExecutor(t.TempDir(), WithInstructionLimit(100000))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.CreateLuaJob(`while true do end`)(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrInstructionLimit) {
+			t.Errorf("Expected ErrInstructionLimit, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected infinite loop to be aborted")
+	}
+
+	// 未超过限制的脚本正常执行，且每次执行重新计数
+	for i := 0; i < 3; i++ {
+		if err := e.ExecuteString(context.Background(), `local n = 0 for i = 1, 1000 do n = n + i end`); err != nil {
+			t.Errorf("Expected script within limit to succeed, got %v", err)
+		}
+	}
+}
+
+// TestInstructionLimitCounting 检查 gopher-lua 每执行一条指令调用一次 Context.Done
+// 指令数限制依赖这一内部行为，升级 gopher-lua 后如果该测试失败，需要重新实现 instructionLimitContext
+func TestInstructionLimitCounting(t *testing.T) {
+	const budget = 1000000
+	L := lua.NewState()
+	defer L.Close()
+
+	limitCtx := newInstructionLimitContext(context.Background(), budget)
+	L.SetContext(limitCtx)
+	if err := L.DoString(`local n = 0 for i = 1, 1000 do n = n + i end`); err != nil {
+		t.Fatalf("Failed to run script: %v", err)
+	}
+
+	// 每次循环至少执行加法和 FORLOOP 两条指令
+	used := budget - atomic.LoadInt64(&limitCtx.remaining)
+	if used < 2000 || used > 2100 {
+		t.Errorf("Expected about 2000 instructions to be counted, got %d; "+
+			"gopher-lua may no longer call Context.Done once per instruction", used)
+	}
+
+	// 宿主函数使用的上下文不消耗指令预算
+	before := atomic.LoadInt64(&limitCtx.remaining)
+	host := hostContext(L)
+	for i := 0; i < 10; i++ {
+		select {
+		case <-host.Done():
+		default:
+		}
+	}
+	if after := atomic.LoadInt64(&limitCtx.remaining); after != before {
+		t.Errorf("Expected host context not to consume the budget, used %d", before-after)
+	}
+}
+
+// TestInfiniteLoopCancellation 测试没有指令数限制时死循环脚本随上下文取消而中止
+func TestInfiniteLoopCancellation(t *testing.T) {
+	e := NewExecutor(t.TempDir())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := e.ExecuteString(ctx, `while true do end`); err == nil {
+		t.Error("Expected error after context timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected script to abort promptly, took %v", elapsed)
+	}
+}
+
+// TestStatePooling 测试复用的 Lua 状态在执行之间不会泄漏数据
+func TestStatePooling(t *testing.T) {
+	e := NewExecutor(t.TempDir(), WithStatePooling(true))
+	ctx := context.Background()
+
+	polluter := `
+		leaked = true
+		string.leaked = true
+		local json = require("json")
+		json.encode = nil
+		print = nil
+	`
+	checker := `
+		assert(leaked == nil, "global leaked")
+		assert(string.leaked == nil, "library field leaked")
+		assert(require("json").encode ~= nil, "module change leaked")
+		assert(print ~= nil, "builtin was not restored")
+	`
+
+	for i := 0; i < 5; i++ {
+		if err := e.ExecuteString(ctx, polluter); err != nil {
+			t.Fatalf("Failed to execute script: %v", err)
+		}
+		if err := e.ExecuteString(ctx, checker); err != nil {
+			t.Fatalf("State leaked between executions: %v", err)
+		}
+	}
+
+	// 出错后仍然可以继续执行
+	if err := e.ExecuteString(ctx, `error("boom")`); err == nil {
+		t.Error("Expected script error")
+	}
+	if err := e.ExecuteString(ctx, checker); err != nil {
+		t.Errorf("Expected execution to succeed after an error: %v", err)
+	}
+}
+
+// BenchmarkExecuteString 比较复用和不复用 Lua 状态时执行简单脚本的性能
+func BenchmarkExecuteString(b *testing.B) {
+	for _, pooling := range []bool{false, true} {
+		name := "Fresh"
+		if pooling {
+			name = "Pooled"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			e := NewExecutor(b.TempDir(), WithStatePooling(pooling))
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := e.ExecuteString(ctx, `local x = 1 + 1`); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestExecuteStringWithResult 测试获取脚本的返回值
+func TestExecuteStringWithResult(t *testing.T) {
+	e := NewExecutor(t.TempDir())
+
+	tests := []struct {
+		script string
+		want   interface{}
+	}{
+		{`return 1 + 41`, float64(42)},
+		{`return "done"`, "done"},
+		{`return {1, 2, 3}`, []interface{}{float64(1), float64(2), float64(3)}},
+		{`return {status = "ok", items = {"a"}}`, map[string]interface{}{"status": "ok", "items": []interface{}{"a"}}},
+		{`local x = 1`, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := e.ExecuteStringWithResult(context.Background(), tt.script)
+		if err != nil {
+			t.Errorf("Script %q failed: %v", tt.script, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Script %q: expected %#v, got %#v", tt.script, tt.want, got)
+		}
+	}
+
+	if _, err := e.ExecuteStringWithResult(context.Background(), `return print`); err == nil {
+		t.Error("Expected error for unconvertible result")
+	}
+
+	// 任务函数将返回值保存到任务上下文
+	task := scheduler.NewTask(scheduler.WithName("lua-result"))
+	job := e.CreateLuaJob(`return "computed"`)
+	if err := job(scheduler.WithTaskInContext(context.Background(), task)); err != nil {
+		t.Fatalf("Failed to run job: %v", err)
+	}
+	if result, _ := task.GetContext().GetString(ResultKey); result != "computed" {
+		t.Errorf("Expected result in task context, got %q", result)
+	}
+}
+
+// TestCheckSyntax 测试检查脚本语法而不执行
+func TestCheckSyntax(t *testing.T) {
+	dir := t.TempDir()
+	e := NewExecutor(dir, WithSyntaxCheckOnSave(true))
+
+	// 语法正确的脚本不会被执行，运行时错误不会被检查出来
+	if err := e.CheckSyntax(`error("not executed")`); err != nil {
+		t.Errorf("Expected valid script to pass, got %v", err)
+	}
+
+	err := e.CheckSyntax("local x = 1\nlocal y = = 2\nprint(y)\n")
+	if err == nil {
+		t.Fatal("Expected syntax error")
+	}
+	if !strings.Contains(err.Error(), "line:2") {
+		t.Errorf("Expected error to include the line number, got %v", err)
+	}
+
+	// 开启保存前检查时语法错误的脚本不会被保存
+	if err := e.SaveScript("broken", "local x = "); err == nil {
+		t.Error("Expected SaveScript to reject a script with a syntax error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "broken.lua")); !os.IsNotExist(err) {
+		t.Errorf("Expected broken script not to be saved, got %v", err)
+	}
+	if err := e.SaveScript("ok", "local x = 1"); err != nil {
+		t.Errorf("Expected valid script to be saved, got %v", err)
+	}
+}
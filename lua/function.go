@@ -0,0 +1,91 @@
+// lua/function.go
+package lua
+
+import (
+	"encoding/json"
+	"fmt"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Function 是可以注册为 Lua 全局函数的 Go 函数
+// 参数中的字符串、数字、布尔值和表分别被转换为 string、float64、bool 以及
+// []interface{}（数组）或 map[string]interface{}（对象），nil 被转换为 nil
+type Function func(args ...interface{}) (interface{}, error)
+
+// RegisterFunction 将 Go 函数注册为 Lua 全局函数
+// 脚本中调用时返回函数的结果；函数返回错误时返回 nil 和错误信息
+func (e *Executor) RegisterFunction(name string, fn Function) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.functions[name] = fn
+	e.generation++
+}
+
+// registerFunctions 将注册的 Go 函数设置为全局函数，调用方需持有 e.mutex
+func (e *Executor) registerFunctions(L *lua.LState) {
+	for name, fn := range e.functions {
+		L.SetGlobal(name, L.NewFunction(wrapFunction(fn)))
+	}
+}
+
+// wrapFunction 将 Go 函数包装为 Lua 函数
+func wrapFunction(fn Function) lua.LGFunction {
+	return func(L *lua.LState) int {
+		top := L.GetTop()
+		args := make([]interface{}, top)
+		for i := 1; i <= top; i++ {
+			arg, err := toGoValue(L.Get(i), make(map[*lua.LTable]bool))
+			if err != nil {
+				L.ArgError(i, err.Error())
+				return 0
+			}
+			args[i-1] = arg
+		}
+
+		result, err := fn(args...)
+		if err == nil {
+			var value lua.LValue
+			value, err = fromGoValue(L, result)
+			if err == nil {
+				L.Push(value)
+				return 1
+			}
+		}
+
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+}
+
+// fromGoValue 将 Go 值转换为 Lua 值
+// 基本类型直接转换，其他类型按 JSON 编码后的结构转换
+func fromGoValue(L *lua.LState, value interface{}) (lua.LValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return lua.LNil, nil
+	case lua.LValue:
+		return v, nil
+	case bool:
+		return lua.LBool(v), nil
+	case string:
+		return lua.LString(v), nil
+	case int:
+		return lua.LNumber(v), nil
+	case int64:
+		return lua.LNumber(v), nil
+	case float64:
+		return lua.LNumber(v), nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %T to a Lua value: %w", value, err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return toLuaValue(L, decoded), nil
+}
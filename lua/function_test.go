@@ -0,0 +1,58 @@
+package lua
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestRegisterFunction 测试在 Lua 中调用注册的 Go 函数
+func TestRegisterFunction(t *testing.T) {
+	e := NewExecutor(t.TempDir())
+
+	e.RegisterFunction("sum", func(args ...interface{}) (interface{}, error) {
+		total := 0.0
+		for _, arg := range args {
+			switch v := arg.(type) {
+			case float64:
+				total += v
+			case []interface{}:
+				for _, item := range v {
+					n, ok := item.(float64)
+					if !ok {
+						return nil, fmt.Errorf("unexpected item %v", item)
+					}
+					total += n
+				}
+			default:
+				return nil, errors.New("sum expects numbers")
+			}
+		}
+		return total, nil
+	})
+	e.RegisterFunction("describe", func(args ...interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"count": len(args),
+			"first": args[0],
+			"flag":  args[1],
+		}, nil
+	})
+
+	script := `
+		assert(sum(1, 2, 3) == 6, "sum of numbers")
+		assert(sum({1, 2}, 3.5) == 6.5, "sum of table and number")
+
+		local value, err = sum("x")
+		assert(value == nil and err == "sum expects numbers", "error returned to Lua")
+
+		local info = describe({name = "job"}, true)
+		assert(info.count == 2 and info.first.name == "job" and info.flag == true, "table result")
+	`
+	for _, pooling := range []bool{false, true} {
+		e.pooling = pooling
+		if err := e.ExecuteString(context.Background(), script); err != nil {
+			t.Errorf("Script failed (pooling=%v): %v", pooling, err)
+		}
+	}
+}
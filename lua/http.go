@@ -0,0 +1,89 @@
+// lua/http.go
+package lua
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// DefaultHTTPTimeout 是 http 模块请求的默认超时时间
+const DefaultHTTPTimeout = 30 * time.Second
+
+// SetHTTPTimeout 设置 http 模块单次请求的超时时间，0 表示只受任务上下文限制
+func (e *Executor) SetHTTPTimeout(timeout time.Duration) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.httpTimeout = timeout
+	e.generation++
+}
+
+// httpLoader 返回内置 http 模块的加载函数，提供 http.get 和 http.post
+// 请求使用脚本的上下文，任务超时或取消时请求随之中止
+func httpLoader(client *http.Client, timeout time.Duration) lua.LGFunction {
+	return func(L *lua.LState) int {
+		mod := L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+			"get": func(L *lua.LState) int {
+				return doHTTPRequest(L, client, timeout, http.MethodGet, L.CheckString(1), "", L.OptTable(2, nil))
+			},
+			"post": func(L *lua.LState) int {
+				return doHTTPRequest(L, client, timeout, http.MethodPost, L.CheckString(1), L.OptString(2, ""), L.OptTable(3, nil))
+			},
+		})
+		L.Push(mod)
+		return 1
+	}
+}
+
+// doHTTPRequest 发送 HTTP 请求，成功时返回状态码和响应体，失败时返回 nil, nil 和错误信息
+func doHTTPRequest(L *lua.LState, client *http.Client, timeout time.Duration, method, url, body string, headers *lua.LTable) int {
+	ctx := hostContext(L)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	pushError := func(err error) int {
+		L.Push(lua.LNil)
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 3
+	}
+
+	var reader io.Reader
+	if method != http.MethodGet {
+		reader = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return pushError(err)
+	}
+
+	if headers != nil {
+		headers.ForEach(func(key, value lua.LValue) {
+			req.Header.Set(key.String(), value.String())
+		})
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return pushError(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return pushError(err)
+	}
+
+	L.Push(lua.LNumber(resp.StatusCode))
+	L.Push(lua.LString(data))
+	L.Push(lua.LNil)
+	return 3
+}
@@ -0,0 +1,92 @@
+package lua
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newHTTPTestServer 创建测试服务器：/echo 回显请求方法、请求头和请求体，/slow 阻塞直到请求被取消
+func newHTTPTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, r.Method+" "+r.Header.Get("X-Token")+" "+string(body))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestHTTPGetPost 测试 http.get 和 http.post
+func TestHTTPGetPost(t *testing.T) {
+	server := newHTTPTestServer(t)
+
+	result := runScript(t, `
+		local http = require("http")
+		local status, body, err = http.get("`+server.URL+`/echo")
+		assert(err == nil, err)
+		local pstatus, pbody, perr = http.post("`+server.URL+`/echo", "payload", {["X-Token"] = "secret"})
+		assert(perr == nil, perr)
+		result = status .. "|" .. body .. "|" .. pstatus .. "|" .. pbody
+	`)
+
+	want := "201|GET  |201|POST secret payload"
+	if result.String() != want {
+		t.Errorf("Expected %q, got %q", want, result.String())
+	}
+
+	result = runScript(t, `
+		local http = require("http")
+		local status, body, err = http.get("http://127.0.0.1:0/")
+		result = tostring(status) .. " " .. tostring(body) .. " " .. tostring(err ~= nil)
+	`)
+	if result.String() != "nil nil true" {
+		t.Errorf("Expected request error, got %q", result.String())
+	}
+}
+
+// TestHTTPCancellation 测试任务上下文取消和请求超时会中止请求
+func TestHTTPCancellation(t *testing.T) {
+	server := newHTTPTestServer(t)
+	script := `
+		local http = require("http")
+		local status, body, err = http.get("` + server.URL + `/slow")
+		assert(err == nil, err)
+	`
+
+	e := NewExecutor(t.TempDir())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := e.ExecuteString(ctx, script)
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected request to be aborted promptly, took %v", elapsed)
+	}
+
+	// 默认超时同样生效
+	e.SetHTTPTimeout(100 * time.Millisecond)
+	start = time.Now()
+	if err := e.ExecuteString(context.Background(), script); err == nil {
+		t.Error("Expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected request to time out promptly, took %v", elapsed)
+	}
+}
@@ -0,0 +1,151 @@
+// lua/json.go
+package lua
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// errRecursiveTable 表示表中存在循环引用，无法编码为 JSON
+var errRecursiveTable = errors.New("cannot encode recursive table")
+
+// jsonLoader 加载内置的 json 模块，提供 json.encode 和 json.decode
+func jsonLoader(L *lua.LState) int {
+	mod := L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"encode": jsonEncode,
+		"decode": jsonDecode,
+	})
+	L.Push(mod)
+	return 1
+}
+
+// jsonEncode 将 Lua 值编码为 JSON 字符串，失败时返回 nil 和错误信息
+func jsonEncode(L *lua.LState) int {
+	value, err := toGoValue(L.CheckAny(1), make(map[*lua.LTable]bool))
+	if err == nil {
+		var data []byte
+		data, err = json.Marshal(value)
+		if err == nil {
+			L.Push(lua.LString(data))
+			return 1
+		}
+	}
+
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err.Error()))
+	return 2
+}
+
+// jsonDecode 将 JSON 字符串解码为 Lua 值，失败时返回 nil 和错误信息
+func jsonDecode(L *lua.LState) int {
+	var value interface{}
+	if err := json.Unmarshal([]byte(L.CheckString(1)), &value); err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	L.Push(toLuaValue(L, value))
+	return 1
+}
+
+// toGoValue 将 Lua 值转换为可以被 encoding/json 编码的 Go 值
+// 键为 1..n 连续整数的表编码为数组，空表编码为空数组，其余表编码为对象
+func toGoValue(value lua.LValue, visited map[*lua.LTable]bool) (interface{}, error) {
+	switch v := value.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LBool:
+		return bool(v), nil
+	case lua.LNumber:
+		return float64(v), nil
+	case lua.LString:
+		return string(v), nil
+	case *lua.LTable:
+		if visited[v] {
+			return nil, errRecursiveTable
+		}
+		visited[v] = true
+		defer delete(visited, v)
+
+		return tableToGoValue(v, visited)
+	default:
+		return nil, fmt.Errorf("cannot encode value of type %s", value.Type())
+	}
+}
+
+// tableToGoValue 将 Lua 表转换为 Go 切片或映射
+func tableToGoValue(table *lua.LTable, visited map[*lua.LTable]bool) (interface{}, error) {
+	length := table.Len()
+	count := 0
+	table.ForEach(func(lua.LValue, lua.LValue) { count++ })
+
+	if count == length {
+		array := make([]interface{}, 0, length)
+		for i := 1; i <= length; i++ {
+			item, err := toGoValue(table.RawGetInt(i), visited)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, item)
+		}
+		return array, nil
+	}
+
+	object := make(map[string]interface{}, count)
+	var err error
+	table.ForEach(func(key, value lua.LValue) {
+		if err != nil {
+			return
+		}
+
+		var name string
+		switch k := key.(type) {
+		case lua.LString:
+			name = string(k)
+		case lua.LNumber:
+			name = k.String()
+		default:
+			err = fmt.Errorf("cannot encode table key of type %s", key.Type())
+			return
+		}
+
+		object[name], err = toGoValue(value, visited)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// toLuaValue 将 encoding/json 解码得到的 Go 值转换为 Lua 值
+func toLuaValue(L *lua.LState, value interface{}) lua.LValue {
+	switch v := value.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case []interface{}:
+		table := L.CreateTable(len(v), 0)
+		for i, item := range v {
+			table.RawSetInt(i+1, toLuaValue(L, item))
+		}
+		return table
+	case map[string]interface{}:
+		table := L.CreateTable(0, len(v))
+		for key, item := range v {
+			table.RawSetString(key, toLuaValue(L, item))
+		}
+		return table
+	default:
+		return lua.LString(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,68 @@
+package lua
+
+import (
+	"context"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// runScript 执行脚本并返回名为 result 的全局变量
+func runScript(t *testing.T, script string) lua.LValue {
+	t.Helper()
+
+	e := NewExecutor(t.TempDir())
+	L := e.newState()
+	defer L.Close()
+	L.SetContext(context.Background())
+
+	if err := L.DoString(script); err != nil {
+		t.Fatalf("Failed to execute script: %v", err)
+	}
+	return L.GetGlobal("result")
+}
+
+// TestJSONRoundTrip 测试解码 JSON、修改字段后重新编码
+func TestJSONRoundTrip(t *testing.T) {
+	result := runScript(t, `
+		local json = require("json")
+		local data = json.decode('{"name": "backup", "retries": 3, "enabled": true, "tags": ["a", "b"], "meta": {"owner": null, "level": 1.5}}')
+		data.retries = data.retries + 1
+		data.enabled = not data.enabled
+		table.insert(data.tags, "c")
+		data.meta.owner = "ops"
+		result = json.encode(data)
+	`)
+
+	want := `{"enabled":false,"meta":{"level":1.5,"owner":"ops"},"name":"backup","retries":4,"tags":["a","b","c"]}`
+	if result.String() != want {
+		t.Errorf("Expected %s, got %s", want, result.String())
+	}
+}
+
+// TestJSONValues 测试各种类型的编码和错误处理
+func TestJSONValues(t *testing.T) {
+	tests := []struct {
+		script string
+		want   string
+	}{
+		{`result = json.encode(nil)`, `null`},
+		{`result = json.encode(42)`, `42`},
+		{`result = json.encode("hi")`, `"hi"`},
+		{`result = json.encode({})`, `[]`},
+		{`result = json.encode({1, {true, false}, "x"})`, `[1,[true,false],"x"]`},
+		{`result = json.encode({[1] = "a", [3] = "c"})`, `{"1":"a","3":"c"}`},
+		{`result = tostring(json.decode("null"))`, `nil`},
+		{`result = json.decode("[10, 20]")[2]`, `20`},
+		{`local t = {}; t.self = t; local s, err = json.encode(t); result = err`, errRecursiveTable.Error()},
+		{`local v, err = json.decode("{bad"); result = tostring(v) .. (err and " error" or "")`, `nil error`},
+		{`local s, err = json.encode({f = print}); result = tostring(s)`, `nil`},
+	}
+
+	for _, tt := range tests {
+		result := runScript(t, `local json = require("json")`+"\n"+tt.script)
+		if result.String() != tt.want {
+			t.Errorf("Script %q: expected %s, got %s", tt.script, tt.want, result.String())
+		}
+	}
+}
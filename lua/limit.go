@@ -0,0 +1,76 @@
+// lua/limit.go
+package lua
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ErrInstructionLimit 表示脚本执行的指令数超过了限制
+var ErrInstructionLimit = errors.New("lua instruction limit exceeded")
+
+// instructionLimitContext 在 Lua 虚拟机中统计执行的指令数
+// gopher-lua 没有提供指令计数的钩子。设置了上下文的 LState 每执行一条指令都会调用一次 Done 检查是否取消
+// （gopher-lua v1.1.1 的 mainLoopWithContext），因此 Done 的调用次数即为执行的指令数，
+// 超过预算后 Done 返回已关闭的通道使脚本中止。这依赖 gopher-lua 的内部实现，go.mod 固定了其版本，
+// 升级后由 TestInstructionLimitCounting 检查该行为是否仍然成立。
+// 宿主函数（sleep、http 等）应通过 hostContext 获取上下文，避免其中的 Done 调用消耗指令预算
+type instructionLimitContext struct {
+	context.Context
+	remaining int64
+	exceeded  chan struct{}
+	once      sync.Once
+}
+
+// newInstructionLimitContext 创建限制指令数的上下文
+func newInstructionLimitContext(parent context.Context, limit int) *instructionLimitContext {
+	return &instructionLimitContext{
+		Context:   parent,
+		remaining: int64(limit),
+		exceeded:  make(chan struct{}),
+	}
+}
+
+// Done 统计一条指令，超过预算时返回已关闭的通道
+func (c *instructionLimitContext) Done() <-chan struct{} {
+	if atomic.AddInt64(&c.remaining, -1) < 0 {
+		c.once.Do(func() { close(c.exceeded) })
+		return c.exceeded
+	}
+	return c.Context.Done()
+}
+
+// Err 超过预算时返回 ErrInstructionLimit，否则返回父上下文的错误
+func (c *instructionLimitContext) Err() error {
+	if c.Exceeded() {
+		return ErrInstructionLimit
+	}
+	return c.Context.Err()
+}
+
+// Exceeded 返回是否已超过指令预算
+func (c *instructionLimitContext) Exceeded() bool {
+	select {
+	case <-c.exceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// hostContext 返回宿主函数等待或发起请求时使用的上下文
+// 去掉指令计数的包装，只保留脚本执行的取消和超时，使宿主函数不消耗指令预算
+func hostContext(L *lua.LState) context.Context {
+	ctx := L.Context()
+	if ctx == nil {
+		return context.Background()
+	}
+	if limitCtx, ok := ctx.(*instructionLimitContext); ok {
+		return limitCtx.Context
+	}
+	return ctx
+}
@@ -0,0 +1,111 @@
+// lua/pool.go
+package lua
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+// WithStatePooling 设置是否复用 Lua 状态
+// 启用后执行完成的状态会被重置到初始的全局环境并放回池中，省去每次创建状态和注册模块的开销；
+// 执行出错的状态不会被复用
+func WithStatePooling(enabled bool) ExecutorOption {
+	return func(e *Executor) {
+		e.pooling = enabled
+	}
+}
+
+// pooledState 是可复用的 Lua 状态及其初始环境的快照
+type pooledState struct {
+	L          *lua.LState
+	generation uint64                                    // 创建时执行器的配置版本
+	baseline   map[*lua.LTable]map[lua.LValue]lua.LValue // 全局表及其中各个表的初始内容
+}
+
+// newPooledState 创建 Lua 状态并记录初始环境
+func (e *Executor) newPooledState() *pooledState {
+	L, generation := e.newStateWithGeneration()
+	ps := &pooledState{
+		L:          L,
+		generation: generation,
+		baseline:   make(map[*lua.LTable]map[lua.LValue]lua.LValue),
+	}
+
+	// 记录全局表和其中的库表（string、table、package 等），以及模块的加载器和缓存
+	globals := L.G.Global
+	ps.baseline[globals] = snapshotTable(globals)
+	globals.ForEach(func(_, value lua.LValue) {
+		if table, ok := value.(*lua.LTable); ok && table != globals {
+			ps.baseline[table] = snapshotTable(table)
+		}
+	})
+	for _, field := range []string{"loaded", "preload"} {
+		if table, ok := L.GetField(L.GetGlobal("package"), field).(*lua.LTable); ok {
+			ps.baseline[table] = snapshotTable(table)
+		}
+	}
+
+	return ps
+}
+
+// snapshotTable 复制表中的所有键值
+func snapshotTable(table *lua.LTable) map[lua.LValue]lua.LValue {
+	snapshot := make(map[lua.LValue]lua.LValue)
+	table.ForEach(func(key, value lua.LValue) {
+		snapshot[key] = value
+	})
+	return snapshot
+}
+
+// reset 将状态恢复到初始环境，脚本新增的全局变量和修改的库函数都会被还原
+func (ps *pooledState) reset() {
+	ps.L.SetTop(0)
+	ps.L.RemoveContext()
+
+	for table, snapshot := range ps.baseline {
+		var added []lua.LValue
+		table.ForEach(func(key, _ lua.LValue) {
+			if _, exists := snapshot[key]; !exists {
+				added = append(added, key)
+			}
+		})
+		for _, key := range added {
+			table.RawSet(key, lua.LNil)
+		}
+		for key, value := range snapshot {
+			table.RawSet(key, value)
+		}
+	}
+}
+
+// acquireState 获取用于一次执行的 Lua 状态，执行结束后必须调用 release，ok 表示执行是否成功
+func (e *Executor) acquireState() (L *lua.LState, release func(ok bool)) {
+	if !e.pooling {
+		L := e.newState()
+		return L, func(bool) { L.Close() }
+	}
+
+	ps, _ := e.statePool.Get().(*pooledState)
+	if ps == nil || ps.generation != e.currentGeneration() {
+		if ps != nil {
+			ps.L.Close()
+		}
+		ps = e.newPooledState()
+	}
+
+	return ps.L, func(ok bool) {
+		// 出错的状态可能处于不一致的状态，配置变更前创建的状态也不再复用
+		if !ok || ps.generation != e.currentGeneration() {
+			ps.L.Close()
+			return
+		}
+		ps.reset()
+		e.statePool.Put(ps)
+	}
+}
+
+// currentGeneration 返回执行器当前的配置版本
+func (e *Executor) currentGeneration() uint64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.generation
+}
@@ -3,6 +3,7 @@ package shell_task
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/UserLeeZJ/shell-task/scheduler"
@@ -14,6 +15,9 @@ type Task = scheduler.Task
 // Job 定义任务函数类型
 type Job = scheduler.Job
 
+// ResultJob 定义返回结果的任务函数类型
+type ResultJob = scheduler.ResultJob
+
 // JobResult 表示任务执行结果
 type JobResult = scheduler.JobResult
 
@@ -23,6 +27,9 @@ type TaskOption = scheduler.TaskOption
 // Logger 定义了日志接口，支持不同级别的日志记录
 type Logger = scheduler.Logger
 
+// Level 表示日志级别
+type Level = scheduler.Level
+
 // Priority 定义任务优先级
 type Priority = scheduler.Priority
 
@@ -46,6 +53,9 @@ type FixedDelayRetryStrategy = scheduler.FixedDelayRetryStrategy
 // ExponentialBackoffRetryStrategy 指数退避重试策略
 type ExponentialBackoffRetryStrategy = scheduler.ExponentialBackoffRetryStrategy
 
+// DecorrelatedJitterRetryStrategy 去相关抖动重试策略
+type DecorrelatedJitterRetryStrategy = scheduler.DecorrelatedJitterRetryStrategy
+
 // TaskBuilder 提供流式API创建和配置任务
 type TaskBuilder = scheduler.TaskBuilder
 
@@ -72,6 +82,14 @@ const (
 	PriorityHigh   = scheduler.PriorityHigh
 )
 
+// 日志级别常量
+const (
+	LevelDebug = scheduler.LevelDebug
+	LevelInfo  = scheduler.LevelInfo
+	LevelWarn  = scheduler.LevelWarn
+	LevelError = scheduler.LevelError
+)
+
 // New 创建新的任务实例
 func New(opts ...TaskOption) *Task {
 	return scheduler.NewTask(opts...)
@@ -92,6 +110,11 @@ func NewTaskGroup(name string, logger Logger) *TaskGroup {
 	return scheduler.NewTaskGroup(name, logger)
 }
 
+// NewTaskGroupWithConcurrency 创建限制最大并发数的任务组
+func NewTaskGroupWithConcurrency(name string, logger Logger, maxConcurrent int) *TaskGroup {
+	return scheduler.NewTaskGroupWithConcurrency(name, logger, maxConcurrent)
+}
+
 // NewDefaultTaskGroup 创建一个使用默认日志记录器的任务组
 func NewDefaultTaskGroup(name string) *TaskGroup {
 	return scheduler.NewDefaultTaskGroup(name)
@@ -103,14 +126,36 @@ func NewFuncLogger(logFunc func(format string, args ...any)) Logger {
 	return scheduler.NewFuncLogger(logFunc)
 }
 
+// NewLeveledLogger 创建只输出不低于 min 级别日志的日志记录器
+func NewLeveledLogger(inner Logger, min Level) Logger {
+	return scheduler.NewLeveledLogger(inner, min)
+}
+
+// NewJSONLogger 创建一个以 JSON 格式输出日志的日志记录器
+// 每行输出一个包含 level、msg 和 time 字段的 JSON 对象
+func NewJSONLogger(w io.Writer) Logger {
+	return scheduler.NewJSONLogger(w)
+}
+
 // WorkerPool 表示一个工作池，用于限制并发执行的任务数量
 type WorkerPool = scheduler.WorkerPool
 
+// PoolMetrics 是工作池运行状况的快照
+type PoolMetrics = scheduler.PoolMetrics
+
 // NewWorkerPool 创建一个新的工作池
 func NewWorkerPool(size int, logger Logger) *WorkerPool {
 	return scheduler.NewWorkerPool(size, logger)
 }
 
+// Scheduler 是进程内按名称管理任务的注册表
+type Scheduler = scheduler.Scheduler
+
+// NewScheduler 创建一个新的任务注册表
+func NewScheduler(logger Logger) *Scheduler {
+	return scheduler.NewScheduler(logger)
+}
+
 // TaskWithContextMap 创建一个带上下文的任务，使用 map 传递上下文数据
 func TaskWithContextMap(name string, fn func(ctx context.Context, data map[string]interface{}) error) *Task {
 	return scheduler.TaskWithContextMap(name, fn)
@@ -131,7 +176,12 @@ func Sequence(tasks ...*Task) []*Task {
 	return scheduler.Sequence(tasks...)
 }
 
-// Parallel 创建一个并行任务组，返回一个汇聚任务
+// SequenceStopOnError 创建一个任务序列，任务以错误结束时停止后续任务
+func SequenceStopOnError(tasks ...*Task) []*Task {
+	return scheduler.SequenceStopOnError(tasks...)
+}
+
+// Parallel 创建一个并行任务组，返回一个汇聚任务，并行任务的错误汇总在汇聚任务的 GetLastError 中
 func Parallel(name string, tasks ...*Task) *Task {
 	return scheduler.Parallel(name, tasks...)
 }
@@ -141,6 +191,11 @@ func RunAfter(task *Task, dependencies ...*Task) *Task {
 	return scheduler.RunAfter(task, dependencies...)
 }
 
+// RunAfterDelay 设置任务依赖关系，所有依赖完成后再等待 delay 才启动任务
+func RunAfterDelay(task *Task, delay time.Duration, dependencies ...*Task) *Task {
+	return scheduler.RunAfterDelay(task, delay, dependencies...)
+}
+
 // 重试策略相关函数
 // NewFixedDelayRetryStrategy 创建固定间隔重试策略
 func NewFixedDelayRetryStrategy(delay time.Duration, maxRetries int) *scheduler.FixedDelayRetryStrategy {
@@ -152,6 +207,11 @@ func NewExponentialBackoffRetryStrategy(initialDelay, maxDelay time.Duration, fa
 	return scheduler.NewExponentialBackoffRetryStrategy(initialDelay, maxDelay, factor, maxRetries)
 }
 
+// NewDecorrelatedJitterRetryStrategy 创建去相关抖动重试策略
+func NewDecorrelatedJitterRetryStrategy(baseDelay, maxDelay time.Duration, maxRetries int) *scheduler.DecorrelatedJitterRetryStrategy {
+	return scheduler.NewDecorrelatedJitterRetryStrategy(baseDelay, maxDelay, maxRetries)
+}
+
 // RetryOnNetworkError 包装重试策略，添加网络错误判断
 func RetryOnNetworkError(strategy RetryStrategy) RetryStrategy {
 	return scheduler.RetryOnNetworkError(strategy)
@@ -194,6 +254,7 @@ var (
 	// 基本选项
 	WithName            = scheduler.WithName
 	WithJob             = scheduler.WithJob
+	WithResultJob       = scheduler.WithResultJob
 	WithTimeout         = scheduler.WithTimeout
 	WithRepeat          = scheduler.WithRepeat
 	WithMaxRuns         = scheduler.WithMaxRuns
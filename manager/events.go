@@ -0,0 +1,124 @@
+// manager/events.go
+package manager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/UserLeeZJ/shell-task/storage"
+)
+
+// TaskEventType 表示任务事件类型
+type TaskEventType string
+
+// 任务事件类型常量
+const (
+	TaskEventStarted   TaskEventType = "started"   // 任务已启动
+	TaskEventSucceeded TaskEventType = "succeeded" // 一次执行成功
+	TaskEventFailed    TaskEventType = "failed"    // 一次执行失败
+	TaskEventCompleted TaskEventType = "completed" // 任务达到最大运行次数而结束
+	TaskEventStopped   TaskEventType = "stopped"   // 任务被停止
+	TaskEventPaused    TaskEventType = "paused"    // 任务被暂停
+	TaskEventResumed   TaskEventType = "resumed"   // 任务被恢复
+)
+
+// eventBufferSize 是每个订阅者的事件缓冲区大小，缓冲区已满时新事件会被丢弃
+const eventBufferSize = 64
+
+// TaskEvent 表示任务生命周期事件
+type TaskEvent struct {
+	TaskID int64              // 任务ID
+	Name   string             // 任务名称
+	Type   TaskEventType      // 事件类型
+	Status storage.TaskStatus // 事件对应的任务状态
+	Time   time.Time          // 事件时间
+	Err    error              // 执行错误，仅失败事件设置
+}
+
+// eventBus 将任务事件分发给所有订阅者
+type eventBus struct {
+	mutex       sync.Mutex
+	subscribers map[chan TaskEvent]struct{}
+	closed      bool
+}
+
+// subscribe 添加一个订阅者，事件总线已关闭时返回已关闭的通道
+func (b *eventBus) subscribe() chan TaskEvent {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	ch := make(chan TaskEvent, eventBufferSize)
+	if b.closed {
+		close(ch)
+		return ch
+	}
+
+	if b.subscribers == nil {
+		b.subscribers = make(map[chan TaskEvent]struct{})
+	}
+	b.subscribers[ch] = struct{}{}
+	return ch
+}
+
+// unsubscribe 移除订阅者并关闭其通道
+func (b *eventBus) unsubscribe(ch <-chan TaskEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for sub := range b.subscribers {
+		if sub == ch {
+			delete(b.subscribers, sub)
+			close(sub)
+			return
+		}
+	}
+}
+
+// publish 以非阻塞方式将事件发送给所有订阅者，订阅者缓冲区已满时丢弃该事件
+func (b *eventBus) publish(event TaskEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub <- event:
+		default:
+		}
+	}
+}
+
+// close 关闭所有订阅者的通道，之后发布的事件会被忽略
+func (b *eventBus) close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for sub := range b.subscribers {
+		close(sub)
+	}
+	b.subscribers = nil
+	b.closed = true
+}
+
+// Subscribe 订阅任务生命周期事件
+// 每个订阅者有独立的缓冲区，事件以非阻塞方式发送，订阅者处理过慢时新事件会被丢弃。
+// 调用 Unsubscribe 或停止任务管理器时通道会被关闭
+func (m *TaskManager) Subscribe() <-chan TaskEvent {
+	return m.events.subscribe()
+}
+
+// Unsubscribe 取消订阅并关闭对应的通道
+func (m *TaskManager) Unsubscribe(ch <-chan TaskEvent) {
+	m.events.unsubscribe(ch)
+}
+
+// emit 发布任务事件
+func (m *TaskManager) emit(id int64, name string, eventType TaskEventType, status storage.TaskStatus, err error) {
+	m.events.publish(TaskEvent{
+		TaskID: id,
+		Name:   name,
+		Type:   eventType,
+		Status: status,
+		Time:   time.Now(),
+		Err:    err,
+	})
+}
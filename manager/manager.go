@@ -3,8 +3,10 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
+	"runtime"
 	"sync"
 	"time"
 
@@ -13,23 +15,103 @@ import (
 	"github.com/UserLeeZJ/shell-task/storage"
 )
 
+// CompletionReasonMaxRuns 表示任务因达到最大运行次数而结束
+const CompletionReasonMaxRuns = "max_runs_reached"
+
+// shellName 返回 Shell 任务使用的命令解释器，Windows 上为 cmd，其他系统为 sh
+func shellName() string {
+	if runtime.GOOS == "windows" {
+		return "cmd"
+	}
+	return "sh"
+}
+
+// shellCommand 创建执行 Shell 任务的命令，args 作为命令的位置参数（sh 中为 $1、$2...）
+func shellCommand(ctx context.Context, content string, args []string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", append([]string{"/C", content}, args...)...)
+	}
+	// sh -c 之后的第一个参数是 $0
+	return exec.CommandContext(ctx, "sh", append([]string{"-c", content, "shelltask"}, args...)...)
+}
+
+// TaskCompletionEvent 表示任务结束事件
+type TaskCompletionEvent struct {
+	TaskID   int64     // 任务ID
+	Name     string    // 任务名称
+	RunCount int       // 最终运行次数
+	Reason   string    // 结束原因
+	Disabled bool      // 是否已被自动禁用
+	Time     time.Time // 事件时间
+}
+
 // TaskManager 任务管理器
 type TaskManager struct {
-	storage    *storage.SQLiteStorage
+	storage    storage.Storage
+	storageMu  sync.RWMutex // 保护 storage 字段，迁移存储时切换
 	executor   *lua.Executor
 	workerPool *scheduler.WorkerPool
 	tasks      map[int64]*scheduler.Task
 	mutex      sync.RWMutex
+
+	// 任务结束处理
+	onCompletion        func(TaskCompletionEvent) // 任务结束时的回调
+	autoDisableOnFinish bool                      // 任务结束时是否自动禁用
+
+	// 热加载
+	watchMu       sync.Mutex    // 保护 watchStop
+	watchStop     chan struct{} // 关闭时停止热加载协程
+	onReloadError func(error)   // 热加载失败时的回调
+
+	// 事件订阅
+	events         eventBus                                     // 任务生命周期事件总线
+	webhooks       []*webhook                                   // 任务事件的 webhook 通知
+	onWebhookError func(url string, event TaskEvent, err error) // webhook 重试后仍发送失败时的回调
+}
+
+// ManagerOption 是配置任务管理器的函数类型
+type ManagerOption func(*TaskManager)
+
+// WithCompletionHandler 设置任务达到最大运行次数时的回调函数
+func WithCompletionHandler(handler func(TaskCompletionEvent)) ManagerOption {
+	return func(m *TaskManager) {
+		m.onCompletion = handler
+	}
+}
+
+// WithAutoDisable 设置任务达到最大运行次数时是否自动禁用
+// 禁用的任务状态为 TaskStatusDisabled，不会在启动时被重新加载
+func WithAutoDisable(disable bool) ManagerOption {
+	return func(m *TaskManager) {
+		m.autoDisableOnFinish = disable
+	}
+}
+
+// WithReloadErrorHandler 设置 Watch 重新加载任务失败时的回调函数
+func WithReloadErrorHandler(handler func(error)) ManagerOption {
+	return func(m *TaskManager) {
+		m.onReloadError = handler
+	}
 }
 
 // NewTaskManager 创建一个新的任务管理器
-func NewTaskManager(storage *storage.SQLiteStorage, executor *lua.Executor) *TaskManager {
-	return &TaskManager{
+func NewTaskManager(storage storage.Storage, executor *lua.Executor, opts ...ManagerOption) *TaskManager {
+	m := &TaskManager{
 		storage:    storage,
 		executor:   executor,
 		workerPool: scheduler.NewWorkerPool(5, nil), // 创建一个有5个工作协程的工作池
 		tasks:      make(map[int64]*scheduler.Task),
 	}
+
+	// 应用所有配置项
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// 启动 webhook 通知
+	m.startWebhooks()
+
+	return m
 }
 
 // Start 启动任务管理器
@@ -43,6 +125,9 @@ func (m *TaskManager) Start() error {
 
 // Stop 停止任务管理器
 func (m *TaskManager) Stop() {
+	// 停止热加载
+	m.stopWatch()
+
 	// 停止工作池
 	m.workerPool.Stop()
 
@@ -53,44 +138,163 @@ func (m *TaskManager) Stop() {
 	for _, task := range m.tasks {
 		task.Stop()
 	}
+
+	// 关闭所有事件订阅
+	m.events.close()
 }
 
-// LoadAllTasks 加载所有任务
+// store 返回当前使用的存储
+func (m *TaskManager) store() storage.Storage {
+	m.storageMu.RLock()
+	defer m.storageMu.RUnlock()
+	return m.storage
+}
+
+// LoadAllTasks 加载所有任务，使运行中的任务与存储中的状态一致
+// 启动存储中状态为运行中但尚未加载的任务，暂停或恢复状态发生变化的任务，
+// 停止存储中已取消、已禁用或已删除的任务。可以重复调用，已加载的任务不会被重复启动
 func (m *TaskManager) LoadAllTasks() error {
-	// 获取所有任务
-	tasks, err := m.storage.ListTasks()
+	// 只查询运行中和暂停的任务，不加载已完成、失败的历史任务
+	running, err := m.store().GetTasksByStatus(storage.TaskStatusRunning)
+	if err != nil {
+		return err
+	}
+	paused, err := m.store().GetTasksByStatus(storage.TaskStatusPaused)
 	if err != nil {
 		return err
 	}
 
-	// 加载每个任务
-	for _, taskInfo := range tasks {
-		if taskInfo.Status == storage.TaskStatusRunning {
+	synced := make(map[int64]bool, len(running)+len(paused))
+	for _, taskInfo := range running {
+		synced[taskInfo.ID] = true
+
+		m.mutex.RLock()
+		task, loaded := m.tasks[taskInfo.ID]
+		m.mutex.RUnlock()
+
+		if !loaded {
+			// 上次运行时已达到最大运行次数但未来得及更新状态的任务，直接标记为结束
+			if maxRunsReached(taskInfo) {
+				if err := m.finishTask(taskInfo); err != nil {
+					return err
+				}
+				continue
+			}
+
 			// 如果任务状态为运行中，则启动任务
 			if err := m.StartTask(taskInfo.ID); err != nil {
 				return err
 			}
+		} else if task.GetState() == scheduler.TaskStatePaused {
+			task.Resume()
+		}
+	}
+
+	for _, taskInfo := range paused {
+		synced[taskInfo.ID] = true
+
+		m.mutex.RLock()
+		task, loaded := m.tasks[taskInfo.ID]
+		m.mutex.RUnlock()
+
+		if loaded {
+			task.Pause()
+		}
+	}
+
+	// 其余已加载的任务逐个查询存储中的状态，停止已取消、已禁用或已删除的任务
+	for _, id := range m.GetRunningTasks() {
+		if synced[id] {
+			continue
+		}
+
+		taskInfo, err := m.store().GetTask(id)
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			m.unloadTask(id)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if taskInfo.Status == storage.TaskStatusCancelled || taskInfo.Status == storage.TaskStatusDisabled {
+			m.unloadTask(id)
 		}
 	}
 
 	return nil
 }
 
+// unloadTask 停止任务并将其从任务映射中移除，不修改存储中的状态
+func (m *TaskManager) unloadTask(id int64) {
+	m.mutex.Lock()
+	task, exists := m.tasks[id]
+	delete(m.tasks, id)
+	m.mutex.Unlock()
+
+	if exists {
+		task.Stop()
+	}
+}
+
+// Watch 每隔 interval 重新调用 LoadAllTasks，加载其他进程对任务的修改
+// 重新加载失败时调用 WithReloadErrorHandler 设置的回调。再次调用会替换之前的热加载，
+// 调用 Stop 停止任务管理器时热加载也随之停止
+func (m *TaskManager) Watch(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	m.watchMu.Lock()
+	if m.watchStop != nil {
+		close(m.watchStop)
+	}
+	stop := make(chan struct{})
+	m.watchStop = stop
+	m.watchMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := m.LoadAllTasks(); err != nil && m.onReloadError != nil {
+					m.onReloadError(err)
+				}
+			}
+		}
+	}()
+}
+
+// stopWatch 停止热加载协程
+func (m *TaskManager) stopWatch() {
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+
+	if m.watchStop != nil {
+		close(m.watchStop)
+		m.watchStop = nil
+	}
+}
+
 // StartTask 启动任务
 func (m *TaskManager) StartTask(id int64) error {
 	// 获取任务信息
-	taskInfo, err := m.storage.GetTask(id)
+	taskInfo, err := m.store().GetTask(id)
 	if err != nil {
 		return err
 	}
 
 	// 检查任务是否已经在运行
-	m.mutex.RLock()
-	_, exists := m.tasks[id]
-	m.mutex.RUnlock()
-	if exists {
+	if m.IsTaskRunning(id) {
 		return fmt.Errorf("task %d is already running", id)
 	}
+	if maxRunsReached(taskInfo) {
+		return fmt.Errorf("task %d has already reached its max runs (%d)", id, taskInfo.MaxRuns)
+	}
 
 	// 创建任务
 	task, err := m.createTask(taskInfo)
@@ -98,23 +302,44 @@ func (m *TaskManager) StartTask(id int64) error {
 		return err
 	}
 
-	// 添加到任务映射
+	// 添加到任务映射，并发启动同一任务时只有一个能成功
 	m.mutex.Lock()
+	if _, exists := m.tasks[id]; exists {
+		m.mutex.Unlock()
+		return fmt.Errorf("task %d is already running", id)
+	}
 	m.tasks[id] = task
 	m.mutex.Unlock()
 
 	// 更新任务状态
+	previousStatus := taskInfo.Status
 	taskInfo.Status = storage.TaskStatusRunning
-	if err := m.storage.SaveTask(taskInfo); err != nil {
+	if err := m.store().SaveTask(taskInfo); err != nil {
+		m.removeTask(id)
 		return err
 	}
 
-	// 提交任务到工作池
-	m.workerPool.Submit(task)
+	// 提交任务到工作池，提交失败时撤销上面的修改
+	if err := m.workerPool.Submit(task); err != nil {
+		m.removeTask(id)
+		taskInfo.Status = previousStatus
+		if saveErr := m.store().SaveTask(taskInfo); saveErr != nil {
+			return fmt.Errorf("failed to submit task %d: %w (restoring status: %v)", id, err, saveErr)
+		}
+		return fmt.Errorf("failed to submit task %d: %w", id, err)
+	}
+	m.emit(id, taskInfo.Name, TaskEventStarted, storage.TaskStatusRunning, nil)
 
 	return nil
 }
 
+// removeTask 将任务从任务映射中移除
+func (m *TaskManager) removeTask(id int64) {
+	m.mutex.Lock()
+	delete(m.tasks, id)
+	m.mutex.Unlock()
+}
+
 // StopTask 停止任务
 func (m *TaskManager) StopTask(id int64) error {
 	// 获取任务
@@ -134,12 +359,125 @@ func (m *TaskManager) StopTask(id int64) error {
 	m.mutex.Unlock()
 
 	// 更新任务状态
-	taskInfo, err := m.storage.GetTask(id)
+	taskInfo, err := m.store().GetTask(id)
 	if err != nil {
 		return err
 	}
 	taskInfo.Status = storage.TaskStatusCancelled
-	return m.storage.SaveTask(taskInfo)
+	if err := m.store().SaveTask(taskInfo); err != nil {
+		return err
+	}
+
+	m.emit(id, taskInfo.Name, TaskEventStopped, storage.TaskStatusCancelled, nil)
+	return nil
+}
+
+// DeleteTask 删除任务
+// 任务正在运行时先停止任务并从任务映射中移除，再从存储中删除，避免已删除的任务继续执行
+func (m *TaskManager) DeleteTask(id int64) error {
+	m.mutex.RLock()
+	task, exists := m.tasks[id]
+	m.mutex.RUnlock()
+
+	if exists {
+		task.Stop()
+
+		m.mutex.Lock()
+		delete(m.tasks, id)
+		m.mutex.Unlock()
+	}
+
+	if err := m.store().DeleteTask(id); err != nil {
+		return err
+	}
+
+	if exists {
+		m.emit(id, task.GetName(), TaskEventStopped, storage.TaskStatusCancelled, nil)
+	}
+	return nil
+}
+
+// PauseTask 暂停正在运行的任务
+// 暂停的任务保留在运行任务列表中，不再开始新的执行，直到调用 ResumeTask
+func (m *TaskManager) PauseTask(id int64) error {
+	m.mutex.RLock()
+	task, exists := m.tasks[id]
+	m.mutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("task %d is not running", id)
+	}
+
+	if !task.Pause() {
+		return fmt.Errorf("task %d cannot be paused in state %s", id, task.GetState())
+	}
+
+	if err := m.store().UpdateTaskStatus(id, storage.TaskStatusPaused); err != nil {
+		return err
+	}
+
+	m.emit(id, task.GetName(), TaskEventPaused, storage.TaskStatusPaused, nil)
+	return nil
+}
+
+// ResumeTask 恢复已暂停的任务
+// 如果任务在暂停状态下被重新加载（例如程序重启后），则重新启动该任务
+func (m *TaskManager) ResumeTask(id int64) error {
+	m.mutex.RLock()
+	task, exists := m.tasks[id]
+	m.mutex.RUnlock()
+
+	if !exists {
+		status, err := m.GetTaskStatus(id)
+		if err != nil {
+			return err
+		}
+		if status != storage.TaskStatusPaused {
+			return fmt.Errorf("task %d is not paused", id)
+		}
+		return m.StartTask(id)
+	}
+
+	if !task.Resume() {
+		return fmt.Errorf("task %d is not paused", id)
+	}
+
+	if err := m.store().UpdateTaskStatus(id, storage.TaskStatusRunning); err != nil {
+		return err
+	}
+
+	m.emit(id, task.GetName(), TaskEventResumed, storage.TaskStatusRunning, nil)
+	return nil
+}
+
+// ValidateTask 检查任务能否被调度，但不启动也不执行任务
+// 依次检查任务配置和 cron 表达式、Lua 脚本的语法以及 Shell 任务所需的命令解释器是否可用，
+// 返回的错误说明了失败的原因
+func (m *TaskManager) ValidateTask(id int64) error {
+	taskInfo, err := m.store().GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	task, err := m.createTask(taskInfo)
+	if err != nil {
+		return fmt.Errorf("task %d (%s): %w", id, taskInfo.Name, err)
+	}
+	if err := task.Validate(); err != nil {
+		return fmt.Errorf("task %d (%s): invalid configuration: %w", id, taskInfo.Name, err)
+	}
+
+	switch taskInfo.Type {
+	case storage.TaskTypeLua:
+		if err := m.executor.CheckSyntax(taskInfo.Content); err != nil {
+			return fmt.Errorf("task %d (%s): lua syntax error: %w", id, taskInfo.Name, err)
+		}
+	case storage.TaskTypeShell:
+		if _, err := exec.LookPath(shellName()); err != nil {
+			return fmt.Errorf("task %d (%s): shell not available: %w", id, taskInfo.Name, err)
+		}
+	}
+
+	return nil
 }
 
 // createTask 创建任务
@@ -156,22 +494,36 @@ func (m *TaskManager) createTask(taskInfo *storage.TaskInfo) (*scheduler.Task, e
 		options = append(options, scheduler.WithRepeat(time.Duration(taskInfo.Interval)*time.Second))
 	}
 
-	// 设置最大运行次数
+	// 设置 cron 调度
+	if taskInfo.Cron != "" {
+		if _, err := scheduler.ParseCron(taskInfo.Cron); err != nil {
+			return nil, err
+		}
+		options = append(options, scheduler.WithCron(taskInfo.Cron))
+	}
+
+	// 设置优先级
+	if taskInfo.Priority != 0 {
+		options = append(options, scheduler.WithPriority(scheduler.Priority(taskInfo.Priority)))
+	}
+
+	// 设置最大运行次数，扣除任务加载前已保存的运行次数
 	if taskInfo.MaxRuns > 0 {
-		options = append(options, scheduler.WithMaxRuns(taskInfo.MaxRuns))
+		options = append(options, scheduler.WithMaxRuns(max(taskInfo.MaxRuns-taskInfo.RunCount, 1)))
 	}
 
-	// 创建任务函数
+	// 创建任务函数，复制参数避免调用方修改
+	args := append([]string{}, taskInfo.Args...)
+	content := taskInfo.Content
 	var job scheduler.Job
 	switch taskInfo.Type {
 	case storage.TaskTypeLua:
 		// Lua 脚本任务
-		job = m.executor.CreateLuaJob(taskInfo.Content)
+		job = m.executor.CreateLuaJobWithArgs(content, args)
 	case storage.TaskTypeShell:
 		// Shell 命令任务
 		job = func(ctx context.Context) error {
-			cmd := exec.CommandContext(ctx, "cmd", "/C", taskInfo.Content)
-			return cmd.Run()
+			return shellCommand(ctx, content, args).Run()
 		}
 	default:
 		return nil, fmt.Errorf("unsupported task type: %s", taskInfo.Type)
@@ -181,38 +533,105 @@ func (m *TaskManager) createTask(taskInfo *storage.TaskInfo) (*scheduler.Task, e
 	options = append(options, scheduler.WithJob(job))
 
 	// 添加错误处理
+	// 错误处理器和完成回调使用任务信息的副本，不与调用方共享 *TaskInfo；
+	// infoMutex 保护副本，并使同一任务的存储更新按顺序进行。runFailed 记录本次执行是否失败
+	info := *taskInfo
+	var infoMutex sync.Mutex
+	runFailed := false
 	options = append(options, scheduler.WithErrorHandler(func(err error) {
 		// 更新任务错误信息
-		taskInfo.LastError = err.Error()
-		m.storage.UpdateTaskRunInfo(taskInfo.ID, taskInfo.RunCount, taskInfo.LastRunAt, taskInfo.LastError)
+		infoMutex.Lock()
+		runFailed = true
+		info.LastError = err.Error()
+		m.store().UpdateTaskRunInfo(info.ID, info.RunCount, info.LastRunAt, info.LastError)
+		infoMutex.Unlock()
+
+		m.emit(info.ID, info.Name, TaskEventFailed, storage.TaskStatusFailed, err)
 	}))
 
 	// 添加完成回调
+	// 运行次数以调度器的计数为准，加上任务加载前已保存的次数
+	var task *scheduler.Task
+	baseRunCount := info.RunCount
 	options = append(options, scheduler.WithPostHook(func() {
-		// 更新任务运行信息
-		taskInfo.RunCount++
-		taskInfo.LastRunAt = time.Now()
-		m.storage.UpdateTaskRunInfo(taskInfo.ID, taskInfo.RunCount, taskInfo.LastRunAt, taskInfo.LastError)
+		infoMutex.Lock()
+
+		// 更新任务运行信息，调度器在完成回调之后才计入本次执行
+		info.RunCount = baseRunCount + task.GetRunCount() + 1
+		info.LastRunAt = time.Now()
+		m.store().UpdateTaskRunInfo(info.ID, info.RunCount, info.LastRunAt, info.LastError)
+
+		failed := runFailed
+		runFailed = false
+
+		// 如果达到最大运行次数，更新状态为已完成（或已禁用）
+		finished := maxRunsReached(&info)
+		var finishErr error
+		if finished {
+			finishErr = m.finishTask(&info)
+		}
+		status, runCount := info.Status, info.RunCount
+		infoMutex.Unlock()
+
+		if !failed {
+			m.emit(info.ID, info.Name, TaskEventSucceeded, storage.TaskStatusRunning, nil)
+		}
+		if !finished {
+			return
+		}
 
-		// 如果达到最大运行次数，更新状态为已完成
-		if taskInfo.MaxRuns > 0 && taskInfo.RunCount >= taskInfo.MaxRuns {
-			taskInfo.Status = storage.TaskStatusCompleted
-			m.storage.SaveTask(taskInfo)
+		// 从任务映射中移除
+		m.mutex.Lock()
+		delete(m.tasks, info.ID)
+		m.mutex.Unlock()
 
-			// 从任务映射中移除
-			m.mutex.Lock()
-			delete(m.tasks, taskInfo.ID)
-			m.mutex.Unlock()
+		// 结束状态保存失败时通过失败事件报告，保存的运行次数已达到上限，重新加载时会再次标记为结束
+		if finishErr != nil {
+			m.emit(info.ID, info.Name, TaskEventFailed, storage.TaskStatusRunning,
+				fmt.Errorf("failed to save status of finished task %d: %w", info.ID, finishErr))
 		}
+
+		// 通知任务结束
+		m.emit(info.ID, info.Name, TaskEventCompleted, status, nil)
+		m.notifyCompletion(TaskCompletionEvent{
+			TaskID:   info.ID,
+			Name:     info.Name,
+			RunCount: runCount,
+			Reason:   CompletionReasonMaxRuns,
+			Disabled: m.autoDisableOnFinish,
+			Time:     time.Now(),
+		})
 	}))
 
 	// 创建任务
-	return scheduler.NewTask(options...), nil
+	task = scheduler.NewTask(options...)
+	return task, nil
+}
+
+// maxRunsReached 检查任务保存的运行次数是否已达到最大运行次数
+func maxRunsReached(taskInfo *storage.TaskInfo) bool {
+	return taskInfo.MaxRuns > 0 && taskInfo.RunCount >= taskInfo.MaxRuns
+}
+
+// finishTask 将已达到最大运行次数的任务标记为已完成（或已禁用），不再调度执行
+func (m *TaskManager) finishTask(taskInfo *storage.TaskInfo) error {
+	taskInfo.Status = storage.TaskStatusCompleted
+	if m.autoDisableOnFinish {
+		taskInfo.Status = storage.TaskStatusDisabled
+	}
+	return m.store().SaveTask(taskInfo)
+}
+
+// notifyCompletion 调用任务结束回调
+func (m *TaskManager) notifyCompletion(event TaskCompletionEvent) {
+	if m.onCompletion != nil {
+		m.onCompletion(event)
+	}
 }
 
 // GetTaskStatus 获取任务状态
 func (m *TaskManager) GetTaskStatus(id int64) (storage.TaskStatus, error) {
-	taskInfo, err := m.storage.GetTask(id)
+	taskInfo, err := m.store().GetTask(id)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,720 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UserLeeZJ/shell-task/lua"
+	"github.com/UserLeeZJ/shell-task/scheduler"
+	"github.com/UserLeeZJ/shell-task/storage"
+)
+
+// newTestManager 创建使用临时数据库的任务管理器
+func newTestManager(t *testing.T, opts ...ManagerOption) (*TaskManager, *storage.SQLiteStorage) {
+	t.Helper()
+
+	dir := t.TempDir()
+	s, err := storage.NewSQLiteStorage(filepath.Join(dir, "tasks.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	m := NewTaskManager(s, lua.NewExecutor(filepath.Join(dir, "scripts")), opts...)
+	if err := m.Start(); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	t.Cleanup(m.Stop)
+
+	return m, s
+}
+
+// saveLuaTask 保存一个 Lua 任务并返回其ID
+func saveLuaTask(t *testing.T, s *storage.SQLiteStorage, name, script string, interval int64, maxRuns int) int64 {
+	t.Helper()
+
+	task := &storage.TaskInfo{
+		Name:     name,
+		Type:     storage.TaskTypeLua,
+		Content:  script,
+		Status:   storage.TaskStatusIdle,
+		Interval: interval,
+		MaxRuns:  maxRuns,
+	}
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	return task.ID
+}
+
+// TestCompletionEventOnMaxRuns 测试任务达到最大运行次数时触发结束事件并自动禁用
+func TestCompletionEventOnMaxRuns(t *testing.T) {
+	var mu sync.Mutex
+	var events []TaskCompletionEvent
+	received := make(chan struct{}, 1)
+
+	m, s := newTestManager(t,
+		WithCompletionHandler(func(event TaskCompletionEvent) {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+			received <- struct{}{}
+		}),
+		WithAutoDisable(true),
+	)
+
+	id := saveLuaTask(t, s, "max-runs", "local x = 1", 1, 2)
+	if err := m.StartTask(id); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for completion event")
+	}
+
+	// 等待一段时间，确认事件只触发一次
+	time.Sleep(1500 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 completion event, got %d", len(events))
+	}
+
+	event := events[0]
+	if event.TaskID != id || event.RunCount != 2 || event.Reason != CompletionReasonMaxRuns || !event.Disabled {
+		t.Errorf("Unexpected completion event: %+v", event)
+	}
+
+	status, err := m.GetTaskStatus(id)
+	if err != nil {
+		t.Fatalf("Failed to get task status: %v", err)
+	}
+	if status != storage.TaskStatusDisabled {
+		t.Errorf("Expected task status to be %s, got %s", storage.TaskStatusDisabled, status)
+	}
+
+	if m.IsTaskRunning(id) {
+		t.Error("Expected task to be removed from running tasks")
+	}
+}
+
+// finishFailingStorage 在保存结束状态时返回错误的存储
+type finishFailingStorage struct {
+	storage.Storage
+}
+
+func (s finishFailingStorage) SaveTask(task *storage.TaskInfo) error {
+	if task.Status == storage.TaskStatusCompleted || task.Status == storage.TaskStatusDisabled {
+		return errors.New("disk full")
+	}
+	return s.Storage.SaveTask(task)
+}
+
+// TestFinishTaskSaveFailure 测试结束状态保存失败时发送失败事件，任务仍然结束
+func TestFinishTaskSaveFailure(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.NewSQLiteStorage(filepath.Join(dir, "tasks.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	m := NewTaskManager(finishFailingStorage{s}, lua.NewExecutor(filepath.Join(dir, "scripts")))
+	if err := m.Start(); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	defer m.Stop()
+
+	events := m.Subscribe()
+	id := saveLuaTask(t, s, "finish-fails", "local x = 1", 0, 1)
+	if err := m.StartTask(id); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	var failure error
+	timeout := time.After(3 * time.Second)
+	for done := false; !done; {
+		select {
+		case event := <-events:
+			switch event.Type {
+			case TaskEventFailed:
+				failure = event.Err
+			case TaskEventCompleted:
+				done = true
+			}
+		case <-timeout:
+			t.Fatal("Timeout waiting for completion event")
+		}
+	}
+
+	if failure == nil || !strings.Contains(failure.Error(), "disk full") {
+		t.Errorf("Expected a failed event carrying the save error, got %v", failure)
+	}
+	if m.IsTaskRunning(id) {
+		t.Error("Expected finished task to be removed from the manager")
+	}
+}
+
+// TestReloadPartiallyRunTask 测试重新加载已运行过部分次数的任务时只执行剩余次数，结束事件只触发一次
+func TestReloadPartiallyRunTask(t *testing.T) {
+	var mu sync.Mutex
+	var events []TaskCompletionEvent
+
+	m, s := newTestManager(t, WithCompletionHandler(func(event TaskCompletionEvent) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	}))
+
+	// 模拟重启前已运行 2 次、仍处于运行中的任务
+	partial := &storage.TaskInfo{
+		Name:     "partial",
+		Type:     storage.TaskTypeLua,
+		Content:  "local x = 1",
+		Status:   storage.TaskStatusRunning,
+		Interval: 1,
+		MaxRuns:  3,
+		RunCount: 2,
+	}
+	// 模拟已达到最大运行次数但状态未来得及更新的任务
+	reached := &storage.TaskInfo{
+		Name:     "reached",
+		Type:     storage.TaskTypeLua,
+		Content:  "local x = 1",
+		Status:   storage.TaskStatusRunning,
+		Interval: 1,
+		MaxRuns:  3,
+		RunCount: 3,
+	}
+	for _, info := range []*storage.TaskInfo{partial, reached} {
+		if err := s.SaveTask(info); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	if err := m.LoadAllTasks(); err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+	if m.IsTaskRunning(reached.ID) {
+		t.Error("Expected task that reached its max runs not to be started")
+	}
+	if info, _ := s.GetTask(reached.ID); info.Status != storage.TaskStatusCompleted || info.RunCount != 3 {
+		t.Errorf("Expected finished task to be completed with 3 runs, got status %s and run count %d",
+			info.Status, info.RunCount)
+	}
+	if err := m.StartTask(reached.ID); err == nil {
+		t.Error("Expected starting a task that reached its max runs to fail")
+	}
+
+	m.mutex.RLock()
+	task := m.tasks[partial.ID]
+	m.mutex.RUnlock()
+	if task == nil {
+		t.Fatal("Expected partially run task to be started")
+	}
+
+	select {
+	case <-task.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for task to finish")
+	}
+
+	// 等待一个重复间隔，确认任务不会继续执行
+	time.Sleep(1500 * time.Millisecond)
+
+	if got := task.GetRunCount(); got != 1 {
+		t.Errorf("Expected reloaded task to run once, got %d", got)
+	}
+
+	stored, err := s.GetTask(partial.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if stored.RunCount != 3 || stored.Status != storage.TaskStatusCompleted {
+		t.Errorf("Expected stored run count 3 and status %s, got %d and %s",
+			storage.TaskStatusCompleted, stored.RunCount, stored.Status)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 || events[0].TaskID != partial.ID || events[0].RunCount != 3 {
+		t.Errorf("Expected a single completion event with run count 3, got %+v", events)
+	}
+}
+
+// TestStartTaskSubmitFailure 测试提交到工作池失败时返回错误并撤销启动
+func TestStartTaskSubmitFailure(t *testing.T) {
+	m, s := newTestManager(t)
+	id := saveLuaTask(t, s, "rejected", "local x = 1", 0, 0)
+
+	// 工作池停止后提交会失败
+	m.workerPool.Stop()
+
+	err := m.StartTask(id)
+	if !errors.Is(err, scheduler.ErrPoolStopped) {
+		t.Fatalf("Expected ErrPoolStopped, got %v", err)
+	}
+	if m.IsTaskRunning(id) {
+		t.Error("Expected task not to be tracked as running after a failed submit")
+	}
+	if info, _ := s.GetTask(id); info.Status != storage.TaskStatusIdle {
+		t.Errorf("Expected stored status to stay %s, got %s", storage.TaskStatusIdle, info.Status)
+	}
+}
+
+// TestMigrateStorage 测试运行时迁移到新存储
+func TestMigrateStorage(t *testing.T) {
+	m, src := newTestManager(t)
+
+	ids := []int64{
+		saveLuaTask(t, src, "first", "local x = 1", 0, 0),
+		saveLuaTask(t, src, "second", "local x = 2", 0, 0),
+		saveLuaTask(t, src, "periodic", "local x = 3", 1, 0),
+	}
+
+	// 迁移时有任务正在运行
+	if err := m.StartTask(ids[2]); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	dest, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "dest.db"))
+	if err != nil {
+		t.Fatalf("Failed to create destination storage: %v", err)
+	}
+	defer dest.Close()
+
+	if err := m.MigrateStorage(dest); err != nil {
+		t.Fatalf("Failed to migrate storage: %v", err)
+	}
+
+	tasks, err := dest.ListTasks()
+	if err != nil {
+		t.Fatalf("Failed to list destination tasks: %v", err)
+	}
+	if len(tasks) != len(ids) {
+		t.Fatalf("Expected %d tasks in destination, got %d", len(ids), len(tasks))
+	}
+	for i, task := range tasks {
+		if task.ID != ids[i] {
+			t.Errorf("Expected task %d to keep ID %d, got %d", i, ids[i], task.ID)
+		}
+	}
+
+	// 管理器应使用新存储
+	if err := dest.UpdateTaskStatus(ids[0], storage.TaskStatusPaused); err != nil {
+		t.Fatalf("Failed to update task status: %v", err)
+	}
+	status, err := m.GetTaskStatus(ids[0])
+	if err != nil {
+		t.Fatalf("Failed to get task status: %v", err)
+	}
+	if status != storage.TaskStatusPaused {
+		t.Errorf("Expected status from destination storage, got %s", status)
+	}
+
+	// 正在运行的任务的运行信息应写入新存储
+	time.Sleep(1500 * time.Millisecond)
+	before, _ := src.GetTask(ids[2])
+	after, err := dest.GetTask(ids[2])
+	if err != nil {
+		t.Fatalf("Failed to get migrated task: %v", err)
+	}
+	if after.RunCount <= before.RunCount {
+		t.Errorf("Expected run count in destination (%d) to exceed source (%d)", after.RunCount, before.RunCount)
+	}
+}
+
+// TestStartTaskInvalidCron 测试 cron 表达式无效时拒绝启动任务
+func TestStartTaskInvalidCron(t *testing.T) {
+	m, s := newTestManager(t)
+
+	task := &storage.TaskInfo{
+		Name:     "bad-cron",
+		Type:     storage.TaskTypeLua,
+		Content:  "local x = 1",
+		Status:   storage.TaskStatusIdle,
+		Priority: 9,
+		Cron:     "61 * * * *",
+	}
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	if err := m.StartTask(task.ID); err == nil {
+		t.Fatal("Expected error for invalid cron expression")
+	}
+	if m.IsTaskRunning(task.ID) {
+		t.Error("Expected task not to be running")
+	}
+}
+
+// TestPauseResumeTask 测试暂停和恢复任务
+func TestPauseResumeTask(t *testing.T) {
+	m, s := newTestManager(t)
+
+	id := saveLuaTask(t, s, "pausable", "local x = 1", 1, 0)
+	if err := m.StartTask(id); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	runCount := func() int {
+		info, err := s.GetTask(id)
+		if err != nil {
+			t.Fatalf("Failed to get task: %v", err)
+		}
+		return info.RunCount
+	}
+
+	// 等待第一次执行完成后暂停
+	deadline := time.Now().Add(3 * time.Second)
+	for runCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timeout waiting for first run")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err := m.PauseTask(id); err != nil {
+		t.Fatalf("Failed to pause task: %v", err)
+	}
+
+	if status, _ := m.GetTaskStatus(id); status != storage.TaskStatusPaused {
+		t.Errorf("Expected status %s, got %s", storage.TaskStatusPaused, status)
+	}
+	if !m.IsTaskRunning(id) {
+		t.Error("Expected paused task to stay in the running tasks")
+	}
+	if err := m.PauseTask(id); err == nil {
+		t.Error("Expected error pausing an already paused task")
+	}
+
+	// 暂停期间不再执行
+	paused := runCount()
+	time.Sleep(2500 * time.Millisecond)
+	if n := runCount(); n != paused {
+		t.Errorf("Expected no runs while paused, run count went from %d to %d", paused, n)
+	}
+
+	if err := m.ResumeTask(id); err != nil {
+		t.Fatalf("Failed to resume task: %v", err)
+	}
+	if status, _ := m.GetTaskStatus(id); status != storage.TaskStatusRunning {
+		t.Errorf("Expected status %s, got %s", storage.TaskStatusRunning, status)
+	}
+	if err := m.ResumeTask(id); err == nil {
+		t.Error("Expected error resuming a task that is not paused")
+	}
+
+	deadline = time.Now().Add(3 * time.Second)
+	for runCount() == paused {
+		if time.Now().After(deadline) {
+			t.Fatal("Timeout waiting for task to run after resume")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// TestWatchReloadsTasks 测试热加载启动新任务并停止已取消的任务
+func TestWatchReloadsTasks(t *testing.T) {
+	m, s := newTestManager(t)
+	m.Watch(50 * time.Millisecond)
+
+	// 其他进程创建了一个运行中的任务
+	id := saveLuaTask(t, s, "external", "local x = 1", 60, 0)
+	if err := s.UpdateTaskStatus(id, storage.TaskStatusRunning); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !m.IsTaskRunning(id) {
+		if time.Now().After(deadline) {
+			t.Fatal("Timeout waiting for watcher to start the new task")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 重复加载不会重复启动任务
+	if err := m.LoadAllTasks(); err != nil {
+		t.Errorf("Expected reload of an already running task to succeed, got %v", err)
+	}
+
+	// 其他进程取消了该任务
+	if err := s.UpdateTaskStatus(id, storage.TaskStatusCancelled); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+
+	deadline = time.Now().Add(2 * time.Second)
+	for m.IsTaskRunning(id) {
+		if time.Now().After(deadline) {
+			t.Fatal("Timeout waiting for watcher to stop the cancelled task")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestSubscribeTaskEvents 测试订阅者收到任务生命周期事件
+func TestSubscribeTaskEvents(t *testing.T) {
+	m, s := newTestManager(t)
+
+	events := m.Subscribe()
+	other := m.Subscribe()
+
+	id := saveLuaTask(t, s, "failing", `error("boom")`, 0, 0)
+	if err := m.StartTask(id); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	var received []TaskEventType
+	timeout := time.After(3 * time.Second)
+	for len(received) < 2 {
+		select {
+		case event := <-events:
+			if event.TaskID != id || event.Name != "failing" {
+				t.Errorf("Unexpected event: %+v", event)
+			}
+			received = append(received, event.Type)
+			if event.Type == TaskEventFailed {
+				if event.Err == nil || event.Status != storage.TaskStatusFailed {
+					t.Errorf("Expected failed event with an error, got %+v", event)
+				}
+			}
+		case <-timeout:
+			t.Fatalf("Timeout waiting for events, received %v", received)
+		}
+	}
+
+	if received[0] != TaskEventStarted || received[1] != TaskEventFailed {
+		t.Errorf("Expected [started failed] events, got %v", received)
+	}
+
+	// 每个订阅者都收到事件
+	select {
+	case event := <-other:
+		if event.Type != TaskEventStarted {
+			t.Errorf("Expected second subscriber to receive the started event, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected second subscriber to receive events")
+	}
+
+	m.Unsubscribe(other)
+	for range other {
+		// 排空剩余事件，通道关闭后退出
+	}
+}
+
+// TestDeleteRunningTask 测试删除正在运行的任务时先停止任务再从存储中删除
+func TestDeleteRunningTask(t *testing.T) {
+	m, s := newTestManager(t)
+
+	id := saveLuaTask(t, s, "deletable", "local x = 1", 1, 0)
+	if err := m.StartTask(id); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	m.mutex.RLock()
+	task := m.tasks[id]
+	m.mutex.RUnlock()
+
+	if err := m.DeleteTask(id); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	if m.IsTaskRunning(id) {
+		t.Error("Expected deleted task to be removed from the running tasks")
+	}
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Error("Expected deleted task to be stopped")
+	}
+	if _, err := s.GetTask(id); err == nil {
+		t.Error("Expected deleted task to be removed from storage")
+	}
+}
+
+// TestRunCountMatchesScheduler 测试保存的运行次数与调度器的运行次数一致，重试不会额外计数
+func TestRunCountMatchesScheduler(t *testing.T) {
+	m, s := newTestManager(t)
+
+	info := &storage.TaskInfo{
+		Name:       "retried",
+		Type:       storage.TaskTypeLua,
+		Content:    `error("boom")`,
+		Status:     storage.TaskStatusIdle,
+		Interval:   1,
+		MaxRuns:    3,
+		RetryTimes: 2,
+	}
+	if err := s.SaveTask(info); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	if err := m.StartTask(info.ID); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	m.mutex.RLock()
+	task := m.tasks[info.ID]
+	m.mutex.RUnlock()
+
+	select {
+	case <-task.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for task to finish")
+	}
+
+	stored, err := s.GetTask(info.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if stored.RunCount != 3 || stored.RunCount != task.GetRunCount() {
+		t.Errorf("Expected stored run count 3 matching scheduler, got stored=%d scheduler=%d",
+			stored.RunCount, task.GetRunCount())
+	}
+}
+
+// TestConcurrentTaskInfoUpdates 在多个周期任务运行时并发更新状态和运行信息，配合 -race 检查数据竞争
+func TestConcurrentTaskInfoUpdates(t *testing.T) {
+	m, s := newTestManager(t)
+
+	var ids []int64
+	for i := 0; i < 4; i++ {
+		id := saveLuaTask(t, s, fmt.Sprintf("stress-%d", i), `if math.random() < 0.5 then error("boom") end`, 1, 0)
+		if err := m.StartTask(id); err != nil {
+			t.Fatalf("Failed to start task: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				m.PauseTask(id)
+				m.GetTaskStatus(id)
+				m.ResumeTask(id)
+				m.GetRunningTasks()
+				m.LoadAllTasks()
+				time.Sleep(time.Millisecond)
+			}
+		}(id)
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	for _, id := range ids {
+		info, err := s.GetTask(id)
+		if err != nil {
+			t.Fatalf("Failed to get task: %v", err)
+		}
+		if info.RunCount == 0 {
+			t.Errorf("Expected task %d to have run", id)
+		}
+		if err := m.StopTask(id); err != nil {
+			t.Errorf("Failed to stop task %d: %v", id, err)
+		}
+	}
+}
+
+// TestValidateTask 测试校验任务而不执行
+func TestValidateTask(t *testing.T) {
+	m, s := newTestManager(t)
+
+	valid := saveLuaTask(t, s, "valid", "local x = 1 + 1", 0, 0)
+	if err := m.ValidateTask(valid); err != nil {
+		t.Errorf("Expected valid task to pass, got %v", err)
+	}
+	if m.IsTaskRunning(valid) {
+		t.Error("Expected validation not to start the task")
+	}
+	if info, _ := s.GetTask(valid); info.RunCount != 0 || info.Status != storage.TaskStatusIdle {
+		t.Errorf("Expected task not to run, got status %s and run count %d", info.Status, info.RunCount)
+	}
+
+	badScript := saveLuaTask(t, s, "bad-script", "local x = ", 0, 0)
+	err := m.ValidateTask(badScript)
+	if err == nil || !strings.Contains(err.Error(), "lua syntax error") {
+		t.Errorf("Expected lua syntax error, got %v", err)
+	}
+
+	unsupported := &storage.TaskInfo{
+		Name:    "python",
+		Type:    storage.TaskType("python"),
+		Content: "print(1)",
+		Status:  storage.TaskStatusIdle,
+	}
+	if err := s.SaveTask(unsupported); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	err = m.ValidateTask(unsupported.ID)
+	if err == nil || !strings.Contains(err.Error(), "unsupported task type") {
+		t.Errorf("Expected unsupported type error, got %v", err)
+	}
+}
+
+// TestTaskArgs 测试 Lua 和 Shell 任务读取任务参数
+func TestTaskArgs(t *testing.T) {
+	m, s := newTestManager(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		typ     storage.TaskType
+		content string
+	}{
+		{"lua", storage.TaskTypeLua, `local f = assert(io.open(arg[2], "w")) f:write(arg[1]) f:close()`},
+		{"shell", storage.TaskTypeShell, `printf '%s' "$1" > "$2"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(dir, tt.name+".txt")
+			task := &storage.TaskInfo{
+				Name:    "args-" + tt.name,
+				Type:    tt.typ,
+				Content: tt.content,
+				Status:  storage.TaskStatusIdle,
+				MaxRuns: 1,
+				Args:    []string{"hello " + tt.name, output},
+			}
+			if err := s.SaveTask(task); err != nil {
+				t.Fatalf("Failed to save task: %v", err)
+			}
+			if err := m.StartTask(task.ID); err != nil {
+				t.Fatalf("Failed to start task: %v", err)
+			}
+
+			deadline := time.Now().Add(2 * time.Second)
+			for {
+				data, err := os.ReadFile(output)
+				if err == nil && string(data) == "hello "+tt.name {
+					break
+				}
+				if time.Now().After(deadline) {
+					info, _ := s.GetTask(task.ID)
+					t.Fatalf("Expected task to write its argument, got %q (read error: %v, task error: %q)", data, err, info.LastError)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
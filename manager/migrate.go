@@ -0,0 +1,78 @@
+// manager/migrate.go
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/UserLeeZJ/shell-task/storage"
+)
+
+// MigrateStorage 将所有任务复制到新存储并切换为使用新存储
+// 复制期间继续使用旧存储提供服务；复制完成后在短暂加锁期间同步复制过程中发生的变更，
+// 然后原子地切换存储。任务ID在新存储中保持不变，正在运行的任务不受影响
+func (m *TaskManager) MigrateStorage(dest storage.Storage) error {
+	if dest == nil {
+		return errors.New("destination storage is nil")
+	}
+
+	src := m.store()
+	if src == dest {
+		return nil
+	}
+
+	// 第一轮：不加锁复制所有任务
+	tasks, err := src.ListTasks()
+	if err != nil {
+		return fmt.Errorf("failed to list source tasks: %w", err)
+	}
+
+	copied := make(map[int64]time.Time, len(tasks))
+	for _, task := range tasks {
+		if err := copyTask(dest, task); err != nil {
+			return err
+		}
+		copied[task.ID] = task.UpdatedAt
+	}
+
+	// 第二轮：加锁同步复制期间的变更后切换存储
+	m.storageMu.Lock()
+	defer m.storageMu.Unlock()
+
+	tasks, err = src.ListTasks()
+	if err != nil {
+		return fmt.Errorf("failed to list source tasks: %w", err)
+	}
+
+	for _, task := range tasks {
+		updatedAt, exists := copied[task.ID]
+		delete(copied, task.ID)
+		if exists && updatedAt.Equal(task.UpdatedAt) {
+			continue
+		}
+		if err := copyTask(dest, task); err != nil {
+			return err
+		}
+	}
+
+	// 复制期间被删除的任务
+	for id := range copied {
+		if err := dest.DeleteTask(id); err != nil {
+			return fmt.Errorf("failed to delete task %d from destination: %w", id, err)
+		}
+	}
+
+	m.storage = dest
+	return nil
+}
+
+// copyTask 按原ID将任务写入目标存储
+func copyTask(dest storage.Storage, task *storage.TaskInfo) error {
+	// 复制一份，避免保存时修改源任务的时间字段
+	info := *task
+	if err := dest.SaveTask(&info); err != nil {
+		return fmt.Errorf("failed to copy task %d (%s): %w", task.ID, task.Name, err)
+	}
+	return nil
+}
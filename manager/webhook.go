@@ -0,0 +1,122 @@
+// manager/webhook.go
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/UserLeeZJ/shell-task/storage"
+)
+
+// DefaultWebhookTimeout 是单次 webhook 请求的默认超时时间
+const DefaultWebhookTimeout = 10 * time.Second
+
+// webhook 表示一个 webhook 通知配置
+type webhook struct {
+	url      string
+	statuses map[storage.TaskStatus]bool // 需要通知的任务状态，为空表示通知所有事件
+	client   *http.Client
+}
+
+// webhookPayload 是 webhook 请求的 JSON 内容
+type webhookPayload struct {
+	TaskID    int64              `json:"task_id"`
+	Name      string             `json:"name"`
+	Event     TaskEventType      `json:"event"`
+	Status    storage.TaskStatus `json:"status"`
+	Error     string             `json:"error,omitempty"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+// WithWebhook 设置任务事件的 webhook 通知
+// 任务事件的状态属于 statuses 时，向 url 发送包含任务ID、名称、状态、错误和时间的 JSON POST 请求；
+// 未指定 statuses 时通知所有事件。请求在独立协程中发送，不会阻塞任务执行，
+// 每次请求的超时时间为 DefaultWebhookTimeout，失败时重试一次，重试仍失败时调用 WithWebhookErrorHandler 设置的回调
+func WithWebhook(url string, statuses ...storage.TaskStatus) ManagerOption {
+	return func(m *TaskManager) {
+		hook := &webhook{
+			url:      url,
+			statuses: make(map[storage.TaskStatus]bool, len(statuses)),
+			client:   &http.Client{Timeout: DefaultWebhookTimeout},
+		}
+		for _, status := range statuses {
+			hook.statuses[status] = true
+		}
+		m.webhooks = append(m.webhooks, hook)
+	}
+}
+
+// WithWebhookErrorHandler 设置 webhook 通知重试后仍发送失败时的回调函数
+// 回调在发送通知的协程中调用，参数为 webhook 地址、通知的事件和最后一次发送的错误
+func WithWebhookErrorHandler(handler func(url string, event TaskEvent, err error)) ManagerOption {
+	return func(m *TaskManager) {
+		m.onWebhookError = handler
+	}
+}
+
+// startWebhooks 为每个 webhook 订阅任务事件并在后台发送通知
+func (m *TaskManager) startWebhooks() {
+	for _, hook := range m.webhooks {
+		events := m.Subscribe()
+		go func(hook *webhook) {
+			for event := range events {
+				if hook.matches(event) {
+					go m.deliverWebhook(hook, event)
+				}
+			}
+		}(hook)
+	}
+}
+
+// deliverWebhook 发送事件通知，失败时调用 webhook 错误回调
+func (m *TaskManager) deliverWebhook(hook *webhook, event TaskEvent) {
+	if err := hook.deliver(event); err != nil && m.onWebhookError != nil {
+		m.onWebhookError(hook.url, event, err)
+	}
+}
+
+// matches 检查事件是否需要通知
+func (h *webhook) matches(event TaskEvent) bool {
+	return len(h.statuses) == 0 || h.statuses[event.Status]
+}
+
+// deliver 发送事件通知，失败时重试一次
+func (h *webhook) deliver(event TaskEvent) error {
+	payload := webhookPayload{
+		TaskID:    event.TaskID,
+		Name:      event.Name,
+		Event:     event.Type,
+		Status:    event.Status,
+		Timestamp: event.Time,
+	}
+	if event.Err != nil {
+		payload.Error = event.Err.Error()
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if err = h.post(body); err != nil {
+		err = h.post(body)
+	}
+	return err
+}
+
+// post 发送一次 POST 请求，非 2xx 响应视为失败
+func (h *webhook) post(body []byte) error {
+	resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", h.url, resp.StatusCode)
+	}
+	return nil
+}
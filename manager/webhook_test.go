@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/UserLeeZJ/shell-task/storage"
+)
+
+// TestWebhookOnCompletion 测试任务结束时发送 webhook 通知，失败时重试一次
+func TestWebhookOnCompletion(t *testing.T) {
+	var requests int32
+	payloads := make(chan webhookPayload, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+
+		// 第一次请求失败，验证重试
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var payload webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+		payloads <- payload
+	}))
+	defer server.Close()
+
+	m, s := newTestManager(t, WithWebhook(server.URL, storage.TaskStatusCompleted))
+
+	id := saveLuaTask(t, s, "notify", "local x = 1", 1, 1)
+	if err := m.StartTask(id); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	select {
+	case payload := <-payloads:
+		if payload.TaskID != id || payload.Name != "notify" || payload.Event != TaskEventCompleted ||
+			payload.Status != storage.TaskStatusCompleted || payload.Error != "" || payload.Timestamp.IsZero() {
+			t.Errorf("Unexpected payload: %+v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for webhook")
+	}
+
+	// 只通知匹配的事件
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("Expected 2 requests (one retry), got %d", n)
+	}
+}
+
+// TestWebhookErrorHandler 测试 webhook 重试后仍失败时调用错误回调
+func TestWebhookErrorHandler(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	type failure struct {
+		url   string
+		event TaskEvent
+		err   error
+	}
+	failures := make(chan failure, 4)
+
+	m, s := newTestManager(t,
+		WithWebhook(server.URL, storage.TaskStatusCompleted),
+		WithWebhookErrorHandler(func(url string, event TaskEvent, err error) {
+			failures <- failure{url, event, err}
+		}),
+	)
+
+	id := saveLuaTask(t, s, "unreachable", "local x = 1", 1, 1)
+	if err := m.StartTask(id); err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	select {
+	case f := <-failures:
+		if f.url != server.URL || f.event.TaskID != id || f.event.Type != TaskEventCompleted {
+			t.Errorf("Unexpected failure: %+v", f)
+		}
+		if f.err == nil || !strings.Contains(f.err.Error(), "status 503") {
+			t.Errorf("Expected error to mention the response status, got %v", f.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for webhook error")
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("Expected 2 requests (one retry) before reporting the error, got %d", n)
+	}
+}
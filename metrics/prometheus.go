@@ -0,0 +1,68 @@
+// metrics/prometheus.go
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/UserLeeZJ/shell-task/scheduler"
+)
+
+// contentType 是 Prometheus 文本格式的内容类型
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PoolCollector 以 Prometheus 文本格式导出工作池的运行状况
+// 不依赖 Prometheus 客户端库，可以直接作为 HTTP 处理器供 Prometheus 抓取
+type PoolCollector struct {
+	pool      *scheduler.WorkerPool
+	namespace string
+}
+
+// NewPoolCollector 创建工作池指标导出器，指标名称以 namespace 为前缀，为空时使用 shelltask
+func NewPoolCollector(pool *scheduler.WorkerPool, namespace string) *PoolCollector {
+	if namespace == "" {
+		namespace = "shelltask"
+	}
+	return &PoolCollector{pool: pool, namespace: namespace}
+}
+
+// metric 描述一个导出的指标
+type metric struct {
+	name  string
+	help  string
+	kind  string // gauge 或 counter
+	value float64
+}
+
+// metrics 根据工作池快照生成指标列表
+func (c *PoolCollector) metrics() []metric {
+	m := c.pool.Metrics()
+	return []metric{
+		{"pool_pending_tasks", "Number of tasks waiting to be executed.", "gauge", float64(m.Pending)},
+		{"pool_running_tasks", "Number of tasks currently executing.", "gauge", float64(m.Running)},
+		{"pool_completed_tasks_total", "Total number of tasks completed successfully.", "counter", float64(m.Completed)},
+		{"pool_failed_tasks_total", "Total number of tasks that failed.", "counter", float64(m.Failed)},
+		{"pool_queue_length", "Number of tasks in the priority queue.", "gauge", float64(m.QueueLength)},
+		{"pool_workers", "Number of live worker goroutines.", "gauge", float64(m.Workers)},
+	}
+}
+
+// WriteTo 将指标以 Prometheus 文本格式写入 w
+func (c *PoolCollector) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, m := range c.metrics() {
+		name := c.namespace + "_" + m.name
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, m.help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, m.kind)
+		fmt.Fprintf(&buf, "%s %g\n", name, m.value)
+	}
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP 实现 http.Handler，响应 Prometheus 的抓取请求
+func (c *PoolCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	c.WriteTo(w)
+}
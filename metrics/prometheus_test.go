@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UserLeeZJ/shell-task/scheduler"
+)
+
+// TestPoolCollector 测试以 Prometheus 文本格式导出工作池指标
+func TestPoolCollector(t *testing.T) {
+	pool := scheduler.NewWorkerPool(3, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	done := make(chan struct{})
+	pool.Submit(scheduler.NewTask(
+		scheduler.WithName("Task"),
+		scheduler.WithJob(func(ctx context.Context) error {
+			close(done)
+			return nil
+		}),
+	))
+	<-done
+
+	deadline := time.Now().Add(2 * time.Second)
+	for pool.Metrics().Completed != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec := httptest.NewRecorder()
+	NewPoolCollector(pool, "").ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Unexpected content type: %s", ct)
+	}
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE shelltask_pool_completed_tasks_total counter",
+		"shelltask_pool_completed_tasks_total 1",
+		"shelltask_pool_failed_tasks_total 0",
+		"# TYPE shelltask_pool_workers gauge",
+		"shelltask_pool_workers 3",
+		"shelltask_pool_running_tasks 0",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, body)
+		}
+	}
+}
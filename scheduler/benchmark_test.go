@@ -192,3 +192,107 @@ func BenchmarkConcurrentTasks(b *testing.B) {
 		<-done
 	}
 }
+
+// BenchmarkBucketQueueEnqueue 基准测试分桶队列入队
+func BenchmarkBucketQueueEnqueue(b *testing.B) {
+	q := NewBucketQueue()
+	task := NewTask(
+		WithName("BenchmarkTask"),
+		WithJob(func(ctx context.Context) error {
+			return nil
+		}),
+	)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Enqueue(task)
+	}
+}
+
+// BenchmarkBucketQueueDequeue 基准测试分桶队列出队
+func BenchmarkBucketQueueDequeue(b *testing.B) {
+	q := NewBucketQueue()
+	task := NewTask(
+		WithName("BenchmarkTask"),
+		WithJob(func(ctx context.Context) error {
+			return nil
+		}),
+	)
+	// 预先入队足够多的任务
+	for i := 0; i < b.N+1000; i++ {
+		q.Enqueue(task)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Dequeue()
+	}
+}
+
+// churnTasks 创建不同优先级的任务，用于高并发基准测试
+func churnTasks() []*Task {
+	priorities := []Priority{PriorityLow, PriorityNormal, PriorityHigh}
+	tasks := make([]*Task, len(priorities))
+	for i, priority := range priorities {
+		tasks[i] = NewTask(
+			WithName("BenchmarkTask"),
+			WithJob(func(ctx context.Context) error {
+				return nil
+			}),
+			WithPriority(priority),
+		)
+	}
+	return tasks
+}
+
+// BenchmarkPriorityQueueParallelChurn 基准测试优先级队列在并发入队出队下的表现
+func BenchmarkPriorityQueueParallelChurn(b *testing.B) {
+	pq := NewPriorityQueue()
+	tasks := churnTasks()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			pq.Enqueue(tasks[i%len(tasks)])
+			pq.Dequeue()
+			// 模拟调度协程在队列为空时的轮询
+			pq.Dequeue()
+			i++
+		}
+	})
+}
+
+// BenchmarkBucketQueueParallelChurn 基准测试分桶队列在并发入队出队下的表现
+func BenchmarkBucketQueueParallelChurn(b *testing.B) {
+	q := NewBucketQueue()
+	tasks := churnTasks()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			q.Enqueue(tasks[i%len(tasks)])
+			q.Dequeue()
+			// 模拟调度协程在队列为空时的轮询
+			q.Dequeue()
+			i++
+		}
+	})
+}
+
+// BenchmarkPriorityQueueParallelPollEmpty 基准测试并发轮询空的优先级队列
+func BenchmarkPriorityQueueParallelPollEmpty(b *testing.B) {
+	pq := NewPriorityQueue()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pq.Dequeue()
+		}
+	})
+}
+
+// BenchmarkBucketQueueParallelPollEmpty 基准测试并发轮询空的分桶队列
+func BenchmarkBucketQueueParallelPollEmpty(b *testing.B) {
+	q := NewBucketQueue()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Dequeue()
+		}
+	})
+}
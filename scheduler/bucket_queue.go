@@ -0,0 +1,132 @@
+// scheduler/bucket_queue.go
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// taskBucket 保存同一优先级的任务，按先进先出顺序出队
+type taskBucket struct {
+	tasks []*Task
+	head  int // 队首位置，出队时后移，避免每次出队都移动切片
+	mutex sync.Mutex
+}
+
+// push 将任务添加到桶尾
+func (b *taskBucket) push(task *Task) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.tasks = append(b.tasks, task)
+}
+
+// pop 从桶首取出任务，桶为空时返回 nil
+func (b *taskBucket) pop() *Task {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.head >= len(b.tasks) {
+		return nil
+	}
+
+	task := b.tasks[b.head]
+	b.tasks[b.head] = nil // 避免内存泄漏
+	b.head++
+
+	// 已出队部分超过一半时压缩切片
+	if b.head > len(b.tasks)/2 {
+		n := copy(b.tasks, b.tasks[b.head:])
+		for i := n; i < len(b.tasks); i++ {
+			b.tasks[i] = nil
+		}
+		b.tasks = b.tasks[:n]
+		b.head = 0
+	}
+
+	return task
+}
+
+// BucketQueue 是面向高并发场景的优先级队列
+// 每个优先级对应一个独立加锁的先进先出桶，不同优先级的入队互不竞争；
+// 队列为空时 Dequeue 只读取原子计数，不需要加锁，适合调度协程频繁轮询的场景
+type BucketQueue struct {
+	buckets map[Priority]*taskBucket
+	levels  []Priority   // 已存在的优先级，按从高到低排序
+	mutex   sync.RWMutex // 保护 buckets 和 levels，只在出现新优先级时写入
+	size    int64        // 队列中的任务数量
+}
+
+// NewBucketQueue 创建一个新的分桶优先级队列
+func NewBucketQueue() *BucketQueue {
+	return &BucketQueue{
+		buckets: make(map[Priority]*taskBucket),
+	}
+}
+
+// bucket 获取指定优先级的桶，不存在时创建
+func (q *BucketQueue) bucket(priority Priority) *taskBucket {
+	q.mutex.RLock()
+	b, exists := q.buckets[priority]
+	q.mutex.RUnlock()
+	if exists {
+		return b
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	// 再次检查，可能已被其他协程创建
+	if b, exists := q.buckets[priority]; exists {
+		return b
+	}
+
+	b = &taskBucket{}
+	q.buckets[priority] = b
+
+	// 按从高到低的顺序插入优先级
+	i := sort.Search(len(q.levels), func(i int) bool {
+		return q.levels[i] < priority
+	})
+	q.levels = append(q.levels, 0)
+	copy(q.levels[i+1:], q.levels[i:])
+	q.levels[i] = priority
+
+	return b
+}
+
+// Enqueue 将任务添加到队列
+func (q *BucketQueue) Enqueue(task *Task) {
+	q.bucket(task.priority).push(task)
+	atomic.AddInt64(&q.size, 1)
+}
+
+// Dequeue 从队列中取出最高优先级的任务，同一优先级按入队顺序出队
+func (q *BucketQueue) Dequeue() *Task {
+	// 快速路径：队列为空时无需加锁
+	if atomic.LoadInt64(&q.size) == 0 {
+		return nil
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, priority := range q.levels {
+		if task := q.buckets[priority].pop(); task != nil {
+			atomic.AddInt64(&q.size, -1)
+			return task
+		}
+	}
+
+	return nil
+}
+
+// Size 返回队列中的任务数量
+func (q *BucketQueue) Size() int {
+	return int(atomic.LoadInt64(&q.size))
+}
+
+// IsEmpty 检查队列是否为空
+func (q *BucketQueue) IsEmpty() bool {
+	return q.Size() == 0
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// newPriorityTask 创建指定名称和优先级的任务
+func newPriorityTask(name string, priority Priority) *Task {
+	return NewTask(
+		WithName(name),
+		WithJob(func(ctx context.Context) error {
+			return nil
+		}),
+		WithPriority(priority),
+	)
+}
+
+// TestBucketQueueOrdering 测试分桶队列的优先级顺序
+func TestBucketQueueOrdering(t *testing.T) {
+	q := NewBucketQueue()
+
+	if q.Dequeue() != nil {
+		t.Error("Expected nil from empty queue")
+	}
+
+	q.Enqueue(newPriorityTask("low-1", PriorityLow))
+	q.Enqueue(newPriorityTask("normal-1", PriorityNormal))
+	q.Enqueue(newPriorityTask("high-1", PriorityHigh))
+	q.Enqueue(newPriorityTask("normal-2", PriorityNormal))
+	q.Enqueue(newPriorityTask("custom", Priority(7)))
+	q.Enqueue(newPriorityTask("high-2", PriorityHigh))
+	q.Enqueue(newPriorityTask("low-2", PriorityLow))
+
+	if q.Size() != 7 {
+		t.Errorf("Expected queue size to be 7, got %d", q.Size())
+	}
+
+	expected := []string{"high-1", "high-2", "custom", "normal-1", "normal-2", "low-1", "low-2"}
+	for i, name := range expected {
+		task := q.Dequeue()
+		if task == nil {
+			t.Fatalf("Expected task %s at position %d, got nil", name, i)
+		}
+		if task.name != name {
+			t.Errorf("Expected task %s at position %d, got %s", name, i, task.name)
+		}
+	}
+
+	if !q.IsEmpty() {
+		t.Error("Expected queue to be empty after dequeuing all tasks")
+	}
+}
+
+// TestBucketQueueConcurrent 测试分桶队列的并发安全性
+func TestBucketQueueConcurrent(t *testing.T) {
+	q := NewBucketQueue()
+	priorities := []Priority{PriorityLow, PriorityNormal, PriorityHigh}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				q.Enqueue(newPriorityTask("task", priorities[(i+j)%len(priorities)]))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if q.Size() != 800 {
+		t.Fatalf("Expected queue size to be 800, got %d", q.Size())
+	}
+
+	// 出队顺序中优先级不应上升
+	last := PriorityHigh
+	for task := q.Dequeue(); task != nil; task = q.Dequeue() {
+		if task.priority > last {
+			t.Fatalf("Priority increased from %d to %d", last, task.priority)
+		}
+		last = task.priority
+	}
+}
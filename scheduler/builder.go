@@ -51,10 +51,8 @@ func ChainTasks(tasks ...*Task) []*Task {
 					nextTask.taskContext = NewTaskContext()
 				}
 
-				// 复制所有上下文值
-				for k, v := range currentTask.taskContext.GetAll() {
-					nextTask.taskContext.Set(k, v)
-				}
+				// 复制所有上下文值，覆盖下一个任务中的同名值
+				nextTask.taskContext.Merge(currentTask.taskContext, true)
 			}
 		}
 	}
@@ -407,6 +405,14 @@ func RunAfter(task *Task, dependencies ...*Task) *Task {
 	return task
 }
 
+// RunAfterDelay 设置任务依赖关系，所有依赖完成后再等待 delay 才启动任务
+// 等待期间调用任务的 Stop 会取消启动
+func RunAfterDelay(task *Task, delay time.Duration, dependencies ...*Task) *Task {
+	task.dependencyDelay = delay
+	task.DependsOn(dependencies...)
+	return task
+}
+
 // Sequence 创建一个任务序列，每个任务依赖于前一个任务
 func Sequence(tasks ...*Task) []*Task {
 	if len(tasks) <= 1 {
@@ -421,7 +427,35 @@ func Sequence(tasks ...*Task) []*Task {
 	return tasks
 }
 
+// SequenceStopOnError 创建一个任务序列，每个任务依赖于前一个任务，任务以错误结束时停止整个序列
+// 与 Sequence 不同，某个任务失败、被取消或执行出错后结束时，其后所有尚未结束的任务都会被停止
+// （进入已取消状态），而不是一直等待永远不会完成的依赖
+func SequenceStopOnError(tasks ...*Task) []*Task {
+	Sequence(tasks...)
+
+	for i := 0; i < len(tasks)-1; i++ {
+		task := tasks[i]
+		rest := tasks[i+1:]
+		originalCallback := task.onStateChange
+		task.onStateChange = func(oldState, newState TaskState) {
+			// 在通知依赖它的任务之前停止后续任务，避免它们被启动
+			if isTerminalState(newState) && taskEndError(task) != nil {
+				for _, next := range rest {
+					next.Stop()
+				}
+			}
+			if originalCallback != nil {
+				originalCallback(oldState, newState)
+			}
+		}
+	}
+
+	return tasks
+}
+
 // Parallel 创建一个并行任务组，返回一个汇聚任务
+// 汇聚任务在所有并行任务结束（无论成功与否）后执行，有任务以错误结束时，
+// 汇聚任务以列出这些任务及其错误的聚合错误结束，可以通过 GetLastError 获取
 func Parallel(name string, tasks ...*Task) *Task {
 	if len(tasks) == 0 {
 		return nil
@@ -431,11 +465,17 @@ func Parallel(name string, tasks ...*Task) *Task {
 	joinTask := NewTask(
 		WithName(name+"-join"),
 		WithJob(func(ctx context.Context) error {
-			// 这个任务不做实际工作，只是等待所有依赖完成
-			return nil
+			// 这个任务不做实际工作，只汇总并行任务的错误
+			var errs []error
+			for _, task := range tasks {
+				if err := taskEndError(task); err != nil {
+					errs = append(errs, fmt.Errorf("task %s: %w", task.name, err))
+				}
+			}
+			return errors.Join(errs...)
 		}),
-		WithDependencies(tasks...),
 	)
+	joinTask.DependsOnAny(tasks...)
 
 	return joinTask
 }
@@ -0,0 +1,123 @@
+// scheduler/circuit_breaker.go
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState 表示熔断器的状态
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // 闭合状态，正常执行
+	CircuitOpen                         // 断开状态，直接拒绝执行
+	CircuitHalfOpen                     // 半开状态，允许一次试探执行
+)
+
+// String 返回熔断器状态的名称
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreaker 是任务执行的熔断器
+// 连续失败达到阈值后断开，断开期间任务函数不会被调用而直接返回 ErrCircuitOpen；
+// 冷却时间过后进入半开状态，允许一次试探执行，成功则闭合，失败则重新断开。
+// 同一个熔断器可以由多个任务共享，用于保护同一个下游服务
+type CircuitBreaker struct {
+	mutex     sync.Mutex
+	threshold int           // 触发断开的连续失败次数
+	cooldown  time.Duration // 断开后进入半开状态前的冷却时间
+	state     CircuitState  // 当前状态（断开状态在冷却后由 currentState 转为半开）
+	failures  int           // 连续失败次数
+	openedAt  time.Time     // 断开时间
+	probing   bool          // 半开状态下是否已有试探执行
+}
+
+// NewCircuitBreaker 创建连续失败 threshold 次后断开、冷却 cooldown 后半开的熔断器
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 1 // 至少失败一次才断开
+	}
+
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+	}
+}
+
+// State 返回熔断器当前状态
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.currentState()
+}
+
+// Reset 将熔断器恢复为闭合状态
+func (cb *CircuitBreaker) Reset() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.state = CircuitClosed
+	cb.failures = 0
+	cb.probing = false
+}
+
+// currentState 返回当前状态，冷却时间已过的断开状态转为半开（调用方需持有 cb.mutex）
+func (cb *CircuitBreaker) currentState() CircuitState {
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.cooldown {
+		cb.state = CircuitHalfOpen
+		cb.probing = false
+	}
+	return cb.state
+}
+
+// allow 检查是否允许执行，半开状态下只允许一次试探执行
+func (cb *CircuitBreaker) allow() bool {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	switch cb.currentState() {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record 记录一次执行结果并更新状态
+func (cb *CircuitBreaker) record(err error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if err == nil {
+		cb.state = CircuitClosed
+		cb.failures = 0
+		cb.probing = false
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = CircuitOpen
+		cb.openedAt = time.Now()
+		cb.probing = false
+	}
+}
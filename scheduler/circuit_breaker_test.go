@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCircuitBreakerTransitions 测试熔断器在闭合、断开、半开之间的状态转换
+func TestCircuitBreakerTransitions(t *testing.T) {
+	cb := NewCircuitBreaker(2, 50*time.Millisecond)
+
+	fail := true
+	calls := 0
+	run := func() error {
+		task := NewTask(
+			WithName("Downstream"),
+			WithSync(true),
+			WithRetry(3),
+			WithCircuitBreaker(cb),
+			WithJob(func(ctx context.Context) error {
+				calls++
+				if fail {
+					return errors.New("downstream unavailable")
+				}
+				return nil
+			}),
+		)
+		task.Run()
+		return task.GetLastError()
+	}
+
+	if cb.State() != CircuitClosed {
+		t.Fatalf("Expected initial state closed, got %v", cb.State())
+	}
+
+	// 连续失败两次后断开，断开后不再重试
+	if err := run(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen after reaching the threshold, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 job calls before opening, got %d", calls)
+	}
+	if cb.State() != CircuitOpen {
+		t.Fatalf("Expected state open, got %v", cb.State())
+	}
+
+	// 断开期间不调用任务函数
+	if err := run(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen while open, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected job not to be called while open, got %d calls", calls)
+	}
+
+	// 冷却后半开，试探失败重新断开
+	time.Sleep(60 * time.Millisecond)
+	if cb.State() != CircuitHalfOpen {
+		t.Fatalf("Expected state half-open after cooldown, got %v", cb.State())
+	}
+	run()
+	if calls != 3 {
+		t.Errorf("Expected a single probe call in half-open state, got %d calls", calls)
+	}
+	if cb.State() != CircuitOpen {
+		t.Fatalf("Expected failed probe to reopen the breaker, got %v", cb.State())
+	}
+
+	// 冷却后试探成功，恢复闭合
+	time.Sleep(60 * time.Millisecond)
+	fail = false
+	if err := run(); err != nil {
+		t.Errorf("Expected probe to succeed, got %v", err)
+	}
+	if cb.State() != CircuitClosed {
+		t.Errorf("Expected state closed after successful probe, got %v", cb.State())
+	}
+}
+
+// TestCircuitBreakerHalfOpenSingleProbe 测试半开状态只允许一次试探执行
+func TestCircuitBreakerHalfOpenSingleProbe(t *testing.T) {
+	cb := NewCircuitBreaker(1, 10*time.Millisecond)
+	cb.record(errors.New("failed"))
+
+	time.Sleep(20 * time.Millisecond)
+	if !cb.allow() {
+		t.Fatal("Expected first probe to be allowed")
+	}
+	if cb.allow() {
+		t.Error("Expected concurrent probe to be rejected")
+	}
+
+	cb.Reset()
+	if cb.State() != CircuitClosed || !cb.allow() {
+		t.Error("Expected Reset to close the breaker")
+	}
+}
+
+// TestCircuitBreakerPanickingProbe 测试半开状态的试探 panic 后熔断器重新断开，冷却后允许再次试探
+func TestCircuitBreakerPanickingProbe(t *testing.T) {
+	cb := NewCircuitBreaker(1, 20*time.Millisecond)
+	cb.record(errors.New("failed"))
+	time.Sleep(30 * time.Millisecond)
+
+	task := NewTask(
+		WithName("PanickingProbe"),
+		WithSync(true),
+		WithCircuitBreaker(cb),
+		WithJob(func(ctx context.Context) error {
+			panic("probe failed")
+		}),
+	)
+	task.Run()
+	if task.GetState() != TaskStateFailed {
+		t.Errorf("Expected panicking probe to fail the task, got %v", task.GetState())
+	}
+	if cb.State() != CircuitOpen {
+		t.Errorf("Expected panicking probe to reopen the breaker, got %v", cb.State())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !cb.allow() {
+		t.Error("Expected a new probe to be allowed after the cooldown")
+	}
+}
@@ -3,16 +3,21 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
+	"time"
 )
 
 // TaskContext 任务上下文，用于在任务之间传递数据
 type TaskContext struct {
-	values map[string]interface{}
-	mutex  sync.RWMutex
-	parent *TaskContext // 父上下文，用于继承
+	values   map[string]interface{}
+	mutex    sync.RWMutex
+	parent   *TaskContext                  // 父上下文，用于继承
+	watchers map[string][]chan interface{} // 按键注册的值变化通知通道，由 mutex 保护
+	expires  map[string]time.Time          // 通过 SetWithTTL 设置的键的过期时间，由 mutex 保护
 }
 
 // NewTaskContext 创建新的任务上下文
@@ -34,26 +39,184 @@ func (tc *TaskContext) Set(key string, value interface{}) {
 	defer tc.mutex.Unlock()
 
 	tc.values[key] = value
+	delete(tc.expires, key)
+	tc.notifyLocked(key, value)
+}
+
+// SetWithTTL 设置在 ttl 之后过期的上下文值，ttl <= 0 时等同于 Set
+// 过期的值对 Get、Has、GetAll 等读取操作不可见，父上下文中的同名值重新可见；
+// 过期的值在读取时被删除，也可以通过 StartExpirySweeper 定期清理
+func (tc *TaskContext) SetWithTTL(key string, value interface{}, ttl time.Duration) {
+	if ttl <= 0 {
+		tc.Set(key, value)
+		return
+	}
+
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	tc.values[key] = value
+	if tc.expires == nil {
+		tc.expires = make(map[string]time.Time)
+	}
+	tc.expires[key] = time.Now().Add(ttl)
+	tc.notifyLocked(key, value)
+}
+
+// StartExpirySweeper 启动后台协程，每隔 interval 删除已过期的值，直到 ctx 被取消
+func (tc *TaskContext) StartExpirySweeper(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				tc.purgeExpired()
+			}
+		}
+	}()
+}
+
+// purgeExpired 删除所有已过期的值
+func (tc *TaskContext) purgeExpired() {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	now := time.Now()
+	for key := range tc.expires {
+		if tc.expiredLocked(key, now) {
+			delete(tc.values, key)
+			delete(tc.expires, key)
+		}
+	}
+}
+
+// removeExpired 在值已过期时删除它
+func (tc *TaskContext) removeExpired(key string) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	if tc.expiredLocked(key, time.Now()) {
+		delete(tc.values, key)
+		delete(tc.expires, key)
+	}
+}
+
+// expiredLocked 检查键是否已过期，调用者需持有锁
+func (tc *TaskContext) expiredLocked(key string, now time.Time) bool {
+	at, ok := tc.expires[key]
+	return ok && !now.Before(at)
+}
+
+// Watch 返回一个通道，每次设置 key 的值时（Set、SetWithTTL、Merge、LoadJSON、Restore）通道会收到新值
+// 通道只缓冲最新的一个值，接收不及时时旧值被新值替换，因此 Set 不会被阻塞。
+// 删除值（Delete、Clear、Restore 删除快照之后新增的键、值过期）不发送通知。
+// 可以为同一个键注册多个通道，不再使用时应调用 Unwatch 释放
+func (tc *TaskContext) Watch(key string) <-chan interface{} {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	if tc.watchers == nil {
+		tc.watchers = make(map[string][]chan interface{})
+	}
+	ch := make(chan interface{}, 1)
+	tc.watchers[key] = append(tc.watchers[key], ch)
+	return ch
+}
+
+// Unwatch 注销 Watch 返回的通道并关闭它，通道未注册时不做任何事
+func (tc *TaskContext) Unwatch(key string, ch <-chan interface{}) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	watchers := tc.watchers[key]
+	for i, c := range watchers {
+		if (<-chan interface{})(c) != ch {
+			continue
+		}
+		close(c)
+		watchers = append(watchers[:i], watchers[i+1:]...)
+		if len(watchers) == 0 {
+			delete(tc.watchers, key)
+		} else {
+			tc.watchers[key] = watchers
+		}
+		return
+	}
+}
+
+// notifyLocked 将新值发送给 key 的所有监听通道，调用者需持有写锁
+// 写锁保证只有一个协程在发送，因此清空旧值后的发送不会阻塞
+func (tc *TaskContext) notifyLocked(key string, value interface{}) {
+	for _, ch := range tc.watchers[key] {
+		select {
+		case ch <- value:
+			continue
+		default:
+		}
+
+		// 通道中有未接收的旧值，替换为新值
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- value
+	}
 }
 
 // Get 获取上下文值
 func (tc *TaskContext) Get(key string) (interface{}, bool) {
+	// 先从当前上下文查找
 	tc.mutex.RLock()
-	defer tc.mutex.RUnlock()
+	value, exists := tc.values[key]
+	expired := exists && tc.expiredLocked(key, time.Now())
+	parent := tc.parent
+	tc.mutex.RUnlock()
 
-	// 先从当前上下文查找
-	if value, exists := tc.values[key]; exists {
+	if exists && !expired {
 		return value, true
 	}
+	if expired {
+		tc.removeExpired(key)
+	}
 
 	// 如果没有找到且有父上下文，则从父上下文查找
-	if tc.parent != nil {
-		return tc.parent.Get(key)
+	if parent != nil {
+		return parent.Get(key)
 	}
 
 	return nil, false
 }
 
+// Has 检查键是否存在（包括父上下文）
+func (tc *TaskContext) Has(key string) bool {
+	tc.mutex.RLock()
+	defer tc.mutex.RUnlock()
+
+	if _, exists := tc.values[key]; exists && !tc.expiredLocked(key, time.Now()) {
+		return true
+	}
+
+	if tc.parent != nil {
+		return tc.parent.Has(key)
+	}
+
+	return false
+}
+
+// Delete 从当前上下文中删除键
+// 注意：只删除当前上下文中的值，父上下文中的同名值不受影响，仍可通过 Get 访问
+func (tc *TaskContext) Delete(key string) {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	delete(tc.values, key)
+	delete(tc.expires, key)
+}
+
 // GetString 获取字符串类型的上下文值
 func (tc *TaskContext) GetString(key string) (string, bool) {
 	value, exists := tc.Get(key)
@@ -104,6 +267,76 @@ func (tc *TaskContext) GetFloat(key string) (float64, bool) {
 	}
 }
 
+// GetDuration 获取时间间隔类型的上下文值
+// 支持 time.Duration、表示纳秒数的 int/int64，以及可被 time.ParseDuration 解析的字符串
+func (tc *TaskContext) GetDuration(key string) (time.Duration, bool) {
+	value, exists := tc.Get(key)
+	if !exists {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case time.Duration:
+		return v, true
+	case int:
+		return time.Duration(v), true
+	case int64:
+		return time.Duration(v), true
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, false
+		}
+		return d, true
+	default:
+		return 0, false
+	}
+}
+
+// GetTime 获取时间类型的上下文值
+// 支持 time.Time、*time.Time 以及 RFC3339 格式的字符串
+func (tc *TaskContext) GetTime(key string) (time.Time, bool) {
+	value, exists := tc.Get(key)
+	if !exists {
+		return time.Time{}, false
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, true
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// GetValue 获取指定类型的上下文值（包括父上下文）
+// 键不存在或类型不匹配时返回零值和 false
+func GetValue[T any](tc *TaskContext, key string) (T, bool) {
+	var zero T
+
+	value, exists := tc.Get(key)
+	if !exists {
+		return zero, false
+	}
+
+	v, ok := value.(T)
+	if !ok {
+		return zero, false
+	}
+	return v, true
+}
+
 // GetAll 获取所有上下文值
 func (tc *TaskContext) GetAll() map[string]interface{} {
 	tc.mutex.RLock()
@@ -121,13 +354,47 @@ func (tc *TaskContext) GetAll() map[string]interface{} {
 	}
 
 	// 添加当前上下文的值，覆盖父上下文的同名值
+	now := time.Now()
 	for k, v := range tc.values {
-		result[k] = v
+		if !tc.expiredLocked(k, now) {
+			result[k] = v
+		}
 	}
 
 	return result
 }
 
+// ForEach 遍历所有上下文值（包括父上下文），fn 返回 false 时停止遍历
+// 当前上下文的值会覆盖父上下文的同名值，每个键只访问一次
+// 遍历期间持有读锁，因此在 fn 中修改上下文（Set、Delete 等）是不安全的，会导致死锁
+func (tc *TaskContext) ForEach(fn func(key string, value interface{}) bool) {
+	tc.forEach(fn, make(map[string]struct{}))
+}
+
+// forEach 遍历上下文值，跳过已访问的键，返回是否应继续遍历
+func (tc *TaskContext) forEach(fn func(key string, value interface{}) bool, seen map[string]struct{}) bool {
+	tc.mutex.RLock()
+	defer tc.mutex.RUnlock()
+
+	now := time.Now()
+	for k, v := range tc.values {
+		if _, visited := seen[k]; visited || tc.expiredLocked(k, now) {
+			continue
+		}
+		seen[k] = struct{}{}
+
+		if !fn(k, v) {
+			return false
+		}
+	}
+
+	if tc.parent != nil {
+		return tc.parent.forEach(fn, seen)
+	}
+
+	return true
+}
+
 // Filter 根据前缀过滤上下文值
 func (tc *TaskContext) Filter(prefix string) map[string]interface{} {
 	tc.mutex.RLock()
@@ -166,25 +433,41 @@ func (tc *TaskContext) Transform(transformer func(key string, value interface{})
 	return newContext
 }
 
-// CopyTo 将上下文值复制到另一个上下文
+// CopyTo 将上下文值复制到另一个上下文，等价于 target.Merge(tc, overwrite)
 func (tc *TaskContext) CopyTo(target *TaskContext, overwrite bool) {
-	tc.mutex.RLock()
-	defer tc.mutex.RUnlock()
+	target.Merge(tc, overwrite)
+}
 
-	// 获取所有值
-	allValues := tc.GetAll()
+// Merge 将另一个上下文的所有值（包括其父上下文的值）合并到当前上下文
+// overwrite 为 false 时只复制当前上下文（包括父上下文）中不存在的键；为 true 时覆盖同名的值
+// 合并在持有当前上下文写锁时完成，期间其他协程看不到合并了一半的结果
+func (tc *TaskContext) Merge(other *TaskContext, overwrite bool) {
+	if other == nil || other == tc {
+		return
+	}
 
-	// 复制值
-	for k, v := range allValues {
-		if !overwrite {
-			// 如果不覆盖且目标上下文已有该键，则跳过
-			if _, exists := target.Get(k); exists {
-				continue
-			}
+	// 先获取另一个上下文的快照，避免同时持有两个上下文的锁
+	values := other.GetAll()
+
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	for k, v := range values {
+		if !overwrite && tc.hasLocked(k) {
+			continue
 		}
+		tc.values[k] = v
+		delete(tc.expires, k)
+		tc.notifyLocked(k, v)
+	}
+}
 
-		target.Set(k, v)
+// hasLocked 检查键是否存在（包括父上下文），调用者需持有当前上下文的锁
+func (tc *TaskContext) hasLocked(key string) bool {
+	if _, exists := tc.values[key]; exists && !tc.expiredLocked(key, time.Now()) {
+		return true
 	}
+	return tc.parent != nil && tc.parent.Has(key)
 }
 
 // Validator 上下文验证器函数类型
@@ -228,12 +511,174 @@ func (tc *TaskContext) RequiredKeys(keys ...string) error {
 	return nil
 }
 
+// ContextSnapshot 是 Snapshot 返回的上下文快照
+type ContextSnapshot struct {
+	Values  map[string]interface{} // 快照中的值
+	Expires map[string]time.Time   // 通过 SetWithTTL 设置的值的过期时间
+}
+
+// Snapshot 返回当前上下文中值的副本，可以通过 Restore 恢复
+// 只包含当前上下文中未过期的值，不包含父上下文的值；值本身是浅拷贝，修改可变值（例如切片、映射）的内容不会被撤销。
+// 通过 SetWithTTL 设置的值的过期时间保存在 Expires 中，Restore 后仍在原来的时间过期
+func (tc *TaskContext) Snapshot() ContextSnapshot {
+	tc.mutex.RLock()
+	defer tc.mutex.RUnlock()
+
+	now := time.Now()
+	snap := ContextSnapshot{Values: make(map[string]interface{}, len(tc.values))}
+	for k, v := range tc.values {
+		if tc.expiredLocked(k, now) {
+			continue
+		}
+		snap.Values[k] = v
+		if at, ok := tc.expires[k]; ok {
+			if snap.Expires == nil {
+				snap.Expires = make(map[string]time.Time)
+			}
+			snap.Expires[k] = at
+		}
+	}
+	return snap
+}
+
+// Restore 将当前上下文的值恢复为 Snapshot 返回的快照，快照之后新增的键被删除，父上下文不受影响
+// 快照中的值恢复原来的过期时间，恢复时已过期的值被丢弃；恢复的每个值都会通知 Watch 的监听者
+func (tc *TaskContext) Restore(snap ContextSnapshot) {
+	now := time.Now()
+	values := make(map[string]interface{}, len(snap.Values))
+	var expires map[string]time.Time
+	for k, v := range snap.Values {
+		if at, ok := snap.Expires[k]; ok {
+			if !now.Before(at) {
+				continue
+			}
+			if expires == nil {
+				expires = make(map[string]time.Time)
+			}
+			expires[k] = at
+		}
+		values[k] = v
+	}
+
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+	tc.values = values
+	tc.expires = expires
+	for k, v := range values {
+		tc.notifyLocked(k, v)
+	}
+}
+
 // Clear 清除所有上下文值
 func (tc *TaskContext) Clear() {
 	tc.mutex.Lock()
 	defer tc.mutex.Unlock()
 
 	tc.values = make(map[string]interface{})
+	tc.expires = nil
+}
+
+// Clone 创建上下文的深拷贝，副本与原上下文共享父上下文
+// 映射和切片类型的值会被递归复制，修改副本中的这些值不会影响原上下文；
+// 指针、通道等其他引用类型的值仍与原上下文共享
+func (tc *TaskContext) Clone() *TaskContext {
+	tc.mutex.RLock()
+	defer tc.mutex.RUnlock()
+
+	clone := NewTaskContext()
+	clone.parent = tc.parent
+	now := time.Now()
+	for k, v := range tc.values {
+		if tc.expiredLocked(k, now) {
+			continue
+		}
+		clone.values[k] = deepCopyValue(v)
+		if at, ok := tc.expires[k]; ok {
+			if clone.expires == nil {
+				clone.expires = make(map[string]time.Time)
+			}
+			clone.expires[k] = at
+		}
+	}
+	return clone
+}
+
+// deepCopyValue 递归复制映射和切片类型的值，其他类型的值原样返回
+func deepCopyValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return deepCopyReflect(reflect.ValueOf(value)).Interface()
+}
+
+// deepCopyReflect 是 deepCopyValue 基于反射的实现
+func deepCopyReflect(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopyReflect(iter.Value()))
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopyReflect(v.Index(i)))
+		}
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(deepCopyReflect(v.Elem()))
+		return copied
+	default:
+		return v
+	}
+}
+
+// MarshalJSON 将上下文序列化为 JSON 对象
+// 序列化的是 GetAll 返回的合并后的值（包括父上下文），父上下文的链接本身不会被序列化；
+// 只有可被 encoding/json 编码的值才能被保留
+func (tc *TaskContext) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tc.GetAll())
+}
+
+// LoadJSON 从 JSON 对象加载上下文值，已存在的同名键会被覆盖
+// 注意：数字会被解码为 float64，对象和数组分别被解码为 map[string]interface{} 和 []interface{}
+func (tc *TaskContext) LoadJSON(data []byte) error {
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("failed to load context from JSON: %w", err)
+	}
+
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	for k, v := range values {
+		tc.values[k] = v
+		delete(tc.expires, k)
+		tc.notifyLocked(k, v)
+	}
+
+	return nil
+}
+
+// TaskContextFromJSON 从 JSON 数据创建新的任务上下文
+func TaskContextFromJSON(data []byte) (*TaskContext, error) {
+	tc := NewTaskContext()
+	if err := tc.LoadJSON(data); err != nil {
+		return nil, err
+	}
+	return tc, nil
 }
 
 // taskContextKey 是用于在 context.Context 中存储任务的键
@@ -3,7 +3,9 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -201,3 +203,138 @@ func TestTaskGroup(t *testing.T) {
 		t.Errorf("Expected task1 value 'done', got %v, exists: %v", value, exists)
 	}
 }
+
+// TestContextGetDuration 测试获取时间间隔类型的上下文值
+func TestContextGetDuration(t *testing.T) {
+	ctx := NewTaskContext()
+	ctx.Set("duration", 2*time.Second)
+	ctx.Set("nanos", int64(1500))
+	ctx.Set("int", 10)
+	ctx.Set("string", "3m")
+	ctx.Set("invalid", "not a duration")
+	ctx.Set("wrong", true)
+
+	tests := []struct {
+		key      string
+		expected time.Duration
+		ok       bool
+	}{
+		{"duration", 2 * time.Second, true},
+		{"nanos", 1500 * time.Nanosecond, true},
+		{"int", 10 * time.Nanosecond, true},
+		{"string", 3 * time.Minute, true},
+		{"invalid", 0, false},
+		{"wrong", 0, false},
+		{"missing", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ctx.GetDuration(tt.key)
+		if ok != tt.ok || got != tt.expected {
+			t.Errorf("GetDuration(%q) = %v, %v; expected %v, %v", tt.key, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+// TestContextGetTime 测试获取时间类型的上下文值
+func TestContextGetTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ctx := NewTaskContext()
+	ctx.Set("time", now)
+	ctx.Set("pointer", &now)
+	ctx.Set("string", now.Format(time.RFC3339))
+	ctx.Set("invalid", "yesterday")
+	ctx.Set("wrong", 42)
+
+	for _, key := range []string{"time", "pointer", "string"} {
+		got, ok := ctx.GetTime(key)
+		if !ok || !got.Equal(now) {
+			t.Errorf("GetTime(%q) = %v, %v; expected %v, true", key, got, ok, now)
+		}
+	}
+
+	for _, key := range []string{"invalid", "wrong", "missing"} {
+		if got, ok := ctx.GetTime(key); ok || !got.IsZero() {
+			t.Errorf("GetTime(%q) = %v, %v; expected zero time, false", key, got, ok)
+		}
+	}
+}
+
+// TestGetValueGeneric 测试泛型获取上下文值
+func TestGetValueGeneric(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	parent := NewTaskContext()
+	parent.Set("user", user{Name: "Alice", Age: 30})
+
+	ctx := NewTaskContext().WithParent(parent)
+	ctx.Set("items", []string{"a", "b"})
+	ctx.Set("count", 3)
+
+	// 自定义结构体（来自父上下文）
+	if u, ok := GetValue[user](ctx, "user"); !ok || u.Name != "Alice" || u.Age != 30 {
+		t.Errorf("Expected user Alice/30, got %+v, exists: %v", u, ok)
+	}
+
+	// 切片
+	if items, ok := GetValue[[]string](ctx, "items"); !ok || len(items) != 2 || items[1] != "b" {
+		t.Errorf("Expected items [a b], got %v, exists: %v", items, ok)
+	}
+
+	// 类型不匹配
+	if s, ok := GetValue[string](ctx, "count"); ok || s != "" {
+		t.Errorf("Expected type mismatch to return zero value and false, got %q, %v", s, ok)
+	}
+
+	// 键不存在
+	if u, ok := GetValue[*user](ctx, "missing"); ok || u != nil {
+		t.Errorf("Expected missing key to return nil and false, got %v, %v", u, ok)
+	}
+}
+
+// TestContextJSONRoundTrip 测试上下文 JSON 序列化和加载
+func TestContextJSONRoundTrip(t *testing.T) {
+	parent := NewTaskContext()
+	parent.Set("env", "prod")
+
+	ctx := NewTaskContext().WithParent(parent)
+	ctx.Set("name", "backup")
+	ctx.Set("enabled", true)
+	ctx.Set("ratio", 0.5)
+	ctx.Set("tags", []interface{}{"a", "b"})
+	ctx.Set("config", map[string]interface{}{"retries": float64(3)})
+
+	data, err := json.Marshal(ctx)
+	if err != nil {
+		t.Fatalf("Failed to marshal context: %v", err)
+	}
+
+	restored, err := TaskContextFromJSON(data)
+	if err != nil {
+		t.Fatalf("Failed to load context: %v", err)
+	}
+
+	if !reflect.DeepEqual(ctx.GetAll(), restored.GetAll()) {
+		t.Errorf("Expected restored values %v, got %v", ctx.GetAll(), restored.GetAll())
+	}
+
+	// 整数会被解码为 float64
+	ctx.Set("count", 3)
+	data, _ = ctx.MarshalJSON()
+	fresh := NewTaskContext()
+	if err := fresh.LoadJSON(data); err != nil {
+		t.Fatalf("Failed to load context: %v", err)
+	}
+	if val, ok := GetValue[float64](fresh, "count"); !ok || val != 3 {
+		t.Errorf("Expected count to be decoded as float64 3, got %v, exists: %v", val, ok)
+	}
+
+	// 无效的 JSON
+	if err := fresh.LoadJSON([]byte("not json")); err == nil {
+		t.Error("Expected error for invalid JSON, got nil")
+	}
+}
@@ -3,6 +3,8 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -188,3 +190,509 @@ func TestChainTasks(t *testing.T) {
 		t.Errorf("Expected task1 value 'executed' in task2 context, got '%v', exists: %v", val, ok)
 	}
 }
+
+// TestTaskContextDeleteHas 测试删除键和检查键是否存在
+func TestTaskContextDeleteHas(t *testing.T) {
+	parent := NewTaskContext()
+	parent.Set("shared", "parent")
+	parent.Set("parentOnly", "value")
+
+	child := NewTaskContext().WithParent(parent)
+	child.Set("shared", "child")
+	child.Set("local", "value")
+
+	if !child.Has("local") || !child.Has("parentOnly") {
+		t.Error("Expected Has to find local and parent keys")
+	}
+	if child.Has("missing") {
+		t.Error("Expected Has to return false for missing key")
+	}
+
+	// 删除本地键
+	child.Delete("local")
+	if child.Has("local") {
+		t.Error("Expected local key to be deleted")
+	}
+
+	// 删除覆盖父上下文的键后，父上下文的值重新可见
+	child.Delete("shared")
+	if val, ok := child.GetString("shared"); !ok || val != "parent" {
+		t.Errorf("Expected shared value 'parent' after delete, got '%v', exists: %v", val, ok)
+	}
+
+	// 删除只存在于父上下文中的键不影响父上下文
+	child.Delete("parentOnly")
+	if !child.Has("parentOnly") {
+		t.Error("Expected parent-only key to remain visible through parent")
+	}
+	if !parent.Has("parentOnly") {
+		t.Error("Expected parent-only key to remain in parent")
+	}
+}
+
+// TestTaskContextMerge 测试合并上下文
+func TestTaskContextMerge(t *testing.T) {
+	newSource := func() *TaskContext {
+		parent := NewTaskContext()
+		parent.Set("fromParent", "p")
+		source := NewTaskContext().WithParent(parent)
+		source.Set("shared", "source")
+		source.Set("sourceOnly", 1)
+		return source
+	}
+
+	// overwrite 为 false 时只复制缺失的键
+	target := NewTaskContext()
+	target.Set("shared", "target")
+	target.Merge(newSource(), false)
+	if val, _ := target.GetString("shared"); val != "target" {
+		t.Errorf("Expected existing key to be kept, got %q", val)
+	}
+	if val, _ := target.GetInt("sourceOnly"); val != 1 {
+		t.Errorf("Expected missing key to be copied, got %v", val)
+	}
+	if val, _ := target.GetString("fromParent"); val != "p" {
+		t.Errorf("Expected source parent values to be copied, got %q", val)
+	}
+
+	// 目标父上下文中已有的键也视为已存在
+	targetParent := NewTaskContext()
+	targetParent.Set("sourceOnly", 2)
+	child := NewTaskContext().WithParent(targetParent)
+	child.Merge(newSource(), false)
+	if val, _ := child.GetInt("sourceOnly"); val != 2 {
+		t.Errorf("Expected key from target parent to be kept, got %v", val)
+	}
+
+	// overwrite 为 true 时覆盖同名的值
+	target = NewTaskContext()
+	target.Set("shared", "target")
+	target.Set("targetOnly", true)
+	target.Merge(newSource(), true)
+	if val, _ := target.GetString("shared"); val != "source" {
+		t.Errorf("Expected existing key to be overwritten, got %q", val)
+	}
+	if val, _ := target.GetBool("targetOnly"); !val {
+		t.Error("Expected keys missing from source to be kept")
+	}
+
+	// 与自身或 nil 合并不做任何事
+	target.Merge(target, true)
+	target.Merge(nil, true)
+	if len(target.GetAll()) != 4 {
+		t.Errorf("Expected 4 keys, got %v", target.GetAll())
+	}
+}
+
+// TestTaskContextSnapshotRestore 测试上下文快照和恢复
+func TestTaskContextSnapshotRestore(t *testing.T) {
+	parent := NewTaskContext()
+	parent.Set("fromParent", "p")
+	tc := NewTaskContext().WithParent(parent)
+	tc.Set("count", 1)
+	tc.Set("name", "original")
+
+	snap := tc.Snapshot()
+	if _, ok := snap.Values["fromParent"]; ok {
+		t.Error("Expected snapshot to exclude parent values")
+	}
+
+	tc.Set("count", 2)
+	tc.Set("added", true)
+	tc.Delete("name")
+	snap.Values["count"] = 100 // 修改快照不影响上下文
+
+	tc.Restore(snap)
+	if val, _ := tc.GetInt("count"); val != 100 {
+		t.Errorf("Expected restored count 100, got %v", val)
+	}
+	if val, _ := tc.GetString("name"); val != "original" {
+		t.Errorf("Expected deleted key to be restored, got %q", val)
+	}
+	if tc.Has("added") {
+		t.Error("Expected key added after snapshot to be removed")
+	}
+	if val, _ := tc.GetString("fromParent"); val != "p" {
+		t.Errorf("Expected parent values to remain visible, got %q", val)
+	}
+
+	// 恢复后修改快照不影响上下文
+	snap.Values["count"] = 200
+	if val, _ := tc.GetInt("count"); val != 100 {
+		t.Errorf("Expected Restore to copy the snapshot, got %v", val)
+	}
+}
+
+// TestTaskContextRestoreTTLAndWatch 测试恢复快照时保留过期时间并通知监听者
+func TestTaskContextRestoreTTLAndWatch(t *testing.T) {
+	tc := NewTaskContext()
+	tc.SetWithTTL("token", "abc", 100*time.Millisecond)
+	tc.Set("count", 1)
+
+	snap := tc.Snapshot()
+	if _, ok := snap.Expires["token"]; !ok {
+		t.Error("Expected snapshot to record the TTL of token")
+	}
+	if val := snap.Values["token"]; val != "abc" {
+		t.Errorf("Expected snapshot to hold the plain value 'abc', got %v", val)
+	}
+	tc.Set("token", "permanent")
+	tc.Set("count", 2)
+
+	watcher := tc.Watch("count")
+	tc.Restore(snap)
+
+	select {
+	case v := <-watcher:
+		if v != 1 {
+			t.Errorf("Expected watcher to receive restored value 1, got %v", v)
+		}
+	default:
+		t.Error("Expected Restore to notify watchers")
+	}
+	if val, _ := tc.GetString("token"); val != "abc" {
+		t.Errorf("Expected restored token 'abc', got %q", val)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if tc.Has("token") {
+		t.Error("Expected restored token to expire at its original time")
+	}
+	if !tc.Has("count") {
+		t.Error("Expected value without TTL not to expire after restore")
+	}
+
+	// 恢复时已过期的值被丢弃
+	tc.Restore(snap)
+	if tc.Has("token") {
+		t.Error("Expected value that expired before restore to be dropped")
+	}
+}
+
+// TestTaskContextRollbackOnRetry 测试重试前回滚任务上下文
+func TestTaskContextRollbackOnRetry(t *testing.T) {
+	run := func(rollback bool) (seen []int) {
+		tc := NewTaskContext()
+		tc.Set("count", 0)
+		task := NewTask(
+			WithTaskContext(tc),
+			WithRetryStrategy(NewFixedDelayRetryStrategy(time.Millisecond, 2)),
+			WithContextRollbackOnRetry(rollback),
+			WithJob(func(ctx context.Context) error {
+				count, _ := tc.GetInt("count")
+				seen = append(seen, count)
+				tc.Set("count", count+1)
+				if len(seen) < 3 {
+					return errors.New("attempt failed")
+				}
+				return nil
+			}),
+		)
+		if err := task.RunOnce(context.Background()); err != nil {
+			t.Fatalf("Expected third attempt to succeed, got %v", err)
+		}
+		return seen
+	}
+
+	if seen := run(true); len(seen) != 3 || seen[0] != 0 || seen[1] != 0 || seen[2] != 0 {
+		t.Errorf("Expected every attempt to start from count 0 with rollback, got %v", seen)
+	}
+	if seen := run(false); len(seen) != 3 || seen[2] != 2 {
+		t.Errorf("Expected attempts to see earlier changes without rollback, got %v", seen)
+	}
+}
+
+// TestTaskContextWatch 测试监听上下文值的变化
+func TestTaskContextWatch(t *testing.T) {
+	tc := NewTaskContext()
+	first := tc.Watch("status")
+	second := tc.Watch("status")
+	other := tc.Watch("other")
+
+	go tc.Set("status", "running")
+
+	for i, ch := range []<-chan interface{}{first, second} {
+		select {
+		case v := <-ch:
+			if v != "running" {
+				t.Errorf("Watcher %d: expected 'running', got %v", i, v)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Watcher %d: timeout waiting for value", i)
+		}
+	}
+	select {
+	case v := <-other:
+		t.Errorf("Expected no notification for another key, got %v", v)
+	default:
+	}
+
+	// 未及时接收时只保留最新的值，Set 不会阻塞
+	tc.Set("status", "a")
+	tc.Set("status", "b")
+	tc.Set("status", "c")
+	if v := <-first; v != "c" {
+		t.Errorf("Expected latest value 'c', got %v", v)
+	}
+
+	// 注销后通道被关闭，不再收到通知
+	tc.Unwatch("status", first)
+	if _, ok := <-first; ok {
+		t.Error("Expected unwatched channel to be closed")
+	}
+	<-second
+	tc.Set("status", "done")
+	if v := <-second; v != "done" {
+		t.Errorf("Expected remaining watcher to receive 'done', got %v", v)
+	}
+	tc.Unwatch("status", first) // 重复注销不做任何事
+}
+
+// TestTaskContextTTL 测试带过期时间的上下文值
+func TestTaskContextTTL(t *testing.T) {
+	parent := NewTaskContext()
+	parent.Set("shared", "parent")
+	parent.Set("parentOnly", "value")
+
+	tc := NewTaskContext().WithParent(parent)
+	tc.SetWithTTL("token", "abc", 50*time.Millisecond)
+	tc.SetWithTTL("shared", "child", 50*time.Millisecond)
+	tc.Set("permanent", 1)
+
+	if val, ok := tc.GetString("token"); !ok || val != "abc" {
+		t.Errorf("Expected token before expiry, got %q, exists: %v", val, ok)
+	}
+	if val, _ := tc.GetString("shared"); val != "child" {
+		t.Errorf("Expected child value to shadow parent before expiry, got %q", val)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if _, ok := tc.Get("token"); ok {
+		t.Error("Expected token to be expired")
+	}
+	if tc.Has("token") {
+		t.Error("Expected Has to ignore expired values")
+	}
+	if _, ok := tc.GetAll()["token"]; ok {
+		t.Error("Expected GetAll to ignore expired values")
+	}
+	if val, _ := tc.GetString("shared"); val != "parent" {
+		t.Errorf("Expected parent value to be visible after expiry, got %q", val)
+	}
+	if val, ok := tc.GetString("parentOnly"); !ok || val != "value" {
+		t.Errorf("Expected non-expiring parent value, got %q, exists: %v", val, ok)
+	}
+	if !tc.Has("permanent") {
+		t.Error("Expected value without TTL not to expire")
+	}
+
+	// Set 清除之前的过期时间
+	tc.SetWithTTL("key", 1, 20*time.Millisecond)
+	tc.Set("key", 2)
+	time.Sleep(40 * time.Millisecond)
+	if val, ok := tc.GetInt("key"); !ok || val != 2 {
+		t.Errorf("Expected Set to remove the TTL, got %v, exists: %v", val, ok)
+	}
+}
+
+// TestTaskContextExpirySweeper 测试后台清理过期值
+func TestTaskContextExpirySweeper(t *testing.T) {
+	tc := NewTaskContext()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc.StartExpirySweeper(ctx, 10*time.Millisecond)
+
+	tc.SetWithTTL("token", "abc", 20*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	tc.mutex.RLock()
+	_, exists := tc.values["token"]
+	tc.mutex.RUnlock()
+	if exists {
+		t.Error("Expected sweeper to remove the expired value")
+	}
+}
+
+// TestTaskContextForEach 测试遍历上下文值
+func TestTaskContextForEach(t *testing.T) {
+	parent := NewTaskContext()
+	parent.Set("a", "parent")
+	parent.Set("b", "parent")
+
+	child := NewTaskContext().WithParent(parent)
+	child.Set("a", "child")
+	child.Set("c", "child")
+
+	visited := make(map[string]interface{})
+	child.ForEach(func(key string, value interface{}) bool {
+		if _, dup := visited[key]; dup {
+			t.Errorf("Key %s visited more than once", key)
+		}
+		visited[key] = value
+		return true
+	})
+
+	expected := map[string]interface{}{"a": "child", "b": "parent", "c": "child"}
+	if len(visited) != len(expected) {
+		t.Errorf("Expected %d keys, got %d", len(expected), len(visited))
+	}
+	for k, v := range expected {
+		if visited[k] != v {
+			t.Errorf("Expected %s to be %v, got %v", k, v, visited[k])
+		}
+	}
+
+	// 返回 false 时停止遍历
+	count := 0
+	child.ForEach(func(key string, value interface{}) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("Expected iteration to stop after 1 key, got %d", count)
+	}
+}
+
+// TestTaskContextCleanReason 测试上下文清理钩子收到的结束原因
+func TestTaskContextCleanReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []TaskOption
+		stop     bool
+		expected CleanupReason
+	}{
+		{
+			name: "completed",
+			options: []TaskOption{
+				WithJob(func(ctx context.Context) error { return nil }),
+			},
+			expected: CleanupCompleted,
+		},
+		{
+			name: "failed",
+			options: []TaskOption{
+				WithJob(func(ctx context.Context) error { return errors.New("job failed") }),
+				WithCancelOnFailure(true),
+			},
+			expected: CleanupFailed,
+		},
+		{
+			name: "cancelled",
+			options: []TaskOption{
+				WithRepeat(20 * time.Millisecond),
+				WithJob(func(ctx context.Context) error { return nil }),
+			},
+			stop:     true,
+			expected: CleanupCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons := make(chan CleanupReason, 1)
+			cleaned := make(chan struct{}, 1)
+			options := append([]TaskOption{
+				WithName("CleanReason-" + tt.name),
+				WithTaskContext(NewTaskContext()),
+				WithContextClean(func(ctx *TaskContext) {
+					cleaned <- struct{}{}
+				}),
+				WithContextCleanReason(func(ctx *TaskContext, reason CleanupReason) {
+					reasons <- reason
+				}),
+			}, tt.options...)
+
+			task := NewTask(options...)
+			task.Run()
+			if tt.stop {
+				time.Sleep(50 * time.Millisecond)
+				task.Stop()
+			}
+
+			select {
+			case reason := <-reasons:
+				if reason != tt.expected {
+					t.Errorf("Expected cleanup reason %v, got %v", tt.expected, reason)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Timeout waiting for context cleanup")
+			}
+
+			// 原有的清理钩子仍会被调用
+			select {
+			case <-cleaned:
+			default:
+				t.Error("Expected WithContextClean hook to be called")
+			}
+		})
+	}
+}
+
+// TestTaskPrepTimeout 测试上下文准备钩子超时后任务失败
+func TestTaskPrepTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	var jobRan int32
+	done := make(chan struct{})
+	task := NewTask(
+		WithName("PrepTimeout"),
+		WithContextPrep(func(ctx *TaskContext) {
+			<-release
+		}),
+		WithPrepTimeout(50*time.Millisecond),
+		WithJob(func(ctx context.Context) error {
+			atomic.StoreInt32(&jobRan, 1)
+			return nil
+		}),
+		WithOnComplete(func(state TaskState, lastErr error) {
+			close(done)
+		}),
+	)
+
+	task.Run()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for prep hook to time out")
+	}
+
+	if state := task.GetState(); state != TaskStateFailed {
+		t.Errorf("Expected task to fail, got %v", state)
+	}
+	if err := task.GetLastError(); !errors.Is(err, ErrPrepTimeout) {
+		t.Errorf("Expected ErrPrepTimeout, got %v", err)
+	}
+	if atomic.LoadInt32(&jobRan) != 0 {
+		t.Error("Expected job not to run after prep timeout")
+	}
+}
+
+// TestTaskCleanTimeout 测试上下文清理钩子超时后不阻塞任务结束
+func TestTaskCleanTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	task := NewTask(
+		WithName("CleanTimeout"),
+		WithTaskContext(NewTaskContext()),
+		WithContextClean(func(ctx *TaskContext) {
+			<-release
+		}),
+		WithCleanTimeout(50*time.Millisecond),
+		WithJob(func(ctx context.Context) error { return nil }),
+		WithSync(true),
+	)
+
+	// 同步执行的任务在清理结束后 Run 才返回
+	start := time.Now()
+	task.Run()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected slow clean hook to be abandoned, took %v", elapsed)
+	}
+	if state := task.GetState(); state != TaskStateCompleted {
+		t.Errorf("Expected task to complete, got %v", state)
+	}
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestParseCronNext 测试 cron 表达式解析和下一次触发时间计算
+func TestParseCronNext(t *testing.T) {
+	base := time.Date(2024, 1, 31, 10, 17, 30, 0, time.UTC) // 周三
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 31, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2024, 1, 31, 13, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1,5", time.Date(2024, 2, 2, 8, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 4", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}, // 日和周满足其一即可
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := schedule.Next(base); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("Expected ParseCron(%q) to fail", expr)
+		}
+	}
+
+	// 不存在的日期没有下一次触发时间
+	schedule, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron failed: %v", err)
+	}
+	if next := schedule.Next(base); !next.IsZero() {
+		t.Errorf("Expected no next run, got %v", next)
+	}
+}
+
+// TestCronNextHalfHourOffset 测试非整小时偏移时区中按本地整点计算触发时间
+func TestCronNextHalfHourOffset(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		loc = time.FixedZone("IST", 5*3600+30*60)
+	}
+
+	schedule, err := ParseCron("0 12 * * *")
+	if err != nil {
+		t.Fatalf("ParseCron failed: %v", err)
+	}
+	base := time.Date(2024, 1, 31, 10, 17, 0, 0, loc)
+	want := time.Date(2024, 1, 31, 12, 0, 0, 0, loc)
+	if got := schedule.Next(base); !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
+
+// TestTaskWithInvalidCron 测试无效 cron 表达式使任务失败
+func TestTaskWithInvalidCron(t *testing.T) {
+	task := NewTask(
+		WithName("bad-cron"),
+		WithJob(func(ctx context.Context) error { return nil }),
+		WithCron("not a cron"),
+	)
+	task.Run()
+
+	if state := task.GetState(); state != TaskStateFailed {
+		t.Errorf("Expected state Failed, got %v", state)
+	}
+	if task.GetLastError() == nil {
+		t.Error("Expected last error to be set")
+	}
+}
@@ -0,0 +1,264 @@
+// scheduler/dag.go
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RunDAG 按依赖顺序将任务提交到工作池执行，并等待所有任务结束
+// 任务之间的依赖通过 DependsOn 或 WithDependencies 声明，未传入的依赖任务也会被一并执行。
+// 执行前检查依赖关系，存在循环依赖时返回 ErrDependencyCycle 且不提交任何任务。
+// 任务只有在其依赖全部成功完成后才会被提交；依赖以错误结束时，依赖它的任务不再执行。
+// 调用时已处于结束状态的任务不会再次执行，按其已有的结果计入。
+// ctx 结束时停止已提交但未结束的任务，不再提交其余任务，返回的错误中包含 ctx 的错误。
+// 返回值聚合了所有失败或被跳过的任务的错误，全部成功时返回 nil。
+// 返回前恢复任务原有的状态变化回调；ctx 结束时被停止的任务可能仍在执行，
+// 它们保留只转发到原回调的包装，避免与任务协程并发修改回调
+func RunDAG(ctx context.Context, pool *WorkerPool, tasks ...*Task) error {
+	all := collectDAGTasks(tasks)
+	if err := checkDependencyCycle(all); err != nil {
+		return err
+	}
+
+	// 建立反向依赖关系
+	dependents := make(map[*Task][]*Task, len(all))
+	for _, task := range all {
+		for _, dep := range task.GetDependencies() {
+			dependents[dep] = append(dependents[dep], task)
+		}
+	}
+
+	// 任务首次进入结束状态时发送通知
+	// inFlight 记录已提交但尚未结束的任务，受 mutex 保护
+	var mutex sync.Mutex
+	inFlight := make(map[*Task]bool)
+
+	finished := make(chan *Task, len(all))
+	originals := make(map[*Task]func(oldState, newState TaskState), len(all))
+	notify := make(map[*Task]func(), len(all))
+	for _, task := range all {
+		task := task
+		var once sync.Once
+		originalCallback := task.onStateChange
+		originals[task] = originalCallback
+		markFinished := func() {
+			once.Do(func() {
+				mutex.Lock()
+				delete(inFlight, task)
+				mutex.Unlock()
+				finished <- task
+			})
+		}
+		notify[task] = markFinished
+		task.onStateChange = func(oldState, newState TaskState) {
+			if originalCallback != nil {
+				originalCallback(oldState, newState)
+			}
+			if isTerminalState(newState) {
+				markFinished()
+			}
+		}
+	}
+
+	// 恢复已结束和未提交任务的状态变化回调
+	var stopped map[*Task]bool
+	defer func() {
+		for task, original := range originals {
+			if !stopped[task] {
+				task.onStateChange = original
+			}
+		}
+	}()
+
+	// submit 提交任务并记录为执行中
+	submit := func(task *Task) error {
+		mutex.Lock()
+		inFlight[task] = true
+		mutex.Unlock()
+
+		if err := pool.Submit(task); err != nil {
+			mutex.Lock()
+			delete(inFlight, task)
+			mutex.Unlock()
+			return err
+		}
+		return nil
+	}
+
+	// 调用时已结束的任务直接计入
+	preFinished := make(map[*Task]bool)
+	for _, task := range all {
+		if isTerminalState(task.GetState()) {
+			preFinished[task] = true
+			notify[task]()
+		}
+	}
+
+	var errs []error
+	succeeded := make(map[*Task]bool, len(all))
+	skipped := make(map[*Task]bool)
+	remaining := len(all)
+
+	// skipDependents 跳过 cause 的所有下游任务，它们的依赖无法成功完成
+	var skipDependents func(task *Task, cause *Task)
+	skipDependents = func(task *Task, cause *Task) {
+		for _, next := range dependents[task] {
+			if skipped[next] || preFinished[next] {
+				continue
+			}
+			skipped[next] = true
+			remaining--
+			errs = append(errs, fmt.Errorf("task %s skipped: dependency %s did not complete", next.name, cause.name))
+			skipDependents(next, cause)
+		}
+	}
+
+	// 提交没有依赖的任务
+	for _, task := range all {
+		if len(task.GetDependencies()) == 0 && !preFinished[task] {
+			if err := submit(task); err != nil {
+				return fmt.Errorf("failed to submit task %s: %w", task.name, err)
+			}
+		}
+	}
+
+	for remaining > 0 {
+		var task *Task
+		select {
+		case task = <-finished:
+		case <-ctx.Done():
+			// 停止已提交但未结束的任务
+			mutex.Lock()
+			stopped = make(map[*Task]bool, len(inFlight))
+			for task := range inFlight {
+				stopped[task] = true
+			}
+			mutex.Unlock()
+			for task := range stopped {
+				task.Stop()
+			}
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		remaining--
+
+		if err := taskEndError(task); err != nil {
+			errs = append(errs, fmt.Errorf("task %s: %w", task.name, err))
+			skipDependents(task, task)
+			continue
+		}
+		succeeded[task] = true
+
+		// 提交依赖已全部成功完成的下游任务
+		for _, next := range dependents[task] {
+			if skipped[next] || preFinished[next] || !allSucceeded(next.GetDependencies(), succeeded) {
+				continue
+			}
+			if err := submit(next); err != nil {
+				skipped[next] = true
+				remaining--
+				errs = append(errs, fmt.Errorf("failed to submit task %s: %w", next.name, err))
+				skipDependents(next, next)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// collectDAGTasks 收集任务及其所有传递依赖，保持首次出现的顺序
+func collectDAGTasks(tasks []*Task) []*Task {
+	seen := make(map[*Task]bool)
+	var all []*Task
+
+	var visit func(task *Task)
+	visit = func(task *Task) {
+		if task == nil || seen[task] {
+			return
+		}
+		seen[task] = true
+		all = append(all, task)
+		for _, dep := range task.GetDependencies() {
+			visit(dep)
+		}
+	}
+
+	for _, task := range tasks {
+		visit(task)
+	}
+	return all
+}
+
+// checkDependencyCycle 检查任务之间是否存在循环依赖，存在时返回包含循环路径的错误
+func checkDependencyCycle(tasks []*Task) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[*Task]int, len(tasks))
+	var path []*Task
+
+	var visit func(task *Task) error
+	visit = func(task *Task) error {
+		switch marks[task] {
+		case visited:
+			return nil
+		case visiting:
+			// 从路径中找到循环的起点
+			names := []string{task.name}
+			for i := len(path) - 1; i >= 0 && path[i] != task; i-- {
+				names = append(names, path[i].name)
+			}
+			names = append(names, task.name)
+			for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+				names[i], names[j] = names[j], names[i]
+			}
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, " -> "))
+		}
+
+		marks[task] = visiting
+		path = append(path, task)
+		for _, dep := range task.GetDependencies() {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[task] = visited
+		return nil
+	}
+
+	for _, task := range tasks {
+		if err := visit(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskEndError 返回任务以错误结束的原因，成功完成时返回 nil
+// 未设置 WithCancelOnFailure 的一次性任务出错后仍会进入已完成状态，因此同时检查最近一次错误
+func taskEndError(task *Task) error {
+	state := task.GetState()
+	if err := task.GetLastError(); err != nil {
+		return err
+	}
+	if state != TaskStateCompleted {
+		return fmt.Errorf("task ended in state %s", state)
+	}
+	return nil
+}
+
+// allSucceeded 检查所有任务是否都已成功完成
+func allSucceeded(tasks []*Task, succeeded map[*Task]bool) bool {
+	for _, task := range tasks {
+		if !succeeded[task] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRunDAGDiamond 测试菱形依赖按顺序执行
+func TestRunDAGDiamond(t *testing.T) {
+	pool := NewWorkerPool(4, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	var mutex sync.Mutex
+	finishedAt := make(map[string]time.Time)
+	startedAt := make(map[string]time.Time)
+	newTask := func(name string, delay time.Duration) *Task {
+		return NewTask(
+			WithName(name),
+			WithJob(func(ctx context.Context) error {
+				mutex.Lock()
+				startedAt[name] = time.Now()
+				mutex.Unlock()
+				time.Sleep(delay)
+				mutex.Lock()
+				finishedAt[name] = time.Now()
+				mutex.Unlock()
+				return nil
+			}),
+		)
+	}
+
+	a := newTask("A", 10*time.Millisecond)
+	b := newTask("B", 30*time.Millisecond).DependsOn(a)
+	c := newTask("C", 60*time.Millisecond).DependsOn(a)
+	d := newTask("D", 10*time.Millisecond).DependsOn(b, c)
+
+	// 只传入 D，依赖任务会被一并执行
+	if err := RunDAG(context.Background(), pool, d); err != nil {
+		t.Fatalf("Expected DAG to succeed, got %v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, name := range []string{"A", "B", "C", "D"} {
+		if finishedAt[name].IsZero() {
+			t.Fatalf("Expected task %s to have run", name)
+		}
+	}
+	if startedAt["B"].Before(finishedAt["A"]) || startedAt["C"].Before(finishedAt["A"]) {
+		t.Error("Expected B and C to start after A finished")
+	}
+	if startedAt["D"].Before(finishedAt["B"]) || startedAt["D"].Before(finishedAt["C"]) {
+		t.Error("Expected D to start after both B and C finished")
+	}
+}
+
+// TestRunDAGFailure 测试依赖失败时跳过下游任务并聚合错误
+func TestRunDAGFailure(t *testing.T) {
+	pool := NewWorkerPool(2, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	ran := false
+	a := NewTask(WithName("A"), WithJob(func(ctx context.Context) error {
+		return errors.New("boom")
+	}))
+	b := NewTask(WithName("B"), WithJob(func(ctx context.Context) error {
+		ran = true
+		return nil
+	})).DependsOn(a)
+
+	err := RunDAG(context.Background(), pool, a, b)
+	if err == nil {
+		t.Fatal("Expected an aggregated error, got nil")
+	}
+	if !strings.Contains(err.Error(), "boom") || !strings.Contains(err.Error(), "task B skipped") {
+		t.Errorf("Expected error to mention the failure and the skipped task, got %v", err)
+	}
+	if ran {
+		t.Error("Expected B not to run after its dependency failed")
+	}
+}
+
+// TestRunDAGCycle 测试循环依赖被拒绝
+func TestRunDAGCycle(t *testing.T) {
+	pool := NewWorkerPool(1, nil)
+
+	a := NewTask(WithName("A"))
+	b := NewTask(WithName("B")).DependsOn(a)
+	a.DependsOn(b)
+
+	err := RunDAG(context.Background(), pool, a)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("Expected ErrDependencyCycle, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "A -> B -> A") {
+		t.Errorf("Expected error to describe the cycle, got %v", err)
+	}
+}
+
+// TestRunDAGContext 测试 ctx 结束时 RunDAG 返回，停止执行中的任务并恢复未提交任务的回调
+func TestRunDAGContext(t *testing.T) {
+	pool := NewWorkerPool(2, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	a := NewTask(WithName("A"), WithJob(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(stopped)
+		return ctx.Err()
+	}))
+
+	ran := false
+	callback := func(oldState, newState TaskState) {}
+	b := NewTask(
+		WithName("B"),
+		WithJob(func(ctx context.Context) error {
+			ran = true
+			return nil
+		}),
+		WithStateChangeCallback(callback),
+	).DependsOn(a)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- RunDAG(ctx, pool, b) }()
+
+	// A 开始执行后结束 ctx
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for A to start")
+	}
+	cancel()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected RunDAG to return after the context ended")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("Expected running task to be stopped")
+	}
+	if ran {
+		t.Error("Expected B not to run after the context ended")
+	}
+	if reflect.ValueOf(b.onStateChange).Pointer() != reflect.ValueOf(callback).Pointer() {
+		t.Error("Expected the original state change callback of B to be restored")
+	}
+}
+
+// TestRunDAGAlreadyFinished 测试调用时已结束的任务按已有结果计入，不会再次执行
+func TestRunDAGAlreadyFinished(t *testing.T) {
+	pool := NewWorkerPool(2, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	var aRuns, bRuns int32
+	a := NewTask(WithName("A"), WithJob(func(ctx context.Context) error {
+		atomic.AddInt32(&aRuns, 1)
+		return nil
+	}))
+	b := NewTask(WithName("B"), WithJob(func(ctx context.Context) error {
+		atomic.AddInt32(&bRuns, 1)
+		return nil
+	})).DependsOn(a)
+
+	a.Run()
+	select {
+	case <-a.executionDone():
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for A to finish")
+	}
+	if a.GetState() != TaskStateCompleted {
+		t.Fatalf("Expected A to be completed, got %s", a.GetState())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := RunDAG(ctx, pool, b); err != nil {
+		t.Fatalf("Expected DAG to succeed, got %v", err)
+	}
+	if atomic.LoadInt32(&aRuns) != 1 || atomic.LoadInt32(&bRuns) != 1 {
+		t.Errorf("Expected A and B to run once each, got A=%d B=%d", aRuns, bRuns)
+	}
+}
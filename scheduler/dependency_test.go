@@ -3,7 +3,10 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -198,3 +201,225 @@ func TestSimplifiedAPI(t *testing.T) {
 		}
 	}
 }
+
+// TestRunAfterDelay 测试依赖完成后延迟启动
+func TestRunAfterDelay(t *testing.T) {
+	var mu sync.Mutex
+	var upstreamDone, downstreamStart time.Time
+
+	upstream := NewTask(
+		WithName("Upstream"),
+		WithJob(func(ctx context.Context) error {
+			mu.Lock()
+			upstreamDone = time.Now()
+			mu.Unlock()
+			return nil
+		}),
+	)
+
+	started := make(chan struct{})
+	downstream := NewTask(
+		WithName("Downstream"),
+		WithJob(func(ctx context.Context) error {
+			mu.Lock()
+			downstreamStart = time.Now()
+			mu.Unlock()
+			close(started)
+			return nil
+		}),
+	)
+
+	delay := 200 * time.Millisecond
+	RunAfterDelay(downstream, delay, upstream)
+
+	downstream.Run()
+	upstream.Run()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for downstream task to start")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	elapsed := downstreamStart.Sub(upstreamDone)
+	if elapsed < delay {
+		t.Errorf("Expected downstream to start at least %v after upstream, got %v", delay, elapsed)
+	}
+	if elapsed > delay+200*time.Millisecond {
+		t.Errorf("Expected downstream to start about %v after upstream, got %v", delay, elapsed)
+	}
+}
+
+// TestRunAfterDelayCancel 测试延迟期间取消任务
+func TestRunAfterDelayCancel(t *testing.T) {
+	upstream := NewTask(
+		WithName("Upstream"),
+		WithJob(func(ctx context.Context) error {
+			return nil
+		}),
+	)
+
+	executed := make(chan struct{}, 1)
+	downstream := NewTask(
+		WithName("Downstream"),
+		WithJob(func(ctx context.Context) error {
+			executed <- struct{}{}
+			return nil
+		}),
+	)
+
+	RunAfterDelay(downstream, 200*time.Millisecond, upstream)
+
+	downstream.Run()
+	upstream.Run()
+
+	// 在延迟期间停止下游任务
+	time.Sleep(50 * time.Millisecond)
+	downstream.Stop()
+
+	select {
+	case <-executed:
+		t.Error("Expected downstream not to run after being stopped during the delay")
+	case <-time.After(400 * time.Millisecond):
+	}
+
+	if state := downstream.GetState(); state != TaskStateCancelled {
+		t.Errorf("Expected downstream state to be cancelled, got %v", state)
+	}
+}
+
+// TestSequenceStopOnError 测试序列中的任务失败时取消后续任务
+func TestSequenceStopOnError(t *testing.T) {
+	for _, cancelOnFailure := range []bool{true, false} {
+		var thirdRan int32
+		first := NewTask(WithName("First"), WithJob(func(ctx context.Context) error { return nil }))
+		second := NewTask(
+			WithName("Second"),
+			WithCancelOnFailure(cancelOnFailure),
+			WithJob(func(ctx context.Context) error { return errors.New("second failed") }),
+		)
+		third := NewTask(WithName("Third"), WithJob(func(ctx context.Context) error {
+			atomic.StoreInt32(&thirdRan, 1)
+			return nil
+		}))
+
+		group := NewTaskGroup("StopOnError", nil)
+		group.AddTasks(SequenceStopOnError(first, second, third)...)
+
+		err := group.RunAndWait(2 * time.Second)
+		if errors.Is(err, ErrTimeout) {
+			t.Fatalf("cancelOnFailure=%v: sequence deadlocked", cancelOnFailure)
+		}
+		// 未设置 WithCancelOnFailure 的任务出错后仍进入已完成状态，不计入任务组的错误
+		if cancelOnFailure && err == nil {
+			t.Error("Expected the failed task to be reported by the group")
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		if state := third.GetState(); state != TaskStateCancelled {
+			t.Errorf("cancelOnFailure=%v: expected third task to be cancelled, got %v", cancelOnFailure, state)
+		}
+		if atomic.LoadInt32(&thirdRan) != 0 {
+			t.Errorf("cancelOnFailure=%v: expected third task not to run", cancelOnFailure)
+		}
+	}
+}
+
+// TestDependsOnAny 测试依赖任务失败后清理任务仍会执行
+func TestDependsOnAny(t *testing.T) {
+	upstream := NewTask(
+		WithName("Upstream"),
+		WithTaskContext(NewTaskContext()),
+		WithCancelOnFailure(true),
+		WithJob(func(ctx context.Context) error {
+			TaskFromContext(ctx).SetContextValue("resource", "tmp-dir")
+			return errors.New("upstream failed")
+		}),
+	)
+
+	cleaned := make(chan interface{}, 1)
+	cleanup := NewTask(
+		WithName("Cleanup"),
+		WithTaskContext(NewTaskContext()),
+		WithJob(func(ctx context.Context) error {
+			value, _ := TaskFromContext(ctx).GetContextValue("resource")
+			cleaned <- value
+			return nil
+		}),
+	)
+	cleanup.DependsOnAny(upstream)
+
+	var dependentRan int32
+	dependent := NewTask(WithName("Dependent"), WithJob(func(ctx context.Context) error {
+		atomic.StoreInt32(&dependentRan, 1)
+		return nil
+	}))
+	dependent.DependsOn(upstream)
+
+	cleanup.Run()
+	dependent.Run()
+	upstream.Run()
+
+	select {
+	case value := <-cleaned:
+		if value != "tmp-dir" {
+			t.Errorf("Expected context value tmp-dir to be transferred, got %v", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected cleanup task to run after upstream failure")
+	}
+
+	if state := upstream.GetState(); state != TaskStateFailed {
+		t.Errorf("Expected upstream to fail, got %v", state)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt32(&dependentRan) != 0 {
+		t.Error("Expected DependsOn dependent not to run after upstream failure")
+	}
+}
+
+// TestParallelJoinAggregatesErrors 测试汇聚任务汇总并行任务的错误
+func TestParallelJoinAggregatesErrors(t *testing.T) {
+	newChild := func(name string, err error) *Task {
+		return NewTask(
+			WithName(name),
+			WithCancelOnFailure(err != nil),
+			WithJob(func(ctx context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				return err
+			}),
+		)
+	}
+
+	a := newChild("A", nil)
+	b := newChild("B", errors.New("b failed"))
+	c := newChild("C", nil)
+
+	join := Parallel("group", a, b, c)
+	join.Run()
+	a.Run()
+	b.Run()
+	c.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for !isTerminalState(join.GetState()) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if state := join.GetState(); state != TaskStateCompleted {
+		t.Fatalf("Expected join task to complete after a child failure, got %v", state)
+	}
+	err := join.GetLastError()
+	if err == nil {
+		t.Fatal("Expected join task to report the failed child")
+	}
+	if !strings.Contains(err.Error(), "task B") || !strings.Contains(err.Error(), "b failed") {
+		t.Errorf("Expected error to name child B, got %v", err)
+	}
+	if strings.Contains(err.Error(), "task A") || strings.Contains(err.Error(), "task C") {
+		t.Errorf("Expected only the failed child in the error, got %v", err)
+	}
+}
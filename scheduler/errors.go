@@ -2,11 +2,73 @@
 package scheduler
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"time"
 )
 
 // 常见错误
 var (
 	ErrTaskNotFound = errors.New("task not found")
 	ErrTimeout      = errors.New("operation timed out")
+	ErrQueueFull    = errors.New("task queue is full")
+	ErrPoolStopped  = errors.New("worker pool is stopped")
+	ErrRateLimited  = errors.New("submission rate limit exceeded")
+
+	ErrTaskTimeout      = errors.New("task timed out")
+	ErrTaskStopped      = errors.New("task stopped")
+	ErrDeadlineExceeded = errors.New("task deadline exceeded")
+	ErrCircuitOpen      = errors.New("circuit breaker is open")
+	ErrDependencyCycle  = errors.New("dependency cycle detected")
+	ErrPrepTimeout      = errors.New("context prep hook timed out")
+	ErrCleanTimeout     = errors.New("context clean hook timed out")
+
+	// 任务配置错误，由 Task.Validate 返回
+	ErrJobNotSet       = errors.New("job is not set")
+	ErrInvalidMaxRuns  = errors.New("max runs must not be negative")
+	ErrInvalidRetry    = errors.New("retry times must not be negative")
+	ErrInvalidTimeout  = errors.New("timeout out of range")
+	ErrInvalidInterval = errors.New("repeat interval must be positive")
 )
+
+// StatusError 表示带有状态码（例如 HTTP 状态码）的任务错误
+// 任务函数返回或包装 StatusError 后，可以使用 RetryOnStatusCodes 按状态码决定是否重试
+type StatusError struct {
+	Code int   // 状态码
+	Err  error // 原始错误，可以为 nil
+}
+
+// Error 实现 error 接口
+func (e *StatusError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("status %d: %v", e.Code, e.Err)
+	}
+	return fmt.Sprintf("status %d", e.Code)
+}
+
+// Unwrap 返回原始错误
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError 表示任务单次执行超过了 WithTimeout 设置的超时时间
+// errors.Is(err, ErrTaskTimeout) 和 errors.Is(err, context.DeadlineExceeded) 都成立
+type TimeoutError struct {
+	Timeout time.Duration // 超时时间
+}
+
+// Error 实现 error 接口
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("task timed out after %v: %v", e.Timeout, context.DeadlineExceeded)
+}
+
+// Is 使 errors.Is(err, ErrTaskTimeout) 成立
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTaskTimeout
+}
+
+// Unwrap 返回 context.DeadlineExceeded
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
@@ -2,6 +2,8 @@
 package scheduler
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 )
@@ -19,6 +21,13 @@ type TaskGroup struct {
 	// 组级别的回调函数
 	onAllCompleted func()
 	onAnyFailed    func([]*Task)
+	onProgress     func(completed, total int)
+
+	// 并发限制，slots 为 nil 时不限制
+	slots   chan struct{}  // 信号量，容量为最大并发数
+	pending []*Task        // 等待槽位的任务
+	holding map[*Task]bool // 当前占用槽位的任务
+	running bool           // 是否已调用 RunAll
 }
 
 // NewTaskGroup 创建新的任务组
@@ -35,10 +44,21 @@ func NewTaskGroup(name string, logger Logger) *TaskGroup {
 	}
 }
 
+// NewTaskGroupWithConcurrency 创建限制最大并发数的任务组
+// RunAll 时最多同时运行 maxConcurrent 个任务，其余任务在有任务结束后按添加顺序启动
+func NewTaskGroupWithConcurrency(name string, logger Logger, maxConcurrent int) *TaskGroup {
+	tg := NewTaskGroup(name, logger)
+	if maxConcurrent > 0 {
+		tg.slots = make(chan struct{}, maxConcurrent)
+		tg.holding = make(map[*Task]bool)
+	}
+	return tg
+}
+
 // AddTask 添加任务到组
+// 对于限制并发的任务组，在 RunAll 之后添加的任务也会被调度
 func (tg *TaskGroup) AddTask(task *Task) *TaskGroup {
 	tg.mutex.Lock()
-	defer tg.mutex.Unlock()
 
 	// 将任务添加到组
 	tg.tasks = append(tg.tasks, task)
@@ -56,10 +76,30 @@ func (tg *TaskGroup) AddTask(task *Task) *TaskGroup {
 			originalCallback(oldState, newState)
 		}
 
+		// 任务结束时释放并发槽位
+		if isTerminalState(newState) {
+			tg.releaseSlot(task)
+
+			// 只在任务首次进入结束状态时报告进度
+			if !isTerminalState(oldState) {
+				tg.reportProgress()
+			}
+		}
+
 		// 检查组内所有任务是否完成
 		tg.checkGroupCompletion()
 	}
 
+	schedule := tg.running && tg.slots != nil
+	if schedule {
+		tg.pending = append(tg.pending, task)
+	}
+	tg.mutex.Unlock()
+
+	if schedule {
+		tg.schedule()
+	}
+
 	return tg
 }
 
@@ -86,30 +126,140 @@ func (tg *TaskGroup) GetContextValue(key string) (interface{}, bool) {
 	return tg.context.Get(key)
 }
 
-// RunAll 启动组内所有任务
-func (tg *TaskGroup) RunAll() {
+// snapshotTasks 获取组内任务列表的副本
+// 任务的状态变化回调会获取组的锁，因此启动或停止任务时不能持有锁
+func (tg *TaskGroup) snapshotTasks() []*Task {
 	tg.mutex.RLock()
 	defer tg.mutex.RUnlock()
 
+	tasks := make([]*Task, len(tg.tasks))
+	copy(tasks, tg.tasks)
+	return tasks
+}
+
+// RunAll 启动组内所有任务
+func (tg *TaskGroup) RunAll() {
 	tg.logger.Info("Starting all tasks in group: %s", tg.name)
 
-	for _, task := range tg.tasks {
+	if tg.slots == nil {
+		for _, task := range tg.snapshotTasks() {
+			task.Run()
+		}
+		return
+	}
+
+	tg.mutex.Lock()
+	tg.running = true
+	tg.pending = append(tg.pending, tg.tasks...)
+	tg.mutex.Unlock()
+
+	tg.schedule()
+}
+
+// schedule 在有空闲槽位时按顺序启动等待中的任务
+func (tg *TaskGroup) schedule() {
+	for {
+		tg.mutex.Lock()
+		if len(tg.pending) == 0 {
+			tg.mutex.Unlock()
+			return
+		}
+
+		select {
+		case tg.slots <- struct{}{}:
+		default:
+			// 没有空闲槽位，等待有任务结束
+			tg.mutex.Unlock()
+			return
+		}
+
+		task := tg.pending[0]
+		tg.pending[0] = nil
+		tg.pending = tg.pending[1:]
+
+		// 等待期间已被停止的任务不再启动
+		if task.ctx.Err() != nil {
+			<-tg.slots
+			tg.mutex.Unlock()
+			continue
+		}
+		tg.holding[task] = true
+		tg.mutex.Unlock()
+
+		// 在锁外启动任务，任务的状态回调会获取组的锁
 		task.Run()
+
+		// 依赖未满足的任务不会立即运行，释放槽位避免组内任务相互等待
+		if task.GetState() == TaskStateIdle {
+			tg.releaseSlot(task)
+		}
+	}
+}
+
+// releaseSlot 释放任务占用的槽位并调度下一个任务
+func (tg *TaskGroup) releaseSlot(task *Task) {
+	if tg.slots == nil {
+		return
+	}
+
+	tg.mutex.Lock()
+	held := tg.holding[task]
+	if held {
+		delete(tg.holding, task)
+		<-tg.slots
+	}
+	tg.mutex.Unlock()
+
+	if held {
+		tg.schedule()
+	}
+}
+
+// isTerminalState 检查任务状态是否为结束状态
+func isTerminalState(state TaskState) bool {
+	return state == TaskStateCompleted || state == TaskStateFailed || state == TaskStateCancelled
+}
+
+// RunAllWithPool 将组内所有任务提交到工作池执行，由工作池限制并发数
+func (tg *TaskGroup) RunAllWithPool(pool *WorkerPool) {
+	tg.logger.Info("Submitting all tasks in group %s to worker pool", tg.name)
+
+	for _, task := range tg.snapshotTasks() {
+		if err := pool.Submit(task); err != nil {
+			tg.logger.Error("Failed to submit task %s in group %s: %v", task.name, tg.name, err)
+		}
 	}
 }
 
 // StopAll 停止组内所有任务
 func (tg *TaskGroup) StopAll() {
-	tg.mutex.RLock()
-	defer tg.mutex.RUnlock()
-
 	tg.logger.Info("Stopping all tasks in group: %s", tg.name)
 
-	for _, task := range tg.tasks {
+	for _, task := range tg.snapshotTasks() {
 		task.Stop()
 	}
 }
 
+// StopAllAndWait 停止组内所有任务，并等待它们的执行协程退出
+// 超过 timeout 仍有任务未退出时返回 ErrTimeout，这些任务会在之后自行退出
+func (tg *TaskGroup) StopAllAndWait(timeout time.Duration) error {
+	tasks := tg.snapshotTasks()
+	tg.StopAll()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for _, task := range tasks {
+		select {
+		case <-task.executionDone():
+		case <-timer.C:
+			tg.logger.Warn("Timed out waiting for tasks in group %s to stop", tg.name)
+			return fmt.Errorf("%w: tasks in group %s did not stop within %v", ErrTimeout, tg.name, timeout)
+		}
+	}
+	return nil
+}
+
 // GetGroupStats 获取组的统计信息
 func (tg *TaskGroup) GetGroupStats() (total, running, completed, failed int) {
 	tg.mutex.RLock()
@@ -132,15 +282,57 @@ func (tg *TaskGroup) GetGroupStats() (total, running, completed, failed int) {
 	return
 }
 
-// OnAllCompleted 设置所有任务完成时的回调
-func (tg *TaskGroup) OnAllCompleted(callback func()) *TaskGroup {
+// Progress 返回已结束的任务数和任务总数
+// 已完成、失败和取消的任务都计为已结束
+func (tg *TaskGroup) Progress() (completed, total int) {
+	tg.mutex.RLock()
+	defer tg.mutex.RUnlock()
+
+	return tg.progressLocked()
+}
+
+// progressLocked 在已获取锁的情况下统计任务进度
+func (tg *TaskGroup) progressLocked() (completed, total int) {
+	for _, task := range tg.tasks {
+		if isTerminalState(task.GetState()) {
+			completed++
+		}
+	}
+	return completed, len(tg.tasks)
+}
+
+// WithProgressCallback 设置进度回调，组内任务每进入一次结束状态时调用
+func (tg *TaskGroup) WithProgressCallback(callback func(completed, total int)) *TaskGroup {
 	tg.mutex.Lock()
 	defer tg.mutex.Unlock()
 
+	tg.onProgress = callback
+	return tg
+}
+
+// reportProgress 调用进度回调
+func (tg *TaskGroup) reportProgress() {
+	tg.mutex.RLock()
+	onProgress := tg.onProgress
+	completed, total := tg.progressLocked()
+	tg.mutex.RUnlock()
+
+	if onProgress != nil {
+		onProgress(completed, total)
+	}
+}
+
+// OnAllCompleted 设置所有任务完成时的回调
+func (tg *TaskGroup) OnAllCompleted(callback func()) *TaskGroup {
+	tg.mutex.Lock()
 	tg.onAllCompleted = callback
 
 	// 检查是否已经全部完成
-	if tg.areAllTasksCompleted() && callback != nil {
+	allCompleted := tg.areAllTasksCompletedLocked()
+	tg.mutex.Unlock()
+
+	// 在锁外调用回调，允许回调访问任务组
+	if allCompleted && callback != nil {
 		callback()
 	}
 
@@ -150,12 +342,12 @@ func (tg *TaskGroup) OnAllCompleted(callback func()) *TaskGroup {
 // OnAnyFailed 设置任何任务失败时的回调
 func (tg *TaskGroup) OnAnyFailed(callback func([]*Task)) *TaskGroup {
 	tg.mutex.Lock()
-	defer tg.mutex.Unlock()
-
 	tg.onAnyFailed = callback
 
 	// 检查是否已经有失败的任务
-	failedTasks := tg.getFailedTasks()
+	failedTasks := tg.getFailedTasksLocked()
+	tg.mutex.Unlock()
+
 	if len(failedTasks) > 0 && callback != nil {
 		callback(failedTasks)
 	}
@@ -163,19 +355,27 @@ func (tg *TaskGroup) OnAnyFailed(callback func([]*Task)) *TaskGroup {
 	return tg
 }
 
+// Errors 返回组内所有失败任务的最后一次错误
+func (tg *TaskGroup) Errors() []error {
+	var errs []error
+	for _, task := range tg.getFailedTasks() {
+		if err := task.GetLastError(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
 // RunAndWait 运行所有任务并等待完成
+// 所有任务结束后返回由失败任务错误合并而成的错误，超时返回 ErrTimeout
 func (tg *TaskGroup) RunAndWait(timeout time.Duration) error {
 	// 创建完成通知通道
 	done := make(chan struct{})
-	var groupErr error
+	var doneOnce sync.Once
 
 	// 设置完成回调
 	tg.OnAllCompleted(func() {
-		close(done)
-	}).OnAnyFailed(func(failedTasks []*Task) {
-		if len(failedTasks) > 0 {
-			groupErr = failedTasks[0].GetLastError()
-		}
+		doneOnce.Do(func() { close(done) })
 	})
 
 	// 启动所有任务
@@ -184,7 +384,8 @@ func (tg *TaskGroup) RunAndWait(timeout time.Duration) error {
 	// 等待完成或超时
 	select {
 	case <-done:
-		return groupErr
+		// 合并所有失败任务的错误，没有失败时返回 nil
+		return errors.Join(tg.Errors()...)
 	case <-time.After(timeout):
 		tg.StopAll()
 		return ErrTimeout
@@ -193,18 +394,22 @@ func (tg *TaskGroup) RunAndWait(timeout time.Duration) error {
 
 // checkGroupCompletion 检查组内所有任务是否完成
 func (tg *TaskGroup) checkGroupCompletion() {
-	tg.mutex.Lock()
-	defer tg.mutex.Unlock()
+	tg.mutex.RLock()
+	failedTasks := tg.getFailedTasksLocked()
+	allCompleted := tg.areAllTasksCompletedLocked()
+	onAnyFailed := tg.onAnyFailed
+	onAllCompleted := tg.onAllCompleted
+	tg.mutex.RUnlock()
 
+	// 在锁外调用回调，避免回调访问任务组时死锁
 	// 检查是否有失败的任务
-	failedTasks := tg.getFailedTasksLocked()
-	if len(failedTasks) > 0 && tg.onAnyFailed != nil {
-		tg.onAnyFailed(failedTasks)
+	if len(failedTasks) > 0 && onAnyFailed != nil {
+		onAnyFailed(failedTasks)
 	}
 
 	// 检查是否所有任务都完成了
-	if tg.areAllTasksCompletedLocked() && tg.onAllCompleted != nil {
-		tg.onAllCompleted()
+	if allCompleted && onAllCompleted != nil {
+		onAllCompleted()
 	}
 }
 
@@ -219,8 +424,7 @@ func (tg *TaskGroup) areAllTasksCompleted() bool {
 // areAllTasksCompletedLocked 在已获取锁的情况下检查是否所有任务都已完成
 func (tg *TaskGroup) areAllTasksCompletedLocked() bool {
 	for _, task := range tg.tasks {
-		state := task.GetState()
-		if state != TaskStateCompleted && state != TaskStateFailed && state != TaskStateCancelled {
+		if !isTerminalState(task.GetState()) {
 			return false
 		}
 	}
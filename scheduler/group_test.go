@@ -0,0 +1,282 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyTracker 记录任务的并发执行情况
+type concurrencyTracker struct {
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+}
+
+// job 返回一个记录并发数并运行指定时间的任务函数
+func (c *concurrencyTracker) job(d time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		c.mu.Lock()
+		c.running++
+		if c.running > c.maxRunning {
+			c.maxRunning = c.running
+		}
+		c.mu.Unlock()
+
+		time.Sleep(d)
+
+		c.mu.Lock()
+		c.running--
+		c.mu.Unlock()
+		return nil
+	}
+}
+
+// max 返回观察到的最大并发数
+func (c *concurrencyTracker) max() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxRunning
+}
+
+// TestTaskGroupRunAllWithPool 测试通过工作池运行任务组
+func TestTaskGroupRunAllWithPool(t *testing.T) {
+	pool := NewWorkerPool(2, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	tracker := &concurrencyTracker{}
+	group := NewTaskGroup("PoolGroup", nil)
+	for i := 0; i < 6; i++ {
+		group.AddTask(NewTask(
+			WithName(fmt.Sprintf("GroupTask-%d", i)),
+			WithJob(tracker.job(50*time.Millisecond)),
+		))
+	}
+
+	completed := make(chan struct{})
+	var once sync.Once
+	group.OnAllCompleted(func() {
+		once.Do(func() { close(completed) })
+	})
+
+	group.RunAllWithPool(pool)
+
+	select {
+	case <-completed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for group to complete")
+	}
+
+	if max := tracker.max(); max > 2 {
+		t.Errorf("Expected concurrency to stay at or below 2, got %d", max)
+	}
+
+	total, _, completedCount, _ := group.GetGroupStats()
+	if completedCount != total {
+		t.Errorf("Expected all %d tasks to be completed, got %d", total, completedCount)
+	}
+}
+
+// TestTaskGroupWithConcurrency 测试任务组自身的并发限制
+func TestTaskGroupWithConcurrency(t *testing.T) {
+	tracker := &concurrencyTracker{}
+	group := NewTaskGroupWithConcurrency("LimitedGroup", nil, 2)
+	for i := 0; i < 5; i++ {
+		group.AddTask(NewTask(
+			WithName(fmt.Sprintf("GroupTask-%d", i)),
+			WithJob(tracker.job(50*time.Millisecond)),
+		))
+	}
+
+	completed := make(chan struct{})
+	var once sync.Once
+	group.OnAllCompleted(func() {
+		once.Do(func() { close(completed) })
+	})
+
+	group.RunAll()
+
+	// RunAll 之后添加的任务也应被调度
+	group.AddTask(NewTask(
+		WithName("GroupTask-5"),
+		WithJob(tracker.job(50*time.Millisecond)),
+	))
+
+	select {
+	case <-completed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for group to complete")
+	}
+
+	if max := tracker.max(); max > 2 {
+		t.Errorf("Expected concurrency to stay at or below 2, got %d", max)
+	}
+
+	total, _, completedCount, _ := group.GetGroupStats()
+	if total != 6 || completedCount != total {
+		t.Errorf("Expected all 6 tasks to be completed, got %d of %d", completedCount, total)
+	}
+}
+
+// TestTaskGroupRunAndWaitErrors 测试 RunAndWait 合并所有失败任务的错误
+func TestTaskGroupRunAndWaitErrors(t *testing.T) {
+	errA := errors.New("task A failed")
+	errB := errors.New("task B failed")
+
+	newJob := func(err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return err
+		}
+	}
+
+	group := NewTaskGroup("ErrorGroup", nil)
+	group.AddTasks(
+		NewTask(WithName("TaskA"), WithJob(newJob(errA)), WithCancelOnFailure(true)),
+		NewTask(WithName("TaskB"), WithJob(newJob(errB)), WithCancelOnFailure(true)),
+		NewTask(WithName("TaskC"), WithJob(newJob(nil))),
+	)
+
+	err := group.RunAndWait(2 * time.Second)
+	if err == nil {
+		t.Fatal("Expected RunAndWait to return an error, got nil")
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Expected joined error to contain both failures, got %v", err)
+	}
+
+	if errs := group.Errors(); len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %d: %v", len(errs), errs)
+	}
+}
+
+// TestTaskGroupProgress 测试任务组进度报告
+func TestTaskGroupProgress(t *testing.T) {
+	group := NewTaskGroup("ProgressGroup", nil)
+
+	var mu sync.Mutex
+	var reports [][2]int
+	group.WithProgressCallback(func(completed, total int) {
+		mu.Lock()
+		reports = append(reports, [2]int{completed, total})
+		mu.Unlock()
+	})
+
+	// 每个任务等待释放信号，保证逐个结束
+	releases := make([]chan struct{}, 3)
+	for i := range releases {
+		release := make(chan struct{})
+		releases[i] = release
+		group.AddTask(NewTask(
+			WithName(fmt.Sprintf("ProgressTask-%d", i)),
+			WithJob(func(ctx context.Context) error {
+				<-release
+				return nil
+			}),
+		))
+	}
+
+	group.RunAll()
+
+	if completed, total := group.Progress(); completed != 0 || total != 3 {
+		t.Errorf("Expected progress 0/3, got %d/%d", completed, total)
+	}
+
+	for i, release := range releases {
+		close(release)
+
+		deadline := time.Now().Add(time.Second)
+		for {
+			if completed, _ := group.Progress(); completed == i+1 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Timeout waiting for task %d to finish", i)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// 等待最后一次回调
+	time.Sleep(20 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	expected := [][2]int{{1, 3}, {2, 3}, {3, 3}}
+	if len(reports) != len(expected) {
+		t.Fatalf("Expected %d progress reports, got %d: %v", len(expected), len(reports), reports)
+	}
+	for i, report := range reports {
+		if report != expected[i] {
+			t.Errorf("Expected report %d to be %v, got %v", i, expected[i], report)
+		}
+	}
+}
+
+// TestTaskGroupStopAllAndWait 测试停止任务组并等待任务退出
+func TestTaskGroupStopAllAndWait(t *testing.T) {
+	group := NewTaskGroup("StopAndWait", nil)
+
+	var exited int32
+	started := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		group.AddTask(NewTask(
+			WithName(fmt.Sprintf("Worker%d", i)),
+			WithJob(func(ctx context.Context) error {
+				started <- struct{}{}
+				<-ctx.Done()
+				time.Sleep(20 * time.Millisecond) // 模拟收尾工作
+				atomic.AddInt32(&exited, 1)
+				return ctx.Err()
+			}),
+		))
+	}
+	// 从未启动的任务不影响等待
+	group.AddTask(NewTask(WithName("Idle"), WithJob(func(ctx context.Context) error { return nil })))
+
+	for _, task := range group.tasks[:2] {
+		task.Run()
+	}
+	for i := 0; i < 2; i++ {
+		<-started
+	}
+
+	start := time.Now()
+	if err := group.StopAllAndWait(time.Second); err != nil {
+		t.Fatalf("Expected StopAllAndWait to succeed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected StopAllAndWait to return promptly, took %v", elapsed)
+	}
+	if n := atomic.LoadInt32(&exited); n != 2 {
+		t.Errorf("Expected both jobs to have exited, got %d", n)
+	}
+	for _, task := range group.tasks {
+		if !isTerminalState(task.GetState()) {
+			t.Errorf("Expected task %s in a terminal state, got %v", task.GetName(), task.GetState())
+		}
+	}
+
+	// 不响应取消的任务导致超时
+	stuck := NewTaskGroup("Stuck", nil)
+	release := make(chan struct{})
+	defer close(release)
+	running := make(chan struct{})
+	task := NewTask(WithName("Stuck"), WithJob(func(ctx context.Context) error {
+		close(running)
+		<-release
+		return nil
+	}))
+	stuck.AddTask(task)
+	task.Run()
+	<-running
+	if err := stuck.StopAllAndWait(50 * time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+}
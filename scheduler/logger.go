@@ -1,6 +1,14 @@
 // scheduler/logger.go
 package scheduler
 
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
 // Logger 定义了日志接口，支持不同级别的日志记录
 type Logger interface {
 	// Debug 记录调试级别的日志
@@ -65,3 +73,116 @@ func (l *FuncLogger) Error(format string, args ...any) {
 func NewFuncLogger(logFunc func(format string, args ...any)) Logger {
 	return &FuncLogger{logFunc: logFunc}
 }
+
+// JSONLogger 以 JSON 格式输出日志，每行一个包含 level、msg 和 time 字段的对象
+// 适用于需要由日志收集系统解析的场景
+type JSONLogger struct {
+	w     io.Writer
+	mutex sync.Mutex // 保证并发写入时每行日志完整
+}
+
+// jsonLogEntry 是一行 JSON 日志
+type jsonLogEntry struct {
+	Level string    `json:"level"`
+	Msg   string    `json:"msg"`
+	Time  time.Time `json:"time"`
+}
+
+// NewJSONLogger 创建一个将 JSON 日志写入 w 的 Logger
+func NewJSONLogger(w io.Writer) Logger {
+	return &JSONLogger{w: w}
+}
+
+func (l *JSONLogger) Debug(format string, args ...any) {
+	l.log(LevelDebug, format, args...)
+}
+
+func (l *JSONLogger) Info(format string, args ...any) {
+	l.log(LevelInfo, format, args...)
+}
+
+func (l *JSONLogger) Warn(format string, args ...any) {
+	l.log(LevelWarn, format, args...)
+}
+
+func (l *JSONLogger) Error(format string, args ...any) {
+	l.log(LevelError, format, args...)
+}
+
+// log 格式化消息并写入一行 JSON 日志
+func (l *JSONLogger) log(level Level, format string, args ...any) {
+	data, err := json.Marshal(jsonLogEntry{
+		Level: level.String(),
+		Msg:   fmt.Sprintf(format, args...),
+		Time:  time.Now(),
+	})
+	if err != nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.w.Write(append(data, '\n'))
+}
+
+// Level 表示日志级别
+type Level int
+
+// 日志级别常量，按严重程度递增
+const (
+	LevelDebug Level = iota // 调试
+	LevelInfo               // 信息
+	LevelWarn               // 警告
+	LevelError              // 错误
+)
+
+// String 返回日志级别的名称
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// LeveledLogger 包装另一个 Logger，丢弃低于最低级别的日志
+type LeveledLogger struct {
+	inner Logger
+	min   Level
+}
+
+// NewLeveledLogger 创建只输出不低于 min 级别日志的 Logger
+func NewLeveledLogger(inner Logger, min Level) Logger {
+	return &LeveledLogger{inner: inner, min: min}
+}
+
+func (l *LeveledLogger) Debug(format string, args ...any) {
+	if l.min <= LevelDebug {
+		l.inner.Debug(format, args...)
+	}
+}
+
+func (l *LeveledLogger) Info(format string, args ...any) {
+	if l.min <= LevelInfo {
+		l.inner.Info(format, args...)
+	}
+}
+
+func (l *LeveledLogger) Warn(format string, args ...any) {
+	if l.min <= LevelWarn {
+		l.inner.Warn(format, args...)
+	}
+}
+
+func (l *LeveledLogger) Error(format string, args ...any) {
+	if l.min <= LevelError {
+		l.inner.Error(format, args...)
+	}
+}
@@ -1,8 +1,14 @@
 package scheduler
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // TestDefaultLogger 测试默认日志记录器
@@ -141,3 +147,86 @@ func TestTaskWithLoggerFunc(t *testing.T) {
 		t.Error("Expected task.logger to be a FuncLogger, but it wasn't")
 	}
 }
+
+// TestJSONLogger 测试 JSON 日志记录器的输出格式
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf)
+
+	before := time.Now()
+	logger.Debug("debug %d", 1)
+	logger.Info("task %s started", "backup")
+	logger.Warn("queue %s", "full")
+	logger.Error("failed: %v", "boom \"quoted\"")
+
+	want := []struct{ level, msg string }{
+		{"debug", "debug 1"},
+		{"info", "task backup started"},
+		{"warn", "queue full"},
+		{"error", `failed: boom "quoted"`},
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	for i, w := range want {
+		if !scanner.Scan() {
+			t.Fatalf("Expected %d log lines, got %d", len(want), i)
+		}
+
+		var entry struct {
+			Level string    `json:"level"`
+			Msg   string    `json:"msg"`
+			Time  time.Time `json:"time"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Line %d is not valid JSON: %v (%s)", i, err, scanner.Text())
+		}
+		if entry.Level != w.level || entry.Msg != w.msg {
+			t.Errorf("Line %d: expected level %q and msg %q, got %q and %q", i, w.level, w.msg, entry.Level, entry.Msg)
+		}
+		if entry.Time.Before(before.Add(-time.Second)) {
+			t.Errorf("Line %d: unexpected time %v", i, entry.Time)
+		}
+	}
+	if scanner.Scan() {
+		t.Errorf("Unexpected extra log line: %s", scanner.Text())
+	}
+}
+
+// recordingLogger 记录收到的日志级别和消息
+type recordingLogger struct {
+	entries []string
+}
+
+func (l *recordingLogger) Debug(format string, args ...any) { l.record(LevelDebug, format, args...) }
+func (l *recordingLogger) Info(format string, args ...any)  { l.record(LevelInfo, format, args...) }
+func (l *recordingLogger) Warn(format string, args ...any)  { l.record(LevelWarn, format, args...) }
+func (l *recordingLogger) Error(format string, args ...any) { l.record(LevelError, format, args...) }
+
+func (l *recordingLogger) record(level Level, format string, args ...any) {
+	l.entries = append(l.entries, level.String()+": "+fmt.Sprintf(format, args...))
+}
+
+// TestLeveledLogger 测试按最低级别过滤日志
+func TestLeveledLogger(t *testing.T) {
+	inner := &recordingLogger{}
+	logger := NewLeveledLogger(inner, LevelWarn)
+
+	logger.Debug("debug %d", 1)
+	logger.Info("info %d", 2)
+	logger.Warn("warn %d", 3)
+	logger.Error("error %d", 4)
+
+	want := []string{"warn: warn 3", "error: error 4"}
+	if !reflect.DeepEqual(inner.entries, want) {
+		t.Errorf("Expected %v, got %v", want, inner.entries)
+	}
+
+	// 最低级别为 Debug 时全部输出
+	inner.entries = nil
+	logger = NewLeveledLogger(inner, LevelDebug)
+	logger.Debug("a")
+	logger.Info("b")
+	if len(inner.entries) != 2 {
+		t.Errorf("Expected all entries to pass through, got %v", inner.entries)
+	}
+}
@@ -3,6 +3,7 @@ package scheduler
 
 import (
 	"context"
+	"slices"
 	"time"
 )
 
@@ -17,6 +18,19 @@ func WithName(name string) TaskOption {
 func WithJob(job func(context.Context) error) TaskOption {
 	return func(t *Task) {
 		t.job = job
+		t.resultJob = nil
+	}
+}
+
+// WithResultJob 设置返回结果的任务主体函数
+// 返回的结果记录在 JobResult.Value 中传给指标收集器，成功时可以通过 LastResult 获取
+func WithResultJob(job ResultJob) TaskOption {
+	return func(t *Task) {
+		t.resultJob = job
+		t.job = func(ctx context.Context) error {
+			_, err := job(ctx)
+			return err
+		}
 	}
 }
 
@@ -27,10 +41,48 @@ func WithTimeout(timeout time.Duration) TaskOption {
 	}
 }
 
+// WithDeadline 设置任务整体的截止时间
+// 所有迭代和重试共享这一绝对时间，超过截止时间后任务停止执行并以 ErrDeadlineExceeded 失败
+func WithDeadline(deadline time.Time) TaskOption {
+	return func(t *Task) {
+		t.deadline = deadline
+		t.ctx, t.cancelFunc = t.withDeadline(t.ctx, t.cancelFunc)
+	}
+}
+
+// WithRunUntil 设置周期性任务的结束时间
+// 与 WithDeadline 不同，正在进行的执行不会被中断：每次执行结束后，如果下一次执行时间不早于 until，
+// 任务不再等待而是正常进入已完成状态
+func WithRunUntil(until time.Time) TaskOption {
+	return func(t *Task) {
+		t.runUntil = until
+	}
+}
+
 // WithRepeat 设置任务以固定间隔重复执行
 func WithRepeat(interval time.Duration) TaskOption {
 	return func(t *Task) {
 		t.interval = interval
+		t.repeating = true
+	}
+}
+
+// WithFixedRateFrom 设置任务以锚点对齐的固定频率重复执行
+// 每次执行都在 anchor + k*interval 的时间点开始，不受任务执行时长影响，
+// 执行时间超过间隔时跳过错过的时间点。首次执行仍在任务启动时立即进行
+func WithFixedRateFrom(anchor time.Time, interval time.Duration) TaskOption {
+	return func(t *Task) {
+		t.rateAnchor = anchor
+		t.interval = interval
+		t.repeating = true
+	}
+}
+
+// WithCron 设置任务按 cron 表达式（分 时 日 月 周）重复执行
+// 首次执行也等待到下一个匹配的时间点；表达式无效时任务运行即失败
+func WithCron(expr string) TaskOption {
+	return func(t *Task) {
+		t.cron, t.cronErr = ParseCron(expr)
 	}
 }
 
@@ -58,6 +110,14 @@ func WithRetryStrategy(strategy RetryStrategy) TaskOption {
 	}
 }
 
+// WithContextRollbackOnRetry 设置重试前是否回滚任务上下文
+// 启用后，每次执行开始时对任务上下文做快照，重试前恢复快照，使失败的尝试对上下文的修改不影响下一次尝试
+func WithContextRollbackOnRetry(rollback bool) TaskOption {
+	return func(t *Task) {
+		t.rollbackOnRetry = rollback
+	}
+}
+
 // 移除 WithParallelism 选项
 
 // WithLogger 自定义日志记录器
@@ -109,6 +169,15 @@ func WithErrorHandler(handler func(error)) TaskOption {
 	}
 }
 
+// WithOnComplete 设置任务结束时的回调
+// 任务进入已完成、失败或已取消状态时调用一次（包括任务 panic 的情况），
+// state 为结束状态，lastErr 为最近一次错误。调用 Reset 后任务再次结束时会重新调用
+func WithOnComplete(callback func(state TaskState, lastErr error)) TaskOption {
+	return func(t *Task) {
+		t.onComplete = callback
+	}
+}
+
 // WithCancelOnFailure 设置失败时是否取消任务
 func WithCancelOnFailure(cancel bool) TaskOption {
 	return func(t *Task) {
@@ -123,6 +192,64 @@ func WithMetricCollector(collector func(JobResult)) TaskOption {
 	}
 }
 
+// WithCondition 设置任务的执行条件
+// 每次执行前调用 cond，返回 false 时跳过本次执行（不调用任务函数、不计入运行次数），
+// 周期性任务继续等待下一次执行
+func WithCondition(cond func(ctx context.Context) bool) TaskOption {
+	return func(t *Task) {
+		t.condition = cond
+	}
+}
+
+// WithCircuitBreaker 为任务设置熔断器
+// 熔断器断开期间任务函数不会被调用，本次执行以 ErrCircuitOpen 失败且不再重试
+func WithCircuitBreaker(cb *CircuitBreaker) TaskOption {
+	return func(t *Task) {
+		t.circuitBreaker = cb
+	}
+}
+
+// WithRateLimit 限制任务函数的调用速率
+// 每次调用任务函数（包括重试）前等待令牌，令牌以每秒 eventsPerSecond 个的速率生成，最多积累 burst 个。
+// 等待期间任务停止时本次执行以上下文错误结束；eventsPerSecond 不大于 0 时不限制
+func WithRateLimit(eventsPerSecond float64, burst int) TaskOption {
+	return func(t *Task) {
+		t.rateLimiter = newRateLimitBucket(eventsPerSecond, burst)
+	}
+}
+
+// WithMaxConcurrentRuns 允许周期性任务的各次执行重叠，但最多同时进行 n 次
+// 设置后任务函数在独立协程中执行，到达下一次执行时间时不必等待上一次执行结束；
+// 并发执行数达到 n 时按 WithOverlapPolicy 设置的策略等待或跳过。n <= 0 表示各次执行依次进行
+func WithMaxConcurrentRuns(n int) TaskOption {
+	return func(t *Task) {
+		if n <= 0 {
+			t.maxConcurrent = 0
+			t.runSlots = nil
+			return
+		}
+		t.maxConcurrent = n
+		t.runSlots = make(chan struct{}, n)
+	}
+}
+
+// WithOverlapPolicy 设置并发执行数达到 WithMaxConcurrentRuns 上限时的处理策略，默认为 OverlapWait
+func WithOverlapPolicy(policy OverlapPolicy) TaskOption {
+	return func(t *Task) {
+		t.overlapPolicy = policy
+	}
+}
+
+// WithTraceHooks 设置每次执行任务函数前后调用的追踪钩子
+// start 在每次尝试执行前调用，返回的上下文会传给任务函数，可用于创建追踪 span；
+// end 在每次尝试结束后以同一个上下文和执行错误调用。可以借此接入 OpenTelemetry 等追踪系统
+func WithTraceHooks(start func(ctx context.Context, name string) context.Context, end func(ctx context.Context, err error)) TaskOption {
+	return func(t *Task) {
+		t.traceStart = start
+		t.traceEnd = end
+	}
+}
+
 // WithPriority 设置任务优先级
 func WithPriority(priority Priority) TaskOption {
 	return func(t *Task) {
@@ -130,6 +257,26 @@ func WithPriority(priority Priority) TaskOption {
 	}
 }
 
+// WithTags 为任务添加标签，多次调用时标签会累加，重复的标签只保留一个
+func WithTags(tags ...string) TaskOption {
+	return func(t *Task) {
+		for _, tag := range tags {
+			if !slices.Contains(t.tags, tag) {
+				t.tags = append(t.tags, tag)
+			}
+		}
+	}
+}
+
+// WithResultCache 缓存最近一次成功的执行结果
+// 在 ttl 有效期内的执行（包括再次调用 Run 和周期性任务的后续执行）不调用任务函数，而是直接使用缓存结果，
+// 运行次数、钩子和指标照常记录
+func WithResultCache(ttl time.Duration) TaskOption {
+	return func(t *Task) {
+		t.resultCacheTTL = ttl
+	}
+}
+
 // WithSync 设置任务是否同步执行
 func WithSync(sync bool) TaskOption {
 	return func(t *Task) {
@@ -183,6 +330,31 @@ func WithContextClean(clean func(*TaskContext)) TaskOption {
 	}
 }
 
+// WithContextCleanReason 设置带结束原因的上下文清理钩子
+// 任务完成、失败（状态为 TaskStateFailed）或被取消时调用，reason 表示结束原因。
+// 可以与 WithContextClean 同时使用，两者都会被调用
+func WithContextCleanReason(clean func(ctx *TaskContext, reason CleanupReason)) TaskOption {
+	return func(t *Task) {
+		t.contextCleanReason = clean
+	}
+}
+
+// WithPrepTimeout 设置上下文准备钩子的超时时间
+// 准备钩子超过 d 仍未返回时任务不再等待，以 ErrPrepTimeout 失败。d 不大于 0 时不限制
+func WithPrepTimeout(d time.Duration) TaskOption {
+	return func(t *Task) {
+		t.prepTimeout = d
+	}
+}
+
+// WithCleanTimeout 设置上下文清理钩子的超时时间
+// 清理钩子超过 d 仍未返回时任务不再等待，只记录错误日志，不改变任务的结束状态。d 不大于 0 时不限制
+func WithCleanTimeout(d time.Duration) TaskOption {
+	return func(t *Task) {
+		t.cleanTimeout = d
+	}
+}
+
 // WithContextValue 设置上下文值
 func WithContextValue(key string, value interface{}) TaskOption {
 	return func(t *Task) {
@@ -10,13 +10,15 @@ import (
 type TaskItem struct {
 	task     *Task
 	priority Priority
-	index    int // 在堆中的索引，由 heap.Interface 维护
+	seq      uint64 // 入队序号，同优先级的任务按入队顺序出队
+	index    int    // 在堆中的索引，由 heap.Interface 维护
 }
 
 // PriorityQueue 实现了一个基于优先级的任务队列
 type PriorityQueue struct {
-	items []*TaskItem
-	mutex sync.Mutex
+	items   []*TaskItem
+	nextSeq uint64 // 下一个入队序号
+	mutex   sync.Mutex
 }
 
 // Len 返回队列长度
@@ -26,8 +28,12 @@ func (pq *PriorityQueue) Len() int {
 
 // Less 比较两个任务的优先级
 // 注意：我们希望 Pop 返回最高优先级的任务，所以使用 > 而不是 <
+// 优先级相同时先入队的任务先出队
 func (pq *PriorityQueue) Less(i, j int) bool {
-	return pq.items[i].priority > pq.items[j].priority
+	if pq.items[i].priority != pq.items[j].priority {
+		return pq.items[i].priority > pq.items[j].priority
+	}
+	return pq.items[i].seq < pq.items[j].seq
 }
 
 // Swap 交换两个任务的位置
@@ -73,7 +79,9 @@ func (pq *PriorityQueue) Enqueue(task *Task) {
 	item := &TaskItem{
 		task:     task,
 		priority: task.priority,
+		seq:      pq.nextSeq,
 	}
+	pq.nextSeq++
 	heap.Push(pq, item)
 }
 
@@ -90,6 +98,69 @@ func (pq *PriorityQueue) Dequeue() *Task {
 	return item.task
 }
 
+// Peek 返回队列中优先级最高的任务但不将其移出队列，队列为空时返回 nil
+func (pq *PriorityQueue) Peek() *Task {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	if pq.Len() == 0 {
+		return nil
+	}
+	return pq.items[0].task
+}
+
+// Remove 从队列中移除指定的任务，返回任务是否在队列中
+// 同一个任务被多次入队时只移除其中一个
+func (pq *PriorityQueue) Remove(task *Task) bool {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	for _, item := range pq.items {
+		if item.task == task {
+			heap.Remove(pq, item.index)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveWhere 从队列中移除所有满足条件的任务，返回被移除的任务
+// pred 在持有队列锁时调用，不能再调用队列的方法
+func (pq *PriorityQueue) RemoveWhere(pred func(*Task) bool) []*Task {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	var removed []*Task
+	kept := pq.items[:0]
+	for _, item := range pq.items {
+		if pred(item.task) {
+			item.index = -1 // 标记为已移除
+			removed = append(removed, item.task)
+			continue
+		}
+		item.index = len(kept)
+		kept = append(kept, item)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	// 清除尾部的引用，避免内存泄漏
+	for i := len(kept); i < len(pq.items); i++ {
+		pq.items[i] = nil
+	}
+	pq.items = kept
+	heap.Init(pq)
+	return removed
+}
+
+// Size 返回队列中的任务数量（线程安全）
+func (pq *PriorityQueue) Size() int {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+	return pq.Len()
+}
+
 // IsEmpty 检查队列是否为空
 func (pq *PriorityQueue) IsEmpty() bool {
 	pq.mutex.Lock()
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"testing"
 )
 
@@ -117,6 +118,84 @@ func TestPriorityQueuePriority(t *testing.T) {
 	}
 }
 
+// TestPriorityQueueFIFO 测试同优先级的任务按入队顺序出队
+func TestPriorityQueueFIFO(t *testing.T) {
+	pq := NewPriorityQueue()
+
+	tasks := make([]*Task, 5)
+	for i := range tasks {
+		tasks[i] = NewTask(WithName(fmt.Sprintf("Task%d", i)), WithPriority(PriorityNormal))
+		pq.Enqueue(tasks[i])
+	}
+	// 更高优先级的任务仍然先出队
+	high := NewTask(WithName("High"), WithPriority(PriorityHigh))
+	pq.Enqueue(high)
+
+	if task := pq.Dequeue(); task != high {
+		t.Fatalf("Expected High first, got %s", task.GetName())
+	}
+	for i, want := range tasks {
+		if task := pq.Dequeue(); task != want {
+			t.Errorf("Expected %s at position %d, got %s", want.GetName(), i, task.GetName())
+		}
+	}
+}
+
+// TestPriorityQueuePeek 测试查看队首任务
+func TestPriorityQueuePeek(t *testing.T) {
+	pq := NewPriorityQueue()
+	if pq.Peek() != nil {
+		t.Error("Expected Peek on an empty queue to return nil")
+	}
+
+	pq.Enqueue(NewTask(WithName("Low"), WithPriority(PriorityLow)))
+	high := NewTask(WithName("High"), WithPriority(PriorityHigh))
+	pq.Enqueue(high)
+
+	peeked := pq.Peek()
+	if peeked != high {
+		t.Errorf("Expected Peek to return High, got %v", peeked.GetName())
+	}
+	if pq.Size() != 2 {
+		t.Errorf("Expected Peek not to remove the task, got size %d", pq.Size())
+	}
+	if task := pq.Dequeue(); task != peeked {
+		t.Errorf("Expected Dequeue to return the peeked task, got %v", task.GetName())
+	}
+}
+
+// TestPriorityQueueRemove 测试移除队列中的任务
+func TestPriorityQueueRemove(t *testing.T) {
+	pq := NewPriorityQueue()
+
+	low := NewTask(WithName("Low"), WithPriority(PriorityLow))
+	normal := NewTask(WithName("Normal"), WithPriority(PriorityNormal))
+	high := NewTask(WithName("High"), WithPriority(PriorityHigh))
+	pq.Enqueue(low)
+	pq.Enqueue(normal)
+	pq.Enqueue(high)
+
+	if !pq.Remove(normal) {
+		t.Fatal("Expected Remove to find the queued task")
+	}
+	if pq.Remove(normal) {
+		t.Error("Expected second Remove of the same task to return false")
+	}
+	if pq.Size() != 2 {
+		t.Errorf("Expected 2 tasks after remove, got %d", pq.Size())
+	}
+
+	if task := pq.Dequeue(); task != high {
+		t.Errorf("Expected High first, got %v", task.GetName())
+	}
+	if task := pq.Dequeue(); task != low {
+		t.Errorf("Expected Low second, got %v", task.GetName())
+	}
+	if !pq.IsEmpty() {
+		t.Error("Expected queue to be empty")
+	}
+}
+
 // TestPriorityQueueConcurrency 测试并发安全性
 func TestPriorityQueueConcurrency(t *testing.T) {
 	pq := NewPriorityQueue()
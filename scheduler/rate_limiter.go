@@ -0,0 +1,95 @@
+// scheduler/rate_limiter.go
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket 是一个简单的令牌桶限流器
+// 桶容量为 rate，每 per/rate 时间生成一个令牌，允许最多 rate 个请求的突发
+type tokenBucket struct {
+	mutex    sync.Mutex
+	capacity float64       // 桶容量
+	tokens   float64       // 当前令牌数
+	interval time.Duration // 生成一个令牌所需的时间
+	last     time.Time     // 上次补充令牌的时间
+}
+
+// newTokenBucket 创建每 per 时间允许 rate 次的令牌桶，参数无效时返回 nil
+func newTokenBucket(rate int, per time.Duration) *tokenBucket {
+	if rate <= 0 || per <= 0 {
+		return nil
+	}
+
+	return &tokenBucket{
+		capacity: float64(rate),
+		tokens:   float64(rate),
+		interval: per / time.Duration(rate),
+		last:     time.Now(),
+	}
+}
+
+// newRateLimitBucket 创建每秒生成 eventsPerSecond 个令牌、容量为 burst 的令牌桶，参数无效时返回 nil
+func newRateLimitBucket(eventsPerSecond float64, burst int) *tokenBucket {
+	if eventsPerSecond <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1 // 至少允许一次执行
+	}
+
+	return &tokenBucket{
+		capacity: float64(burst),
+		tokens:   float64(burst),
+		interval: time.Duration(float64(time.Second) / eventsPerSecond),
+		last:     time.Now(),
+	}
+}
+
+// reserve 尝试取出一个令牌，成功时返回 0，否则返回距离下一个令牌的等待时间
+func (b *tokenBucket) reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// 按经过的时间补充令牌
+	now := time.Now()
+	if b.interval > 0 {
+		b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) * float64(b.interval))
+}
+
+// take 非阻塞地取出一个令牌
+func (b *tokenBucket) take() bool {
+	return b.reserve() == 0
+}
+
+// wait 阻塞直到取得一个令牌或上下文被取消
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		delay := b.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// scheduler/registry.go
+package scheduler
+
+import (
+	"sort"
+	"sync"
+)
+
+// Scheduler 是进程内按名称管理任务的注册表
+// 与 CLI 使用的任务管理器不同，它不持久化任务，适合在代码中直接管理一组长期运行的任务
+type Scheduler struct {
+	tasks  map[string]*Task
+	mutex  sync.RWMutex
+	logger Logger
+}
+
+// NewScheduler 创建一个新的任务注册表，logger 为 nil 时使用默认日志记录器
+func NewScheduler(logger Logger) *Scheduler {
+	if logger == nil {
+		logger = defaultLoggerInstance
+	}
+
+	return &Scheduler{
+		tasks:  make(map[string]*Task),
+		logger: logger,
+	}
+}
+
+// Register 以 name 注册任务，已有同名任务时替换它，被替换的任务不会被停止
+func (s *Scheduler) Register(name string, t *Task) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.tasks[name]; exists {
+		s.logger.Warn("Task %s is already registered, replacing it", name)
+	}
+	s.tasks[name] = t
+}
+
+// Unregister 注销任务并返回它，任务不存在时返回 false；任务不会被停止
+func (s *Scheduler) Unregister(name string) (*Task, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t, exists := s.tasks[name]
+	delete(s.tasks, name)
+	return t, exists
+}
+
+// Get 按名称获取已注册的任务
+func (s *Scheduler) Get(name string) (*Task, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	t, exists := s.tasks[name]
+	return t, exists
+}
+
+// List 返回所有已注册任务的名称，按名称排序
+func (s *Scheduler) List() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	names := make([]string, 0, len(s.tasks))
+	for name := range s.tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartAll 启动所有未在运行的已注册任务，暂停的任务保持暂停
+func (s *Scheduler) StartAll() {
+	s.logger.Info("Starting all registered tasks")
+
+	for _, t := range s.snapshot() {
+		// 暂停的任务需要通过 Resume 恢复，不在这里重新启动
+		if state := t.GetState(); state != TaskStateRunning && state != TaskStatePaused {
+			t.Run()
+		}
+	}
+}
+
+// StopAll 停止所有已注册的任务，任务仍然保持注册
+func (s *Scheduler) StopAll() {
+	s.logger.Info("Stopping all registered tasks")
+
+	for _, t := range s.snapshot() {
+		t.Stop()
+	}
+}
+
+// snapshot 返回已注册任务的副本，启动和停止任务时不持有锁，避免任务回调访问注册表时死锁
+func (s *Scheduler) snapshot() []*Task {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tasks := make([]*Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
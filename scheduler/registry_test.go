@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSchedulerRegistry 测试注册、查找、启动、停止和列出任务
+func TestSchedulerRegistry(t *testing.T) {
+	s := NewScheduler(nil)
+
+	var runsA, runsB int32
+	newTask := func(counter *int32) *Task {
+		return NewTask(
+			WithJob(func(ctx context.Context) error {
+				atomic.AddInt32(counter, 1)
+				return nil
+			}),
+			WithRepeat(10*time.Millisecond),
+		)
+	}
+	taskA, taskB := newTask(&runsA), newTask(&runsB)
+	s.Register("b", taskB)
+	s.Register("a", taskA)
+
+	if names := s.List(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", names)
+	}
+	if got, ok := s.Get("a"); !ok || got != taskA {
+		t.Error("Expected Get to return the registered task")
+	}
+	if _, ok := s.Get("missing"); ok {
+		t.Error("Expected Get of an unknown name to fail")
+	}
+
+	s.StartAll()
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&runsA) == 0 || atomic.LoadInt32(&runsB) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timeout waiting for registered tasks to run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	s.StopAll()
+	for name, task := range map[string]*Task{"a": taskA, "b": taskB} {
+		if state := task.GetState(); state != TaskStateCancelled {
+			t.Errorf("Expected task %s to be cancelled, got %v", name, state)
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	stoppedA := atomic.LoadInt32(&runsA)
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&runsA); n != stoppedA {
+		t.Errorf("Expected no runs after StopAll, got %d more", n-stoppedA)
+	}
+
+	// 注销后不再出现在列表中
+	if got, ok := s.Unregister("b"); !ok || got != taskB {
+		t.Error("Expected Unregister to return the task")
+	}
+	if names := s.List(); !reflect.DeepEqual(names, []string{"a"}) {
+		t.Errorf("Expected [a] after unregister, got %v", names)
+	}
+}
+
+// TestSchedulerStartAllSkipsPaused 测试 StartAll 不会重新启动暂停的任务
+func TestSchedulerStartAllSkipsPaused(t *testing.T) {
+	s := NewScheduler(nil)
+	task := NewTask(
+		WithJob(func(ctx context.Context) error { return nil }),
+		WithRepeat(10*time.Millisecond),
+	)
+	s.Register("paused", task)
+	defer s.StopAll()
+
+	task.Run()
+	if !task.Pause() {
+		t.Fatal("Expected running task to be paused")
+	}
+
+	s.StartAll()
+	if state := task.GetState(); state != TaskStatePaused {
+		t.Errorf("Expected StartAll to leave the paused task paused, got %v", state)
+	}
+	if !task.Resume() {
+		t.Error("Expected paused task to be resumable after StartAll")
+	}
+}
@@ -0,0 +1,181 @@
+// scheduler/report.go
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// String 返回任务状态的名称
+func (s TaskState) String() string {
+	switch s {
+	case TaskStateIdle:
+		return "idle"
+	case TaskStateRunning:
+		return "running"
+	case TaskStatePaused:
+		return "paused"
+	case TaskStateCompleted:
+		return "completed"
+	case TaskStateFailed:
+		return "failed"
+	case TaskStateCancelled:
+		return "cancelled"
+	default:
+		return fmt.Sprintf("TaskState(%d)", int(s))
+	}
+}
+
+// TaskReport 是任务完整配置和当前状态的快照，用于诊断
+type TaskReport struct {
+	Name            string        `json:"name"`
+	State           string        `json:"state"`
+	Priority        Priority      `json:"priority"`
+	Timeout         time.Duration `json:"timeout"`
+	Interval        time.Duration `json:"interval"`
+	FixedRateAnchor time.Time     `json:"fixed_rate_anchor,omitempty"`
+	MaxRuns         int           `json:"max_runs"`
+	RetryTimes      int           `json:"retry_times"`
+	RetryStrategy   string        `json:"retry_strategy,omitempty"`
+	MaxRetries      int           `json:"max_retries"`
+	StartupDelay    time.Duration `json:"startup_delay"`
+	DependencyDelay time.Duration `json:"dependency_delay"`
+	ResultCacheTTL  time.Duration `json:"result_cache_ttl"`
+	CancelOnFailure bool          `json:"cancel_on_failure"`
+	Sync            bool          `json:"sync"`
+	Hooks           []string      `json:"hooks"`
+	Dependencies    []string      `json:"dependencies"`
+	ContextKeys     []string      `json:"context_keys"`
+	RunCount        int           `json:"run_count"`
+	LastRunTime     time.Time     `json:"last_run_time"`
+	LastError       string        `json:"last_error,omitempty"`
+}
+
+// Report 返回任务的诊断快照
+func (t *Task) Report() TaskReport {
+	report := TaskReport{
+		Name:            t.name,
+		State:           t.GetState().String(),
+		Priority:        t.priority,
+		Timeout:         t.timeout,
+		Interval:        t.getInterval(),
+		FixedRateAnchor: t.rateAnchor,
+		MaxRuns:         t.maxRuns,
+		RetryTimes:      t.retryTimes,
+		MaxRetries:      t.retryTimes,
+		StartupDelay:    t.startupDelay,
+		DependencyDelay: t.dependencyDelay,
+		ResultCacheTTL:  t.resultCacheTTL,
+		CancelOnFailure: t.cancelOnErr,
+		Sync:            t.syncExec,
+		Hooks:           t.configuredHooks(),
+		Dependencies:    make([]string, 0),
+		ContextKeys:     make([]string, 0),
+		RunCount:        t.GetRunCount(),
+		LastRunTime:     t.GetLastRunTime(),
+	}
+
+	if t.retryStrategy != nil {
+		report.RetryStrategy = fmt.Sprintf("%T", t.retryStrategy)
+		report.MaxRetries = t.retryStrategy.MaxRetries()
+	}
+
+	for _, dep := range t.GetDependencies() {
+		report.Dependencies = append(report.Dependencies, dep.name)
+	}
+
+	if t.taskContext != nil {
+		for key := range t.taskContext.GetAll() {
+			report.ContextKeys = append(report.ContextKeys, key)
+		}
+		sort.Strings(report.ContextKeys)
+	}
+
+	if err := t.GetLastError(); err != nil {
+		report.LastError = err.Error()
+	}
+
+	return report
+}
+
+// configuredHooks 返回已设置的钩子名称
+func (t *Task) configuredHooks() []string {
+	hooks := make([]string, 0)
+	if t.preHook != nil {
+		hooks = append(hooks, "pre")
+	}
+	if t.postHook != nil {
+		hooks = append(hooks, "post")
+	}
+	if t.errorHandler != nil {
+		hooks = append(hooks, "error")
+	}
+	if t.onComplete != nil {
+		hooks = append(hooks, "complete")
+	}
+	if t.recoverHook != nil {
+		hooks = append(hooks, "recover")
+	}
+	if t.metricCollector != nil {
+		hooks = append(hooks, "metrics")
+	}
+	if t.condition != nil {
+		hooks = append(hooks, "condition")
+	}
+	if t.circuitBreaker != nil {
+		hooks = append(hooks, "circuit-breaker")
+	}
+	if t.rateLimiter != nil {
+		hooks = append(hooks, "rate-limit")
+	}
+	if t.traceStart != nil || t.traceEnd != nil {
+		hooks = append(hooks, "trace")
+	}
+	if t.contextPrep != nil {
+		hooks = append(hooks, "context-prep")
+	}
+	if t.contextClean != nil || t.contextCleanReason != nil {
+		hooks = append(hooks, "context-clean")
+	}
+	return hooks
+}
+
+// Describe 返回任务配置和状态的可读描述，便于排查问题
+func (t *Task) Describe() string {
+	r := t.Report()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Task: %s\n", r.Name)
+	fmt.Fprintf(&b, "  State:            %s\n", r.State)
+	fmt.Fprintf(&b, "  Priority:         %d\n", r.Priority)
+	fmt.Fprintf(&b, "  Timeout:          %v\n", r.Timeout)
+	fmt.Fprintf(&b, "  Interval:         %v\n", r.Interval)
+	if !r.FixedRateAnchor.IsZero() {
+		fmt.Fprintf(&b, "  Fixed rate from:  %s\n", r.FixedRateAnchor.Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "  Max runs:         %d\n", r.MaxRuns)
+	if r.RetryStrategy != "" {
+		fmt.Fprintf(&b, "  Retry strategy:   %s (max retries: %d)\n", r.RetryStrategy, r.MaxRetries)
+	} else {
+		fmt.Fprintf(&b, "  Retry times:      %d\n", r.RetryTimes)
+	}
+	fmt.Fprintf(&b, "  Startup delay:    %v\n", r.StartupDelay)
+	fmt.Fprintf(&b, "  Dependency delay: %v\n", r.DependencyDelay)
+	fmt.Fprintf(&b, "  Result cache TTL: %v\n", r.ResultCacheTTL)
+	fmt.Fprintf(&b, "  Cancel on error:  %v\n", r.CancelOnFailure)
+	fmt.Fprintf(&b, "  Sync:             %v\n", r.Sync)
+	fmt.Fprintf(&b, "  Hooks:            %s\n", strings.Join(r.Hooks, ", "))
+	fmt.Fprintf(&b, "  Dependencies:     %s\n", strings.Join(r.Dependencies, ", "))
+	fmt.Fprintf(&b, "  Context keys:     %s\n", strings.Join(r.ContextKeys, ", "))
+	fmt.Fprintf(&b, "  Run count:        %d\n", r.RunCount)
+	if !r.LastRunTime.IsZero() {
+		fmt.Fprintf(&b, "  Last run:         %s\n", r.LastRunTime.Format(time.RFC3339))
+	}
+	if r.LastError != "" {
+		fmt.Fprintf(&b, "  Last error:       %s\n", r.LastError)
+	}
+
+	return b.String()
+}
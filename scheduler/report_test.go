@@ -0,0 +1,60 @@
+// scheduler/report_test.go
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTaskDescribe 测试任务诊断报告
+func TestTaskDescribe(t *testing.T) {
+	upstream := NewTask(WithName("Upstream"))
+
+	task := NewTask(
+		WithName("ReportTask"),
+		WithJob(func(ctx context.Context) error {
+			return nil
+		}),
+		WithTimeout(3*time.Second),
+		WithRepeat(time.Minute),
+		WithMaxRuns(10),
+		WithPriority(PriorityHigh),
+		WithRetryStrategy(NewExponentialBackoffRetryStrategy(time.Second, time.Minute, 2, 4)),
+		WithStartupDelay(500*time.Millisecond),
+		WithPreHook(func() {}),
+		WithErrorHandler(func(error) {}),
+		WithContextValue("region", "eu"),
+		WithContextValue("env", "prod"),
+		WithDependencies(upstream),
+	)
+
+	report := task.Report()
+	if report.Name != "ReportTask" || report.State != "idle" || report.Priority != PriorityHigh {
+		t.Errorf("Unexpected basic attributes: %+v", report)
+	}
+	if report.Timeout != 3*time.Second || report.Interval != time.Minute || report.MaxRuns != 10 {
+		t.Errorf("Unexpected scheduling attributes: %+v", report)
+	}
+	if report.MaxRetries != 4 || !strings.Contains(report.RetryStrategy, "ExponentialBackoffRetryStrategy") {
+		t.Errorf("Unexpected retry attributes: strategy=%s maxRetries=%d", report.RetryStrategy, report.MaxRetries)
+	}
+	if !reflect.DeepEqual(report.Dependencies, []string{"Upstream"}) {
+		t.Errorf("Expected dependencies [Upstream], got %v", report.Dependencies)
+	}
+	if !reflect.DeepEqual(report.ContextKeys, []string{"env", "region"}) {
+		t.Errorf("Expected context keys [env region], got %v", report.ContextKeys)
+	}
+	if !reflect.DeepEqual(report.Hooks, []string{"pre", "error"}) {
+		t.Errorf("Expected hooks [pre error], got %v", report.Hooks)
+	}
+
+	description := task.Describe()
+	for _, expected := range []string{"ReportTask", "idle", "1m0s", "max retries: 4", "Upstream", "env, region"} {
+		if !strings.Contains(description, expected) {
+			t.Errorf("Expected description to contain %q, got:\n%s", expected, description)
+		}
+	}
+}
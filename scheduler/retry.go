@@ -5,6 +5,7 @@ import (
 	"errors"
 	"math"
 	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -185,3 +186,138 @@ func (s *ExponentialBackoffRetryStrategy) ShouldRetry(err error) bool {
 func (s *ExponentialBackoffRetryStrategy) MaxRetries() int {
 	return s.maxRetries
 }
+
+// DecorrelatedJitterRetryStrategy 去相关抖动重试策略
+// 每次延迟为 min(maxDelay, random_between(baseDelay, lastSleep*3))，lastSleep 为上一次的延迟，
+// 相比普通的指数退避加抖动，多个任务同时失败时重试时间更分散。
+// 策略会记录上一次的延迟，第 0 次重试时重新从 baseDelay 开始。
+// 方法可以并发调用，但多个任务共享同一个实例时延迟序列会相互影响，建议每个任务使用独立的实例
+type DecorrelatedJitterRetryStrategy struct {
+	baseDelay       time.Duration
+	maxDelay        time.Duration
+	maxRetries      int
+	retryableErrors []error
+	retryPredicate  func(error) bool
+
+	mutex     sync.Mutex
+	lastSleep time.Duration // 上一次的延迟
+}
+
+// NewDecorrelatedJitterRetryStrategy 创建去相关抖动重试策略
+func NewDecorrelatedJitterRetryStrategy(baseDelay, maxDelay time.Duration, maxRetries int) *DecorrelatedJitterRetryStrategy {
+	if maxDelay < baseDelay {
+		maxDelay = baseDelay
+	}
+
+	return &DecorrelatedJitterRetryStrategy{
+		baseDelay:  baseDelay,
+		maxDelay:   maxDelay,
+		maxRetries: maxRetries,
+		lastSleep:  baseDelay,
+	}
+}
+
+// WithRetryableErrors 设置可重试的错误类型
+func (s *DecorrelatedJitterRetryStrategy) WithRetryableErrors(errors ...error) *DecorrelatedJitterRetryStrategy {
+	s.retryableErrors = errors
+	return s
+}
+
+// WithRetryPredicate 设置自定义重试判断函数
+func (s *DecorrelatedJitterRetryStrategy) WithRetryPredicate(predicate func(error) bool) *DecorrelatedJitterRetryStrategy {
+	s.retryPredicate = predicate
+	return s
+}
+
+// NextRetryDelay 实现 RetryStrategy 接口
+func (s *DecorrelatedJitterRetryStrategy) NextRetryDelay(attempt int, err error) time.Duration {
+	if attempt >= s.maxRetries {
+		return 0 // 不再重试
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// 新一轮重试从基础延迟开始
+	if attempt == 0 {
+		s.lastSleep = s.baseDelay
+	}
+
+	delay := s.baseDelay
+	if upper := s.lastSleep * 3; upper > s.baseDelay {
+		delay += time.Duration(rand.Int63n(int64(upper - s.baseDelay)))
+	}
+
+	// 确保不超过最大延迟
+	if delay > s.maxDelay {
+		delay = s.maxDelay
+	}
+
+	s.lastSleep = delay
+	return delay
+}
+
+// ShouldRetry 实现 RetryStrategy 接口
+func (s *DecorrelatedJitterRetryStrategy) ShouldRetry(err error) bool {
+	// 如果错误为空，不需要重试
+	if err == nil {
+		return false
+	}
+
+	// 如果有自定义重试判断函数，使用它
+	if s.retryPredicate != nil {
+		return s.retryPredicate(err)
+	}
+
+	// 如果没有指定可重试的错误类型，则所有错误都可重试
+	if len(s.retryableErrors) == 0 {
+		return true
+	}
+
+	// 检查错误是否在可重试列表中
+	for _, retryableErr := range s.retryableErrors {
+		if errors.Is(err, retryableErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaxRetries 实现 RetryStrategy 接口
+func (s *DecorrelatedJitterRetryStrategy) MaxRetries() int {
+	return s.maxRetries
+}
+
+// statusCodeRetryStrategy 按状态码判断是否重试的重试策略，延迟和最大重试次数沿用被包装的策略
+type statusCodeRetryStrategy struct {
+	RetryStrategy
+	codes map[int]bool
+}
+
+// RetryOnStatusCodes 包装重试策略，按 StatusError 的状态码判断是否重试
+// 错误链中包含 StatusError 时，只有状态码属于 codes 才重试；未指定 codes 时重试 429 和所有 5xx 状态码。
+// 错误不是 StatusError 时由被包装的策略决定是否重试
+func RetryOnStatusCodes(strategy RetryStrategy, codes ...int) RetryStrategy {
+	s := &statusCodeRetryStrategy{
+		RetryStrategy: strategy,
+		codes:         make(map[int]bool, len(codes)),
+	}
+	for _, code := range codes {
+		s.codes[code] = true
+	}
+	return s
+}
+
+// ShouldRetry 实现 RetryStrategy 接口
+func (s *statusCodeRetryStrategy) ShouldRetry(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return s.RetryStrategy.ShouldRetry(err)
+	}
+
+	if len(s.codes) == 0 {
+		return statusErr.Code == 429 || (statusErr.Code >= 500 && statusErr.Code < 600)
+	}
+	return s.codes[statusErr.Code]
+}
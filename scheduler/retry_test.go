@@ -103,6 +103,45 @@ func TestExponentialBackoffRetryStrategy(t *testing.T) {
 	}
 }
 
+// TestDecorrelatedJitterRetryStrategy 测试去相关抖动重试策略
+func TestDecorrelatedJitterRetryStrategy(t *testing.T) {
+	base, maxDelay := 10*time.Millisecond, 200*time.Millisecond
+	strategy := NewDecorrelatedJitterRetryStrategy(base, maxDelay, 8)
+
+	var runs [][]time.Duration
+	for run := 0; run < 5; run++ {
+		var delays []time.Duration
+		for attempt := 0; attempt < strategy.MaxRetries(); attempt++ {
+			delay := strategy.NextRetryDelay(attempt, ErrTemporary)
+			if delay < base || delay > maxDelay {
+				t.Fatalf("Expected delay within [%v, %v], got %v", base, maxDelay, delay)
+			}
+			delays = append(delays, delay)
+		}
+		runs = append(runs, delays)
+	}
+
+	// 延迟应在每轮重试之间有所不同
+	varied := false
+	for _, delays := range runs[1:] {
+		for i := range delays {
+			if delays[i] != runs[0][i] {
+				varied = true
+			}
+		}
+	}
+	if !varied {
+		t.Error("Expected delays to vary between runs")
+	}
+
+	if delay := strategy.NextRetryDelay(8, ErrTemporary); delay != 0 {
+		t.Errorf("Expected delay 0 for attempt >= maxRetries, got %v", delay)
+	}
+	if !strategy.ShouldRetry(ErrTemporary) || strategy.ShouldRetry(nil) {
+		t.Error("Expected all non-nil errors to be retryable by default")
+	}
+}
+
 // TestTaskWithRetryStrategy 测试任务使用重试策略
 func TestTaskWithRetryStrategy(t *testing.T) {
 	// 创建一个计数器，用于跟踪任务执行次数
@@ -173,3 +212,57 @@ func TestRetryOnNetworkError(t *testing.T) {
 		t.Error("Expected non-network error to be non-retryable")
 	}
 }
+
+// TestRetryOnStatusCodes 测试按状态码判断是否重试
+func TestRetryOnStatusCodes(t *testing.T) {
+	base := NewFixedDelayRetryStrategy(10*time.Millisecond, 3).WithRetryableErrors(ErrTemporary)
+	strategy := RetryOnStatusCodes(base, 429, 503)
+
+	if !strategy.ShouldRetry(&StatusError{Code: 503}) {
+		t.Error("Expected status 503 to be retryable")
+	}
+	if !strategy.ShouldRetry(fmt.Errorf("request failed: %w", &StatusError{Code: 429, Err: ErrPermanent})) {
+		t.Error("Expected wrapped status 429 to be retryable")
+	}
+	if strategy.ShouldRetry(&StatusError{Code: 400}) {
+		t.Error("Expected status 400 to be non-retryable")
+	}
+
+	// 非 StatusError 由被包装的策略决定
+	if !strategy.ShouldRetry(ErrTemporary) {
+		t.Error("Expected wrapped strategy to retry ErrTemporary")
+	}
+	if strategy.ShouldRetry(ErrPermanent) {
+		t.Error("Expected wrapped strategy to reject ErrPermanent")
+	}
+	if strategy.MaxRetries() != 3 {
+		t.Errorf("Expected max retries of wrapped strategy, got %d", strategy.MaxRetries())
+	}
+
+	// 未指定状态码时重试 429 和 5xx
+	defaults := RetryOnStatusCodes(base)
+	if !defaults.ShouldRetry(&StatusError{Code: 500}) || !defaults.ShouldRetry(&StatusError{Code: 429}) {
+		t.Error("Expected 5xx and 429 to be retryable by default")
+	}
+	if defaults.ShouldRetry(&StatusError{Code: 404}) {
+		t.Error("Expected 404 to be non-retryable by default")
+	}
+
+	// 任务按状态码重试
+	attempts := 0
+	task := NewTask(
+		WithName("StatusRetry"),
+		WithRetryStrategy(strategy),
+		WithJob(func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return &StatusError{Code: 503}
+			}
+			return &StatusError{Code: 400}
+		}),
+	)
+	task.RunOnce(context.Background())
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts (503 retried, 400 not), got %d", attempts)
+	}
+}
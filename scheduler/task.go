@@ -3,8 +3,10 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,15 +15,27 @@ import (
 // 使用标准库的 log 包，便于默认 logger 实现
 var stdLog = log.Printf
 
+// taskIDCounter 用于为每个任务分配唯一的标识
+var taskIDCounter uint64
+
+// nextTaskID 返回一个进程内唯一的任务标识
+func nextTaskID() string {
+	return strconv.FormatUint(atomic.AddUint64(&taskIDCounter, 1), 10)
+}
+
 // Job 定义任务函数
 type Job func(ctx context.Context) error
 
+// ResultJob 定义返回结果的任务函数
+type ResultJob func(ctx context.Context) (interface{}, error)
+
 // JobResult 用于记录任务执行结果
 type JobResult struct {
 	Name     string
 	Duration time.Duration
 	Success  bool
 	Err      error
+	Value    interface{} // 任务函数返回的结果，仅在使用 WithResultJob 时设置
 }
 
 // TaskOption 是配置任务的函数类型
@@ -38,6 +52,37 @@ const (
 
 // 移除 ResourceLimits 结构体
 
+// OverlapPolicy 定义周期性任务并发执行数达到上限时的处理策略
+type OverlapPolicy int
+
+const (
+	OverlapWait OverlapPolicy = iota // 等待有执行结束后再开始本次执行
+	OverlapSkip                      // 跳过本次执行，等待下一次
+)
+
+// CleanupReason 表示执行上下文清理钩子时任务结束的原因
+type CleanupReason int
+
+const (
+	CleanupCompleted CleanupReason = iota // 任务已完成
+	CleanupFailed                         // 任务执行失败
+	CleanupCancelled                      // 任务被取消
+)
+
+// String 返回清理原因的名称
+func (r CleanupReason) String() string {
+	switch r {
+	case CleanupCompleted:
+		return "completed"
+	case CleanupFailed:
+		return "failed"
+	case CleanupCancelled:
+		return "cancelled"
+	default:
+		return fmt.Sprintf("CleanupReason(%d)", int(r))
+	}
+}
+
 // TaskState 表示任务的状态
 type TaskState int
 
@@ -52,32 +97,70 @@ const (
 
 // Task 表示一个可配置的任务
 type Task struct {
+	id              string // 进程内唯一的任务标识，与存储层的任务ID无关
 	name            string
 	job             Job
+	resultJob       ResultJob // 返回结果的任务函数，设置后代替 job 执行
 	timeout         time.Duration
 	interval        time.Duration
+	repeating       bool          // 是否通过 WithRepeat 或 WithFixedRateFrom 设置了重复执行
+	rateAnchor      time.Time     // 固定频率调度的锚点，零值表示按固定间隔调度
+	deadline        time.Time     // 任务整体的截止时间，零值表示不限制
+	runUntil        time.Time     // 周期性任务的结束时间，零值表示不限制
+	cron            *CronSchedule // cron 调度计划，设置后按 cron 时间点执行
+	cronErr         error         // cron 表达式解析错误
 	maxRuns         int
 	retryTimes      int
 	startupDelay    time.Duration
 	preHook         func()
 	postHook        func()
 	errorHandler    func(error)
+	onComplete      func(state TaskState, lastErr error) // 任务进入结束状态时的回调
 	cancelOnErr     bool
 	logger          Logger
 	recoverHook     func(any)
 	metricCollector func(JobResult)
-	priority        Priority // 任务优先级
-	syncExec        bool     // 是否同步执行
+	condition       func(ctx context.Context) bool                         // 执行条件，返回 false 时跳过本次执行
+	circuitBreaker  *CircuitBreaker                                        // 熔断器，断开时不调用任务函数
+	rateLimiter     *tokenBucket                                           // 执行速率限制，每次调用任务函数前取得令牌
+	maxConcurrent   int                                                    // 最大并发执行数，0 表示各次执行依次进行
+	overlapPolicy   OverlapPolicy                                          // 并发执行数达到上限时的处理策略
+	traceStart      func(ctx context.Context, name string) context.Context // 每次执行前的追踪钩子
+	traceEnd        func(ctx context.Context, err error)                   // 每次执行后的追踪钩子
+	priority        Priority                                               // 任务优先级
+	tags            []string                                               // 任务标签，用于分组和过滤
+	syncExec        bool                                                   // 是否同步执行
 
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 	runCount   int64
 
+	// 最近一次取消的原因，由 causeMutex 保护
+	cancelCause error
+	causeMutex  sync.Mutex
+
+	// 最近一次执行结束时关闭的通道，由 stateMutex 保护，nil 表示从未执行
+	execDone chan struct{}
+
+	// 并发执行
+	runSlots     chan struct{}  // 并发执行槽位
+	runsWG       sync.WaitGroup // 等待正在进行的并发执行
+	launchedRuns int64          // 已开始的并发执行次数
+
 	// 任务状态管理
-	state       TaskState    // 当前状态
-	stateMutex  sync.RWMutex // 保护状态的互斥锁
-	lastRunTime time.Time    // 上次运行时间
-	lastError   error        // 上次错误
+	state       TaskState     // 当前状态
+	stateMutex  sync.RWMutex  // 保护状态的互斥锁
+	lastRunTime time.Time     // 上次运行时间
+	lastError   error         // 上次错误
+	lastResult  interface{}   // 最近一次成功执行返回的结果
+	resumeCh    chan struct{} // 暂停期间非空，恢复时关闭
+	completed   bool          // 是否已调用结束回调
+
+	// 执行统计，由 stateMutex 保护
+	successCount  int           // 成功执行次数
+	failureCount  int           // 失败执行次数
+	lastDuration  time.Duration // 最近一次执行耗时
+	totalDuration time.Duration // 所有执行的总耗时
 
 	// 生命周期事件
 	onStateChange func(oldState, newState TaskState) // 状态变化回调
@@ -87,22 +170,31 @@ type Task struct {
 	contextPrep  func(*TaskContext) // 上下文准备钩子
 	contextClean func(*TaskContext) // 上下文清理钩子
 
+	contextCleanReason func(*TaskContext, CleanupReason) // 带结束原因的上下文清理钩子
+	prepTimeout        time.Duration                     // 上下文准备钩子的超时时间，0 表示不限制
+	cleanTimeout       time.Duration                     // 上下文清理钩子的超时时间，0 表示不限制
+
 	// 重试策略
-	retryStrategy RetryStrategy // 重试策略
+	retryStrategy   RetryStrategy // 重试策略
+	rollbackOnRetry bool          // 重试前是否将任务上下文恢复到本次执行开始时的状态
 
 	// 依赖关系管理
 	dependencies      []*Task         // 依赖的任务列表
 	dependenciesMap   map[string]bool // 依赖任务的完成状态
 	dependenciesMutex sync.RWMutex    // 保护依赖相关字段的互斥锁
 	onDependenciesMet func()          // 所有依赖满足时的回调
+	dependencyDelay   time.Duration   // 依赖满足后启动前的等待时间
+
+	// 结果缓存
+	resultCacheTTL time.Duration // 结果缓存有效期，0 表示不缓存
+	cachedResult   *JobResult    // 最近一次成功的执行结果
+	cachedAt       time.Time     // 结果缓存时间
 }
 
 // NewTask 创建新任务，并应用所有配置项
 func NewTask(opts ...TaskOption) *Task {
-	ctx, cancel := context.WithCancel(context.Background())
 	task := &Task{
-		ctx:        ctx,
-		cancelFunc: cancel,
+		id: nextTaskID(),
 
 		// 默认值
 		logger:   defaultLoggerInstance,
@@ -123,6 +215,7 @@ func NewTask(opts ...TaskOption) *Task {
 			// 默认实现为空
 		},
 	}
+	task.ctx, task.cancelFunc = task.newCancelContext(context.Background())
 
 	// 应用所有配置项
 	for _, opt := range opts {
@@ -144,12 +237,22 @@ func (t *Task) setState(newState TaskState) {
 	t.stateMutex.Lock()
 	oldState := t.state
 	t.state = newState
+	// 首次进入结束状态时调用结束回调
+	notify := t.onComplete != nil && isTerminalState(newState) && !t.completed
+	if notify {
+		t.completed = true
+	}
+	lastErr := t.lastError
 	t.stateMutex.Unlock()
 
 	// 调用状态变化回调
 	if t.onStateChange != nil {
 		t.onStateChange(oldState, newState)
 	}
+
+	if notify {
+		t.onComplete(newState, lastErr)
+	}
 }
 
 // GetLastRunTime 获取上次运行时间
@@ -159,6 +262,14 @@ func (t *Task) GetLastRunTime() time.Time {
 	return t.lastRunTime
 }
 
+// LastResult 获取最近一次成功执行时任务函数返回的结果
+// 仅在使用 WithResultJob 时有值，尚未成功执行过时返回 nil
+func (t *Task) LastResult() interface{} {
+	t.stateMutex.RLock()
+	defer t.stateMutex.RUnlock()
+	return t.lastResult
+}
+
 // GetLastError 获取上次错误
 func (t *Task) GetLastError() error {
 	t.stateMutex.RLock()
@@ -166,6 +277,21 @@ func (t *Task) GetLastError() error {
 	return t.lastError
 }
 
+// Done 返回任务生命周期结束时关闭的通道，与 context.Context 的 Done 相同
+// 任务被停止、取消或达到最大运行次数时通道关闭，调用 Reset 后需重新获取
+func (t *Task) Done() <-chan struct{} {
+	t.stateMutex.RLock()
+	defer t.stateMutex.RUnlock()
+	return t.ctx.Done()
+}
+
+// Err 返回任务上下文结束的原因，任务仍在生命周期内时返回 nil
+func (t *Task) Err() error {
+	t.stateMutex.RLock()
+	defer t.stateMutex.RUnlock()
+	return t.ctx.Err()
+}
+
 // GetContext 获取任务上下文
 func (t *Task) GetContext() *TaskContext {
 	if t.taskContext == nil {
@@ -179,6 +305,22 @@ func (t *Task) GetName() string {
 	return t.name
 }
 
+// GetTags 返回任务标签的副本
+func (t *Task) GetTags() []string {
+	return append([]string(nil), t.tags...)
+}
+
+// ID 返回任务的唯一标识，在 NewTask 和 Clone 时分配，任务的整个生命周期内保持不变
+// 该标识只在当前进程内唯一，与存储层的任务ID无关
+func (t *Task) ID() string {
+	return t.id
+}
+
+// logName 返回日志中使用的任务名称，格式为 name#id，用于区分同名任务
+func (t *Task) logName() string {
+	return t.name + "#" + t.id
+}
+
 // SetContextValue 设置上下文值
 func (t *Task) SetContextValue(key string, value interface{}) {
 	t.GetContext().Set(key, value)
@@ -190,11 +332,27 @@ func (t *Task) GetContextValue(key string) (interface{}, bool) {
 }
 
 // DependsOn 设置当前任务依赖的其他任务
+// 依赖任务进入已完成状态时视为满足
 func (t *Task) DependsOn(tasks ...*Task) *Task {
+	return t.addDependencies(tasks, func(state TaskState) bool {
+		return state == TaskStateCompleted
+	})
+}
+
+// DependsOnAny 设置当前任务依赖的其他任务，依赖任务进入任意结束状态（已完成、失败或已取消）时视为满足
+// 适用于无论上游是否成功都需要执行的清理任务。依赖任务的上下文数据同样会传递给当前任务
+func (t *Task) DependsOnAny(tasks ...*Task) *Task {
+	return t.addDependencies(tasks, isTerminalState)
+}
+
+// addDependencies 添加依赖任务，依赖任务首次进入 satisfied 返回 true 的状态时更新依赖状态
+func (t *Task) addDependencies(tasks []*Task, satisfied func(TaskState) bool) *Task {
 	t.dependenciesMutex.Lock()
 	defer t.dependenciesMutex.Unlock()
 
 	for _, task := range tasks {
+		task := task // 回调闭包需要捕获各自的依赖任务
+
 		// 避免重复添加
 		exists := false
 		for _, dep := range t.dependencies {
@@ -215,8 +373,8 @@ func (t *Task) DependsOn(tasks ...*Task) *Task {
 					originalCallback(oldState, newState)
 				}
 
-				// 当依赖任务完成时，更新依赖状态并传递上下文
-				if newState == TaskStateCompleted {
+				// 当依赖任务进入满足条件的状态时，更新依赖状态并传递上下文
+				if satisfied(newState) && !satisfied(oldState) {
 					// 传递上下文数据
 					t.transferContextFromDependency(task)
 
@@ -237,16 +395,8 @@ func (t *Task) transferContextFromDependency(dependency *Task) {
 		return
 	}
 
-	// 获取依赖任务的上下文数据
-	dependencyContext := dependency.taskContext.GetAll()
-
-	// 将依赖任务的上下文数据复制到当前任务
-	for key, value := range dependencyContext {
-		// 只复制当前任务上下文中不存在的键，避免覆盖
-		if _, exists := t.taskContext.Get(key); !exists {
-			t.taskContext.Set(key, value)
-		}
-	}
+	// 只复制当前任务上下文中不存在的键，避免覆盖
+	t.taskContext.Merge(dependency.taskContext, false)
 }
 
 // GetDependencies 获取当前任务依赖的所有任务
@@ -338,7 +488,10 @@ func (t *Task) areDependenciesMetLocked() bool {
 }
 
 // Run 启动任务
-func (t *Task) Run() {
+// 设置了 WithResultCache 且调用时缓存结果仍然有效时返回缓存的结果和 true，
+// 任务照常运行（调用钩子、计入运行次数、按周期重复），只是缓存有效期内的执行不调用任务函数；
+// 其他情况下返回零值和 false，执行结果可以通过 LastResult、WithMetricCollector 等获取
+func (t *Task) Run() (JobResult, bool) {
 	if t.job == nil {
 		panic("job is not set")
 	}
@@ -346,44 +499,148 @@ func (t *Task) Run() {
 	// 检查任务状态，如果已经在运行则不重复启动
 	currentState := t.GetState()
 	if currentState == TaskStateRunning {
-		t.logger.Warn("[%s] Task is already running", t.name)
-		return
+		t.logger.Warn("[%s] Task is already running", t.logName())
+		return JobResult{}, false
+	}
+
+	// 暂停的任务只能通过 Resume 恢复运行
+	if currentState == TaskStatePaused {
+		t.logger.Warn("[%s] Task is paused, call Resume to continue it", t.logName())
+		return JobResult{}, false
+	}
+
+	// 已停止的任务需要先调用 Reset 才能再次运行
+	if currentState == TaskStateCancelled && t.ctx.Err() != nil {
+		t.logger.Warn("[%s] Task has been stopped, call Reset before running it again", t.logName())
+		return JobResult{}, false
 	}
 
 	// 检查依赖是否满足
 	if !t.AreDependenciesMet() {
-		t.logger.Info("[%s] Task has unmet dependencies, waiting...", t.name)
+		t.logger.Info("[%s] Task has unmet dependencies, waiting...", t.logName())
 
 		// 设置依赖满足时的回调，自动启动任务
 		t.WithOnDependenciesMet(func() {
-			t.logger.Info("[%s] All dependencies met, starting task", t.name)
+			if t.dependencyDelay > 0 {
+				// 在独立协程中等待，避免阻塞依赖任务的状态回调
+				go func() {
+					if t.waitDependencyDelay() {
+						t.Run()
+					}
+				}()
+				return
+			}
+
+			t.logger.Info("[%s] All dependencies met, starting task", t.logName())
 			// 递归调用 Run，此时依赖已满足
 			t.Run()
 		})
 
-		return
+		return JobResult{}, false
 	}
 
+	// 配置无效时任务直接失败
+	if err := t.Validate(); err != nil {
+		t.logger.Error("[%s] Invalid task configuration: %v", t.logName(), err)
+		t.stateMutex.Lock()
+		t.lastError = err
+		t.stateMutex.Unlock()
+		t.setState(TaskStateFailed)
+		return JobResult{}, false
+	}
+
+	// 缓存结果在执行时使用，这里只记录调用时是否命中
+	cached, fromCache := t.CachedResult()
+
+	// 记录本次执行，结束时关闭 done
+	done := make(chan struct{})
+	t.stateMutex.Lock()
+	t.execDone = done
+	t.stateMutex.Unlock()
+
 	// 更新任务状态为运行中
 	t.setState(TaskStateRunning)
 
 	// 根据同步/异步模式决定执行方式
 	if t.syncExec {
 		// 同步执行
-		t.executeTaskSync()
+		t.executeTaskSync(done)
 	} else {
 		// 异步执行
-		go t.executeTaskAsync()
+		go t.executeTaskAsync(done)
+	}
+	return cached, fromCache
+}
+
+// executionDone 返回最近一次执行结束时关闭的通道，任务从未执行时返回已关闭的通道
+// 与 Done 不同，它在执行协程真正退出（包括清理钩子执行完毕）后才关闭
+func (t *Task) executionDone() <-chan struct{} {
+	t.stateMutex.RLock()
+	defer t.stateMutex.RUnlock()
+
+	if t.execDone == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	return t.execDone
+}
+
+// MaxTaskTimeout 是 WithTimeout 允许的最大超时时间
+const MaxTaskTimeout = 24 * time.Hour
+
+// Validate 检查任务配置是否有效
+// 返回的错误包含所有无效的配置项，可以使用 errors.Is 与 ErrJobNotSet、ErrInvalidMaxRuns、
+// ErrInvalidRetry、ErrInvalidTimeout、ErrInvalidInterval 或 cron 解析错误比较。
+// Run 和 RunOnce 在执行前会调用 Validate，配置无效时任务直接失败
+func (t *Task) Validate() error {
+	var errs []error
+
+	if t.job == nil {
+		errs = append(errs, ErrJobNotSet)
+	}
+	if t.maxRuns < 0 {
+		errs = append(errs, fmt.Errorf("%w: %d", ErrInvalidMaxRuns, t.maxRuns))
+	}
+	if t.retryTimes < 0 {
+		errs = append(errs, fmt.Errorf("%w: %d", ErrInvalidRetry, t.retryTimes))
+	}
+	if t.timeout < 0 || t.timeout > MaxTaskTimeout {
+		errs = append(errs, fmt.Errorf("%w: %v (must be between 0 and %v)", ErrInvalidTimeout, t.timeout, MaxTaskTimeout))
+	}
+	// 重复执行的间隔无效且不限制运行次数时，任务会退化成意料之外的行为
+	if interval := t.getInterval(); t.repeating && interval <= 0 && t.maxRuns == 0 {
+		errs = append(errs, fmt.Errorf("%w: %v", ErrInvalidInterval, interval))
 	}
+	if t.cronErr != nil {
+		errs = append(errs, t.cronErr)
+	}
+
+	return errors.Join(errs...)
 }
 
-// executeTaskSync 同步执行任务
-func (t *Task) executeTaskSync() {
+// waitDependencyDelay 依赖满足后等待指定时间，返回是否应该继续启动
+func (t *Task) waitDependencyDelay() bool {
+	t.logger.Info("[%s] All dependencies met, starting task after %v", t.logName(), t.dependencyDelay)
+	select {
+	case <-t.ctx.Done():
+		t.logger.Warn("[%s] Dependency delay interrupted: %v", t.logName(), t.ctx.Err())
+		t.markStopped()
+		return false
+	case <-time.After(t.dependencyDelay):
+		return true
+	}
+}
+
+// executeTaskSync 同步执行任务，执行结束后关闭 done
+func (t *Task) executeTaskSync(done chan struct{}) {
+	defer close(done)
 	t.executeTaskCore()
 }
 
-// executeTaskAsync 异步执行任务
-func (t *Task) executeTaskAsync() {
+// executeTaskAsync 异步执行任务，执行结束后关闭 done
+func (t *Task) executeTaskAsync(done chan struct{}) {
+	defer close(done)
 	t.executeTaskCore()
 }
 
@@ -392,13 +649,20 @@ func (t *Task) executeTaskCore() {
 	defer t.handlePanic()
 
 	// 准备上下文
-	t.prepareContext()
+	if !t.prepareContext() {
+		return
+	}
 
 	// 处理启动延迟
 	if !t.handleStartupDelay() {
 		return // 如果在延迟期间被取消，则直接返回
 	}
 
+	// cron 任务等待第一个触发时间
+	if t.cron != nil && !t.waitForNextRun() {
+		return
+	}
+
 	// 主执行循环
 	t.executeMainLoop()
 }
@@ -406,35 +670,55 @@ func (t *Task) executeTaskCore() {
 // handlePanic 处理任务执行过程中的 panic
 func (t *Task) handlePanic() {
 	if r := recover(); r != nil {
-		t.logger.Error("[%s] Recovered from panic: %v", t.name, r)
+		t.logger.Error("[%s] Recovered from panic: %v", t.logName(), r)
 		if t.recoverHook != nil {
 			t.recoverHook(r)
 		}
 
-		// 更新任务状态为失败
-		t.setState(TaskStateFailed)
-
 		// 记录错误信息
 		t.stateMutex.Lock()
 		t.lastError = fmt.Errorf("panic: %v", r)
 		t.stateMutex.Unlock()
 
+		// 更新任务状态为失败
+		t.setState(TaskStateFailed)
+
 		// 执行上下文清理
 		t.cleanupContext()
 	}
 }
 
-// prepareContext 准备任务上下文
-func (t *Task) prepareContext() {
+// prepareContext 准备任务上下文，返回是否应该继续执行
+// 准备钩子超过 prepTimeout 时任务以 ErrPrepTimeout 失败
+func (t *Task) prepareContext() bool {
 	// 确保任务上下文存在
 	if t.taskContext == nil {
 		t.taskContext = NewTaskContext()
 	}
 
 	// 执行上下文准备
-	if t.contextPrep != nil {
-		t.contextPrep(t.taskContext)
+	if t.contextPrep == nil {
+		return true
 	}
+	if !runHookWithTimeout(t.prepTimeout, func() { t.contextPrep(t.taskContext) }) {
+		return true
+	}
+
+	err := fmt.Errorf("%w after %v", ErrPrepTimeout, t.prepTimeout)
+	t.logger.Error("[%s] %v", t.logName(), err)
+
+	t.stateMutex.Lock()
+	t.lastError = err
+	t.stateMutex.Unlock()
+
+	if t.errorHandler != nil {
+		t.errorHandler(err)
+	}
+
+	t.setState(TaskStateFailed)
+	t.cleanupContext()
+	t.cancelFunc()
+	return false
 }
 
 // handleStartupDelay 处理启动延迟，返回是否应该继续执行
@@ -443,11 +727,11 @@ func (t *Task) handleStartupDelay() bool {
 		return true
 	}
 
-	t.logger.Info("[%s] Startup delay: %v", t.name, t.startupDelay)
+	t.logger.Info("[%s] Startup delay: %v", t.logName(), t.startupDelay)
 	select {
 	case <-t.ctx.Done():
-		t.logger.Warn("[%s] Startup delay interrupted: %v", t.name, t.ctx.Err())
-		t.setState(TaskStateCancelled)
+		t.logger.Warn("[%s] Startup delay interrupted: %v", t.logName(), t.ctx.Err())
+		t.markStopped()
 		t.cleanupContext()
 		return false
 	case <-time.After(t.startupDelay):
@@ -463,6 +747,11 @@ func (t *Task) executeMainLoop() {
 			t.handleCancellation()
 			return
 		default:
+			// 暂停期间等待恢复
+			if !t.waitWhilePaused() {
+				t.handleCancellation()
+				return
+			}
 			if !t.executeOneIteration() {
 				return // 如果不需要继续执行，则返回
 			}
@@ -472,13 +761,24 @@ func (t *Task) executeMainLoop() {
 
 // handleCancellation 处理任务取消
 func (t *Task) handleCancellation() {
-	t.logger.Info("[%s] Task stopped: %v", t.name, t.ctx.Err())
-	t.setState(TaskStateCancelled)
+	t.logger.Info("[%s] Task stopped: %v", t.logName(), t.ctx.Err())
+	t.runsWG.Wait()
+	t.markStopped()
 	t.cleanupContext()
 }
 
 // executeOneIteration 执行一次任务迭代，返回是否应该继续执行
 func (t *Task) executeOneIteration() bool {
+	// 执行条件不满足时跳过本次执行
+	if t.condition != nil && !t.condition(t.ctx) {
+		return t.skipIteration()
+	}
+
+	// 允许并发执行时在独立协程中执行任务函数
+	if t.maxConcurrent > 0 {
+		return t.executeConcurrentIteration()
+	}
+
 	// 执行前置钩子
 	if t.preHook != nil {
 		t.preHook()
@@ -491,13 +791,21 @@ func (t *Task) executeOneIteration() bool {
 	t.stateMutex.Unlock()
 
 	// 执行任务并处理重试
-	err := t.executeJobWithRetry(start)
+	err := t.runJob(start)
+	t.recordRun(err, time.Since(start))
 
 	// 处理执行结果
 	if !t.handleJobResult(err) {
 		return false // 如果不需要继续执行，则返回 false
 	}
 
+	// 超过截止时间后任务失败，不再执行后续迭代
+	if t.deadlineExceeded() {
+		t.markStopped()
+		t.cleanupContext()
+		return false
+	}
+
 	// 执行后置钩子
 	if t.postHook != nil {
 		t.postHook()
@@ -509,7 +817,7 @@ func (t *Task) executeOneIteration() bool {
 	}
 
 	// 如果不是周期性任务，执行一次就退出
-	if t.interval <= 0 {
+	if t.getInterval() <= 0 && t.cron == nil {
 		t.setState(TaskStateCompleted)
 		t.cleanupContext()
 		return false
@@ -519,43 +827,225 @@ func (t *Task) executeOneIteration() bool {
 	return t.waitForNextRun()
 }
 
-// executeJobWithRetry 执行任务并处理重试逻辑，返回最终错误
-func (t *Task) executeJobWithRetry(start time.Time) error {
+// skipIteration 跳过本次执行，不调用任务函数也不增加运行次数，返回是否应该继续执行
+func (t *Task) skipIteration() bool {
+	t.logger.Debug("[%s] Condition not met, skipping run", t.logName())
+
+	if t.getInterval() <= 0 && t.cron == nil {
+		// 一次性任务没有后续执行，调用后置钩子通知调用方本次运行结束
+		if t.postHook != nil {
+			t.postHook()
+		}
+		t.setState(TaskStateCompleted)
+		t.cleanupContext()
+		return false
+	}
+
+	return t.waitForNextRun()
+}
+
+// executeConcurrentIteration 在独立协程中开始一次执行，返回是否应该继续执行
+// 并发执行数达到上限时按重叠策略等待或跳过；达到最大运行次数时等待所有执行结束后完成任务
+func (t *Task) executeConcurrentIteration() bool {
+	if !t.acquireRunSlot() {
+		if t.ctx.Err() != nil {
+			return true // 由主循环处理取消
+		}
+		t.logger.Debug("[%s] %d runs in progress, skipping run", t.logName(), t.maxConcurrent)
+		return t.waitForNextRun()
+	}
+
+	launched := atomic.AddInt64(&t.launchedRuns, 1)
+	t.runsWG.Add(1)
+	go func() {
+		defer t.runsWG.Done()
+		defer func() { <-t.runSlots }()
+		defer func() {
+			// panic 后任务已被标记为失败，停止后续执行
+			if t.GetState() == TaskStateFailed {
+				t.cancelFunc()
+			}
+		}()
+		defer t.handlePanic()
+		t.runConcurrent()
+	}()
+
+	// 达到最大运行次数或一次性任务时，等待所有执行结束后完成任务
+	reachedMax := t.maxRuns > 0 && int(launched) >= t.maxRuns
+	if reachedMax || (t.getInterval() <= 0 && t.cron == nil) {
+		t.runsWG.Wait()
+		if t.ctx.Err() == nil {
+			if reachedMax {
+				t.logger.Info("[%s] Reached max runs (%d), stopping.", t.logName(), t.maxRuns)
+			}
+			t.setState(TaskStateCompleted)
+			t.cleanupContext()
+			t.cancelFunc()
+		}
+		return false
+	}
+
+	return t.waitForNextRun()
+}
+
+// acquireRunSlot 获取一个并发执行槽位，OverlapSkip 策略下不等待，任务停止时返回 false
+func (t *Task) acquireRunSlot() bool {
+	if t.overlapPolicy == OverlapSkip {
+		select {
+		case t.runSlots <- struct{}{}:
+			return true
+		default:
+			return false
+		}
+	}
+
+	select {
+	case t.runSlots <- struct{}{}:
+		return true
+	case <-t.ctx.Done():
+		return false
+	}
+}
+
+// runConcurrent 执行一次任务函数及其钩子，在并发执行的协程中调用
+func (t *Task) runConcurrent() {
+	if t.preHook != nil {
+		t.preHook()
+	}
+
+	start := time.Now()
+	t.stateMutex.Lock()
+	t.lastRunTime = start
+	t.stateMutex.Unlock()
+
+	// 失败时按 WithCancelOnFailure 设置停止任务
+	err := t.runJob(start)
+	t.recordRun(err, time.Since(start))
+	t.handleJobResult(err)
+
+	if t.postHook != nil {
+		t.postHook()
+	}
+	atomic.AddInt64(&t.runCount, 1)
+}
+
+// runJob 执行一次迭代的任务函数，缓存结果仍然有效时直接使用缓存结果而不调用任务函数
+func (t *Task) runJob(start time.Time) error {
+	result, ok := t.CachedResult()
+	if !ok {
+		return t.executeJobWithRetry(t.ctx, start)
+	}
+
+	t.logger.Info("[%s] Using cached result from %v ago", t.logName(), time.Since(t.cachedTime()).Round(time.Millisecond))
+	t.stateMutex.Lock()
+	t.lastResult = result.Value
+	t.stateMutex.Unlock()
+	t.collectMetrics(result)
+	return nil
+}
+
+// executeJobWithRetry 在 ctx 下执行任务并处理重试逻辑，返回最终错误
+func (t *Task) executeJobWithRetry(ctx context.Context, start time.Time) error {
 	var err error
 	maxRetries := t.getMaxRetries()
 
+	// 记录执行开始时的上下文，重试前恢复
+	rollback := t.rollbackOnRetry && t.taskContext != nil && maxRetries > 0
+	var snap ContextSnapshot
+	if rollback {
+		snap = t.taskContext.Snapshot()
+	}
+
 	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 && rollback {
+			t.taskContext.Restore(snap)
+		}
+
+		// 等待速率限制的令牌，等待时间不计入超时
+		if t.rateLimiter != nil {
+			if err = t.rateLimiter.wait(ctx); err != nil {
+				break
+			}
+		}
+
 		// 创建任务执行上下文
-		jobCtx, cancel := t.createJobContext()
+		jobCtx, cancel := t.createJobContext(ctx)
 		if cancel != nil {
 			defer cancel()
 		}
 
-		// 执行任务
-		err = t.job(jobCtx)
+		// 开始追踪
+		spanCtx := jobCtx
+		if t.traceStart != nil {
+			if ctx := t.traceStart(jobCtx, t.name); ctx != nil {
+				spanCtx = ctx
+			}
+		}
+
+		// 执行任务，熔断器断开时直接返回 ErrCircuitOpen
+		var value interface{}
+		allowed := t.circuitBreaker == nil || t.circuitBreaker.allow()
+		switch {
+		case !allowed:
+			t.logger.Warn("[%s] Circuit breaker is open, skipping job", t.logName())
+			err = ErrCircuitOpen
+		default:
+			value, err = t.invokeJob(spanCtx)
+		}
 		duration := time.Since(start)
 
-		// 检查是否因为超时而取消
+		// 检查是否因为超时或超过截止时间而取消
 		if jobCtx.Err() == context.DeadlineExceeded {
-			t.logger.Error("[%s] Task timed out after %v", t.name, t.timeout)
-			err = fmt.Errorf("task timed out after %v: %w", t.timeout, jobCtx.Err())
+			if !t.deadline.IsZero() && !time.Now().Before(t.deadline) {
+				t.logger.Error("[%s] Task deadline %v exceeded", t.logName(), t.deadline)
+				err = t.deadlineError()
+				t.setCancelCause(ErrDeadlineExceeded)
+			} else if ctx.Err() == nil {
+				t.logger.Error("[%s] Task timed out after %v", t.logName(), t.timeout)
+				err = &TimeoutError{Timeout: t.timeout}
+				t.setCancelCause(err)
+			}
+		}
+
+		// 记录熔断器结果
+		if allowed && t.circuitBreaker != nil {
+			t.circuitBreaker.record(err)
+		}
+
+		// 结束追踪
+		if t.traceEnd != nil {
+			t.traceEnd(spanCtx, err)
 		}
 
 		// 收集指标
-		t.collectMetrics(JobResult{
+		result := JobResult{
 			Name:     t.name,
 			Duration: duration,
 			Success:  err == nil,
 			Err:      err,
-		})
+			Value:    value,
+		}
+		t.collectMetrics(result)
 
-		// 如果成功，则跳出重试循环
+		// 如果成功，则记录并缓存结果，清除之前的超时原因，跳出重试循环
 		if err == nil {
+			if ctx.Err() == nil {
+				t.setCancelCause(nil)
+			}
+			t.stateMutex.Lock()
+			t.lastResult = value
+			t.stateMutex.Unlock()
+			t.cacheResult(result)
+			break
+		}
+
+		// 任务已停止、超过截止时间或熔断器断开时不再重试
+		if ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) {
 			break
 		}
 
 		// 如果需要重试，则等待后重试
-		if !t.shouldRetry(err, attempt, maxRetries) {
+		if !t.shouldRetry(ctx, err, attempt, maxRetries) {
 			break
 		}
 	}
@@ -563,6 +1053,24 @@ func (t *Task) executeJobWithRetry(start time.Time) error {
 	return err
 }
 
+// invokeJob 调用任务函数
+// 任务函数 panic 时先向熔断器记录失败再继续 panic，否则半开状态的试探永远不会结束
+func (t *Task) invokeJob(ctx context.Context) (value interface{}, err error) {
+	if t.circuitBreaker != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				t.circuitBreaker.record(fmt.Errorf("panic: %v", r))
+				panic(r)
+			}
+		}()
+	}
+
+	if t.resultJob != nil {
+		return t.resultJob(ctx)
+	}
+	return nil, t.job(ctx)
+}
+
 // getMaxRetries 获取最大重试次数
 func (t *Task) getMaxRetries() int {
 	maxRetries := t.retryTimes
@@ -572,19 +1080,162 @@ func (t *Task) getMaxRetries() int {
 	return maxRetries
 }
 
-// createJobContext 创建任务执行上下文
-func (t *Task) createJobContext() (context.Context, context.CancelFunc) {
-	jobCtx := t.ctx
+// inheritContext 使任务上下文继承 parent 的值和取消信号，需在任务运行前调用
+// 已在运行或已被取消的任务保持原上下文不变
+func (t *Task) inheritContext(parent context.Context) {
+	t.stateMutex.Lock()
+	defer t.stateMutex.Unlock()
+
+	if t.state == TaskStateRunning || t.ctx.Err() != nil {
+		return
+	}
+
+	// 停止任务时同时取消原上下文，保证之前通过 Done 获取的通道也会关闭
+	ctx, cancel := t.withDeadline(t.newCancelContext(parent))
+	oldCancel := t.cancelFunc
+	t.ctx = ctx
+	t.cancelFunc = func() {
+		cancel()
+		oldCancel()
+	}
+}
+
+// withDeadline 为设置了截止时间的任务在 ctx 上附加截止时间，返回的取消函数同时取消两者
+func (t *Task) withDeadline(ctx context.Context, cancel context.CancelFunc) (context.Context, context.CancelFunc) {
+	if t.deadline.IsZero() {
+		return ctx, cancel
+	}
+
+	deadlineCtx, deadlineCancel := context.WithDeadlineCause(ctx, t.deadline, ErrDeadlineExceeded)
+	return deadlineCtx, func() {
+		deadlineCancel()
+		cancel()
+	}
+}
+
+// deadlineExceeded 检查任务是否因超过截止时间而结束
+func (t *Task) deadlineExceeded() bool {
+	return !t.deadline.IsZero() && errors.Is(t.ctx.Err(), context.DeadlineExceeded)
+}
+
+// deadlineError 返回超过截止时间的错误
+func (t *Task) deadlineError() error {
+	return fmt.Errorf("%w: %s", ErrDeadlineExceeded, t.deadline.Format(time.RFC3339))
+}
+
+// markStopped 在任务上下文结束时更新状态：超过截止时间时标记为失败并记录错误，否则标记为已取消
+// 已经完成或失败的任务保持原状态
+func (t *Task) markStopped() {
+	if state := t.GetState(); state == TaskStateCompleted || state == TaskStateFailed {
+		return
+	}
+	if t.deadlineExceeded() {
+		t.setCancelCause(ErrDeadlineExceeded)
+		t.stateMutex.Lock()
+		t.lastError = t.deadlineError()
+		t.stateMutex.Unlock()
+		t.setState(TaskStateFailed)
+		return
+	}
+	t.setState(TaskStateCancelled)
+}
+
+// newCancelContext 基于 parent 创建任务上下文，取消时以记录的取消原因作为 context.Cause
+func (t *Task) newCancelContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	return ctx, func() {
+		cancel(t.getCancelCause())
+	}
+}
+
+// setCancelCause 记录最近一次取消的原因
+func (t *Task) setCancelCause(cause error) {
+	t.causeMutex.Lock()
+	t.cancelCause = cause
+	t.causeMutex.Unlock()
+}
+
+// getCancelCause 返回记录的取消原因
+func (t *Task) getCancelCause() error {
+	t.causeMutex.Lock()
+	defer t.causeMutex.Unlock()
+	return t.cancelCause
+}
+
+// CancellationCause 返回任务最近一次被取消的原因，没有被取消时返回 nil
+// 调用 Stop 时为 ErrTaskStopped，单次执行超时时为 *TimeoutError，超过截止时间时为 ErrDeadlineExceeded，
+// 通过父上下文（例如任务组）取消时为父上下文的取消原因。执行成功后之前的超时原因会被清除。
+// 任务函数中可以通过 context.Cause(ctx) 获取同样的原因
+func (t *Task) CancellationCause() error {
+	if cause := t.getCancelCause(); cause != nil {
+		return cause
+	}
+
+	t.stateMutex.RLock()
+	ctx, state := t.ctx, t.state
+	t.stateMutex.RUnlock()
+	if state == TaskStateCancelled && ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return nil
+}
+
+// CancellationReason 返回 CancellationCause 的描述，没有被取消时返回空字符串
+func (t *Task) CancellationReason() string {
+	if cause := t.CancellationCause(); cause != nil {
+		return cause.Error()
+	}
+	return ""
+}
+
+// createJobContext 基于 ctx 创建任务执行上下文
+func (t *Task) createJobContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	jobCtx := ctx
 	var cancel context.CancelFunc
 
 	if t.timeout > 0 {
-		jobCtx, cancel = context.WithTimeout(t.ctx, t.timeout)
+		jobCtx, cancel = context.WithTimeoutCause(ctx, t.timeout, &TimeoutError{Timeout: t.timeout})
 	}
 
 	// 将任务实例添加到上下文中，便于在任务函数中访问
 	return WithTaskInContext(jobCtx, t), cancel
 }
 
+// cacheResult 缓存成功的执行结果
+func (t *Task) cacheResult(result JobResult) {
+	if t.resultCacheTTL <= 0 {
+		return
+	}
+
+	t.stateMutex.Lock()
+	t.cachedResult = &result
+	t.cachedAt = time.Now()
+	t.stateMutex.Unlock()
+}
+
+// cachedTime 获取结果缓存时间
+func (t *Task) cachedTime() time.Time {
+	t.stateMutex.RLock()
+	defer t.stateMutex.RUnlock()
+	return t.cachedAt
+}
+
+// CachedResult 获取仍在有效期内的缓存结果
+func (t *Task) CachedResult() (JobResult, bool) {
+	t.stateMutex.RLock()
+	defer t.stateMutex.RUnlock()
+
+	if t.resultCacheTTL <= 0 || t.cachedResult == nil {
+		return JobResult{}, false
+	}
+
+	if time.Since(t.cachedAt) > t.resultCacheTTL {
+		return JobResult{}, false
+	}
+
+	return *t.cachedResult, true
+}
+
 // collectMetrics 收集任务执行指标
 func (t *Task) collectMetrics(result JobResult) {
 	if t.metricCollector != nil {
@@ -593,7 +1244,7 @@ func (t *Task) collectMetrics(result JobResult) {
 }
 
 // shouldRetry 判断是否应该重试
-func (t *Task) shouldRetry(err error, attempt, maxRetries int) bool {
+func (t *Task) shouldRetry(ctx context.Context, err error, attempt, maxRetries int) bool {
 	// 如果是最后一次尝试，不需要重试
 	if attempt >= maxRetries {
 		return false
@@ -602,35 +1253,47 @@ func (t *Task) shouldRetry(err error, attempt, maxRetries int) bool {
 	if t.retryStrategy != nil {
 		// 检查是否应该重试
 		if !t.retryStrategy.ShouldRetry(err) {
-			t.logger.Warn("[%s] Error not retryable: %v", t.name, err)
+			t.logger.Warn("[%s] Error not retryable: %v", t.logName(), err)
 			return false
 		}
 
 		// 获取下一次重试的延迟时间
 		delay := t.retryStrategy.NextRetryDelay(attempt, err)
 		if delay == 0 {
-			t.logger.Warn("[%s] Retry strategy decided not to retry", t.name)
+			t.logger.Warn("[%s] Retry strategy decided not to retry", t.logName())
 			return false // 策略决定不再重试
 		}
 
 		t.logger.Warn("[%s] Attempt %d failed: %v, retrying after %v...",
-			t.name, attempt+1, err, delay)
+			t.logName(), attempt+1, err, delay)
 
 		// 等待重试
 		select {
-		case <-t.ctx.Done():
-			t.logger.Warn("[%s] Retry interrupted: %v", t.name, t.ctx.Err())
+		case <-ctx.Done():
+			t.logger.Warn("[%s] Retry interrupted: %v", t.logName(), ctx.Err())
 			return false
 		case <-time.After(delay):
 			return true // 继续下一次重试
 		}
-	} else {
-		// 使用原有的重试逻辑
-		t.logger.Warn("[%s] Attempt %d failed: %v, retrying...", t.name, attempt+1, err)
-		return true
 	}
 
-	return false
+	// 使用原有的重试逻辑
+	t.logger.Warn("[%s] Attempt %d failed: %v, retrying...", t.logName(), attempt+1, err)
+	return true
+}
+
+// RunOnce 在当前协程中执行一次任务函数并返回最终错误
+// 遵循任务的超时和重试设置，但不重复执行，也不改变任务的状态、运行次数和生命周期上下文，
+// 之后仍可以正常调用 Run。ctx 结束时停止执行和重试等待；设置了截止时间时同样生效
+func (t *Task) RunOnce(ctx context.Context) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := t.withDeadline(context.WithCancel(ctx))
+	defer cancel()
+
+	return t.executeJobWithRetry(ctx, time.Now())
 }
 
 // handleJobResult 处理任务执行结果，返回是否应该继续执行
@@ -639,7 +1302,7 @@ func (t *Task) handleJobResult(err error) bool {
 		return true
 	}
 
-	t.logger.Error("[%s] Failed after retries: %v", t.name, err)
+	t.logger.Error("[%s] Failed after retries: %v", t.logName(), err)
 
 	// 更新任务状态和错误信息
 	t.stateMutex.Lock()
@@ -664,7 +1327,7 @@ func (t *Task) handleJobResult(err error) bool {
 func (t *Task) checkMaxRuns() bool {
 	newCount := atomic.AddInt64(&t.runCount, 1)
 	if t.maxRuns > 0 && int(newCount) >= t.maxRuns {
-		t.logger.Info("[%s] Reached max runs (%d), stopping.", t.name, t.maxRuns)
+		t.logger.Info("[%s] Reached max runs (%d), stopping.", t.logName(), t.maxRuns)
 		t.setState(TaskStateCompleted)
 		t.cleanupContext()
 		t.cancelFunc()
@@ -675,46 +1338,194 @@ func (t *Task) checkMaxRuns() bool {
 
 // waitForNextRun 等待下一次执行，返回是否应该继续执行
 func (t *Task) waitForNextRun() bool {
+	delay := t.nextRunDelay(time.Now())
+	if delay < 0 {
+		t.logger.Info("[%s] No upcoming run for cron expression %q", t.logName(), t.cron)
+		t.setState(TaskStateCompleted)
+		t.cleanupContext()
+		return false
+	}
+
+	// 下一次执行时间已到达结束时间时正常结束，不再等待
+	if !t.runUntil.IsZero() && !time.Now().Add(delay).Before(t.runUntil) {
+		t.logger.Info("[%s] Reached end time %s, stopping.", t.logName(), t.runUntil.Format(time.RFC3339))
+		t.runsWG.Wait()
+		t.setState(TaskStateCompleted)
+		t.cleanupContext()
+		t.cancelFunc()
+		return false
+	}
+
 	select {
 	case <-t.ctx.Done():
-		t.logger.Info("[%s] Next execution canceled: %v", t.name, t.ctx.Err())
-		t.setState(TaskStateCancelled)
+		t.logger.Info("[%s] Next execution canceled: %v", t.logName(), t.ctx.Err())
+		t.runsWG.Wait()
+		t.markStopped()
 		t.cleanupContext()
 		return false
-	case <-time.After(t.interval):
+	case <-time.After(delay):
 		return true
 	}
 }
 
+// nextRunDelay 计算距离下一次执行的等待时间
+// 固定间隔模式下等待 interval；固定频率模式下等待到锚点之后的下一个间隔边界，
+// 错过的边界会被跳过；cron 模式下等待到下一个匹配的时间点，没有后续时间点时返回负值
+func (t *Task) nextRunDelay(now time.Time) time.Duration {
+	if t.cron != nil {
+		next := t.cron.Next(now)
+		if next.IsZero() {
+			return -1
+		}
+		return next.Sub(now)
+	}
+
+	interval := t.getInterval()
+	if t.rateAnchor.IsZero() {
+		return interval
+	}
+
+	next := t.rateAnchor
+	if elapsed := now.Sub(t.rateAnchor); elapsed > 0 {
+		slots := elapsed / interval
+		if elapsed%interval != 0 {
+			slots++
+		}
+		next = t.rateAnchor.Add(slots * interval)
+	}
+
+	// 本次执行恰好从边界开始时，该边界已经执行过
+	if !next.After(t.GetLastRunTime()) {
+		next = next.Add(interval)
+	}
+
+	return next.Sub(now)
+}
+
 // cleanupContext 清理上下文
+// 清理钩子超过 cleanTimeout 时不再等待，只记录错误日志
 func (t *Task) cleanupContext() {
-	if t.contextClean != nil && t.taskContext != nil {
-		t.contextClean(t.taskContext)
+	if t.taskContext == nil {
+		return
+	}
+	if t.contextClean == nil && t.contextCleanReason == nil {
+		return
+	}
+
+	reason := t.cleanupReason()
+	timedOut := runHookWithTimeout(t.cleanTimeout, func() {
+		if t.contextClean != nil {
+			t.contextClean(t.taskContext)
+		}
+		if t.contextCleanReason != nil {
+			t.contextCleanReason(t.taskContext, reason)
+		}
+	})
+	if timedOut {
+		t.logger.Error("[%s] %v after %v", t.logName(), ErrCleanTimeout, t.cleanTimeout)
+	}
+}
+
+// runHookWithTimeout 执行钩子，超过 timeout 时不再等待并返回 true
+// timeout 不大于 0 时直接在当前协程执行；否则钩子在独立协程中执行，其中的 panic 会在当前协程重新抛出
+func runHookWithTimeout(timeout time.Duration, hook func()) bool {
+	if timeout <= 0 {
+		hook()
+		return false
+	}
+
+	done := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			done <- recover()
+		}()
+		hook()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		if r != nil {
+			panic(r)
+		}
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// cleanupReason 根据任务当前状态返回清理原因
+func (t *Task) cleanupReason() CleanupReason {
+	switch t.GetState() {
+	case TaskStateFailed:
+		return CleanupFailed
+	case TaskStateCancelled:
+		return CleanupCancelled
+	default:
+		return CleanupCompleted
 	}
 }
 
 // Pause 暂停任务（仅对周期性任务有效）
+// 正在进行的执行不受影响，暂停期间不再开始新的执行，直到调用 Resume 或任务被停止
 func (t *Task) Pause() bool {
-	currentState := t.GetState()
-	if currentState != TaskStateRunning {
+	t.stateMutex.Lock()
+	if t.state != TaskStateRunning {
+		t.stateMutex.Unlock()
 		return false
 	}
+	t.state = TaskStatePaused
+	t.resumeCh = make(chan struct{})
+	t.stateMutex.Unlock()
 
-	t.setState(TaskStatePaused)
+	t.logger.Info("[%s] Task paused", t.logName())
+	if t.onStateChange != nil {
+		t.onStateChange(TaskStateRunning, TaskStatePaused)
+	}
 	return true
 }
 
 // Resume 恢复暂停的任务
 func (t *Task) Resume() bool {
-	currentState := t.GetState()
-	if currentState != TaskStatePaused {
+	t.stateMutex.Lock()
+	if t.state != TaskStatePaused {
+		t.stateMutex.Unlock()
 		return false
 	}
+	t.state = TaskStateRunning
+	if t.resumeCh != nil {
+		close(t.resumeCh)
+		t.resumeCh = nil
+	}
+	t.stateMutex.Unlock()
 
-	t.setState(TaskStateRunning)
+	t.logger.Info("[%s] Task resumed", t.logName())
+	if t.onStateChange != nil {
+		t.onStateChange(TaskStatePaused, TaskStateRunning)
+	}
 	return true
 }
 
+// waitWhilePaused 在任务暂停期间阻塞，返回是否应该继续执行（任务被停止时返回 false）
+func (t *Task) waitWhilePaused() bool {
+	t.stateMutex.RLock()
+	resume := t.resumeCh
+	t.stateMutex.RUnlock()
+
+	if resume == nil {
+		return true
+	}
+
+	select {
+	case <-resume:
+		return true
+	case <-t.ctx.Done():
+		return false
+	}
+}
+
 // Stop 停止任务
 func (t *Task) Stop() {
 	currentState := t.GetState()
@@ -723,7 +1534,8 @@ func (t *Task) Stop() {
 	}
 
 	if t.ctx.Err() == nil { // 只有在任务未停止时才记录日志和取消
-		t.logger.Info("[%s] Stopping task...", t.name)
+		t.logger.Info("[%s] Stopping task...", t.logName())
+		t.setCancelCause(ErrTaskStopped)
 		t.setState(TaskStateCancelled)
 		t.cancelFunc()
 	}
@@ -732,18 +1544,26 @@ func (t *Task) Stop() {
 // Reset 重置任务状态，允许重新运行
 func (t *Task) Reset() {
 	currentState := t.GetState()
-	if currentState == TaskStateRunning {
-		t.Stop() // 如果任务正在运行，先停止它
+	if currentState == TaskStateRunning || currentState == TaskStatePaused {
+		t.Stop() // 如果任务正在运行或已暂停，先停止它
 	}
 
-	// 创建新的上下文
-	ctx, cancel := context.WithCancel(context.Background())
+	// 创建新的上下文，截止时间是绝对时间，重置后仍然有效
+	t.setCancelCause(nil)
+	ctx, cancel := t.withDeadline(t.newCancelContext(context.Background()))
 
 	t.stateMutex.Lock()
 	// 重置状态
 	t.state = TaskStateIdle
 	t.lastError = nil
+	t.lastResult = nil
 	t.lastRunTime = time.Time{}
+	t.resumeCh = nil
+	t.completed = false
+	t.successCount = 0
+	t.failureCount = 0
+	t.lastDuration = 0
+	t.totalDuration = 0
 
 	// 重置上下文
 	t.ctx = ctx
@@ -751,9 +1571,87 @@ func (t *Task) Reset() {
 
 	// 重置运行计数
 	atomic.StoreInt64(&t.runCount, 0)
+	atomic.StoreInt64(&t.launchedRuns, 0)
 	t.stateMutex.Unlock()
 
-	t.logger.Info("[%s] Task has been reset", t.name)
+	t.logger.Info("[%s] Task has been reset", t.logName())
+}
+
+// Clone 复制任务的配置，返回一个独立的新任务
+// 新任务拥有新的上下文，运行状态、运行次数和结果缓存均为初始值，依赖关系会重新建立，
+// TaskContext 会被深拷贝（见 TaskContext.Clone）。熔断器、速率限制和重试策略与原任务共享。
+// opts 在复制后应用，可用于修改名称、间隔等配置。
+// 工作池会包装提交给它的任务的钩子，因此应在提交之前克隆模板任务
+func (t *Task) Clone(opts ...TaskOption) *Task {
+	clone := &Task{
+		id:              nextTaskID(),
+		name:            t.name,
+		job:             t.job,
+		resultJob:       t.resultJob,
+		timeout:         t.timeout,
+		interval:        t.getInterval(),
+		repeating:       t.repeating,
+		rateAnchor:      t.rateAnchor,
+		deadline:        t.deadline,
+		runUntil:        t.runUntil,
+		cron:            t.cron,
+		cronErr:         t.cronErr,
+		maxRuns:         t.maxRuns,
+		retryTimes:      t.retryTimes,
+		startupDelay:    t.startupDelay,
+		preHook:         t.preHook,
+		postHook:        t.postHook,
+		errorHandler:    t.errorHandler,
+		onComplete:      t.onComplete,
+		cancelOnErr:     t.cancelOnErr,
+		logger:          t.logger,
+		recoverHook:     t.recoverHook,
+		metricCollector: t.metricCollector,
+		condition:       t.condition,
+		circuitBreaker:  t.circuitBreaker,
+		rateLimiter:     t.rateLimiter,
+		maxConcurrent:   t.maxConcurrent,
+		overlapPolicy:   t.overlapPolicy,
+		traceStart:      t.traceStart,
+		traceEnd:        t.traceEnd,
+		priority:        t.priority,
+		tags:            append([]string(nil), t.tags...),
+		syncExec:        t.syncExec,
+
+		state:         TaskStateIdle,
+		onStateChange: t.onStateChange,
+
+		contextPrep:        t.contextPrep,
+		contextClean:       t.contextClean,
+		contextCleanReason: t.contextCleanReason,
+		prepTimeout:        t.prepTimeout,
+		cleanTimeout:       t.cleanTimeout,
+
+		retryStrategy:   t.retryStrategy,
+		rollbackOnRetry: t.rollbackOnRetry,
+
+		dependencies:      make([]*Task, 0),
+		dependenciesMap:   make(map[string]bool),
+		onDependenciesMet: func() {},
+		dependencyDelay:   t.dependencyDelay,
+
+		resultCacheTTL: t.resultCacheTTL,
+	}
+	clone.ctx, clone.cancelFunc = clone.withDeadline(clone.newCancelContext(context.Background()))
+	if t.maxConcurrent > 0 {
+		clone.runSlots = make(chan struct{}, t.maxConcurrent)
+	}
+
+	if t.taskContext != nil {
+		clone.taskContext = t.taskContext.Clone()
+	}
+	clone.DependsOn(t.GetDependencies()...)
+
+	for _, opt := range opts {
+		opt(clone)
+	}
+
+	return clone
 }
 
 // WithStateChangeCallback 设置状态变化回调
@@ -763,7 +1661,70 @@ func WithStateChangeCallback(callback func(oldState, newState TaskState)) TaskOp
 	}
 }
 
+// SetInterval 修改周期性任务的执行间隔
+// 可以在任务函数中或其他协程中调用，在下一次等待执行时生效；d 不大于 0 时忽略。
+// 固定频率模式下新的间隔从锚点重新计算边界，对 cron 任务无效
+func (t *Task) SetInterval(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	t.stateMutex.Lock()
+	t.interval = d
+	t.stateMutex.Unlock()
+}
+
+// getInterval 获取当前执行间隔
+func (t *Task) getInterval() time.Duration {
+	t.stateMutex.RLock()
+	defer t.stateMutex.RUnlock()
+	return t.interval
+}
+
 // GetRunCount 返回当前运行次数
 func (t *Task) GetRunCount() int {
 	return int(atomic.LoadInt64(&t.runCount))
 }
+
+// TaskMetrics 是任务执行情况的汇总
+type TaskMetrics struct {
+	RunCount        int           // 运行次数，与 GetRunCount 相同
+	SuccessCount    int           // 成功执行次数
+	FailureCount    int           // 重试后仍失败的执行次数
+	LastDuration    time.Duration // 最近一次执行耗时（包括重试）
+	AverageDuration time.Duration // 平均执行耗时
+	LastError       error         // 最近一次错误
+}
+
+// Metrics 返回任务执行情况的汇总，不需要设置指标收集器
+// 统计在每次调度执行结束后更新，RunOnce 的执行不计入统计；调用 Reset 后清零
+func (t *Task) Metrics() TaskMetrics {
+	t.stateMutex.RLock()
+	defer t.stateMutex.RUnlock()
+
+	metrics := TaskMetrics{
+		RunCount:     t.GetRunCount(),
+		SuccessCount: t.successCount,
+		FailureCount: t.failureCount,
+		LastDuration: t.lastDuration,
+		LastError:    t.lastError,
+	}
+	if runs := t.successCount + t.failureCount; runs > 0 {
+		metrics.AverageDuration = t.totalDuration / time.Duration(runs)
+	}
+	return metrics
+}
+
+// recordRun 记录一次执行的结果和耗时
+func (t *Task) recordRun(err error, duration time.Duration) {
+	t.stateMutex.Lock()
+	defer t.stateMutex.Unlock()
+
+	if err == nil {
+		t.successCount++
+	} else {
+		t.failureCount++
+	}
+	t.lastDuration = duration
+	t.totalDuration += duration
+}
@@ -3,6 +3,9 @@ package scheduler
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -146,6 +149,181 @@ func TestTaskTimeout(t *testing.T) {
 	}
 }
 
+// TestTaskTimeoutError 测试超时错误可以通过 errors.Is 识别
+func TestTaskTimeoutError(t *testing.T) {
+	var retried []bool
+	strategy := NewFixedDelayRetryStrategy(10*time.Millisecond, 1).WithRetryPredicate(func(err error) bool {
+		retried = append(retried, errors.Is(err, ErrTaskTimeout))
+		return false
+	})
+
+	errCh := make(chan error, 1)
+	task := NewTask(
+		WithName("TimeoutError"),
+		WithJob(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		WithTimeout(50*time.Millisecond),
+		WithRetryStrategy(strategy),
+		WithErrorHandler(func(err error) {
+			errCh <- err
+		}),
+	)
+
+	task.Run()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for error handler")
+	}
+
+	if !errors.Is(err, ErrTaskTimeout) {
+		t.Errorf("Expected errors.Is(err, ErrTaskTimeout), got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected timeout error to wrap context.DeadlineExceeded, got %v", err)
+	}
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != 50*time.Millisecond {
+		t.Errorf("Expected TimeoutError carrying the timeout, got %v", err)
+	}
+	if len(retried) != 1 || !retried[0] {
+		t.Errorf("Expected retry predicate to see the timeout error, got %v", retried)
+	}
+
+	// 任务函数自身返回的错误不是超时错误
+	jobErr := NewTask(WithJob(func(ctx context.Context) error {
+		return errors.New("job failed")
+	}), WithTimeout(time.Second)).RunOnce(context.Background())
+	if errors.Is(jobErr, ErrTaskTimeout) {
+		t.Errorf("Expected job error not to be a timeout, got %v", jobErr)
+	}
+}
+
+// TestTaskID 测试任务标识唯一且稳定
+func TestTaskID(t *testing.T) {
+	a := NewTask(WithName("Same"))
+	b := NewTask(WithName("Same"))
+
+	if a.ID() == "" {
+		t.Fatal("Expected a non-empty task ID")
+	}
+	if a.ID() == b.ID() {
+		t.Errorf("Expected different IDs for different tasks, both were %q", a.ID())
+	}
+	id := a.ID()
+	if a.ID() != id {
+		t.Error("Expected ID to be stable across calls")
+	}
+
+	a.Reset()
+	if a.ID() != id {
+		t.Errorf("Expected Reset to keep ID %q, got %q", id, a.ID())
+	}
+	if clone := a.Clone(); clone.ID() == a.ID() {
+		t.Error("Expected Clone to assign a new ID")
+	}
+}
+
+// TestTaskTags 测试任务标签
+func TestTaskTags(t *testing.T) {
+	if tags := NewTask().GetTags(); len(tags) != 0 {
+		t.Errorf("Expected no tags by default, got %v", tags)
+	}
+
+	task := NewTask(WithTags("backup", "nightly"), WithTags("nightly", "db"))
+	tags := task.GetTags()
+	if len(tags) != 3 || tags[0] != "backup" || tags[1] != "nightly" || tags[2] != "db" {
+		t.Fatalf("Expected [backup nightly db], got %v", tags)
+	}
+
+	// 修改返回的切片不影响任务
+	tags[0] = "changed"
+	if task.GetTags()[0] != "backup" {
+		t.Error("Expected GetTags to return a copy")
+	}
+	if clone := task.Clone(); len(clone.GetTags()) != 3 {
+		t.Errorf("Expected Clone to copy tags, got %v", clone.GetTags())
+	}
+}
+
+// TestTaskCancellationReason 测试 Stop 和超时取消返回不同的取消原因
+func TestTaskCancellationReason(t *testing.T) {
+	started := make(chan struct{})
+	causeCh := make(chan error, 1)
+	stopped := NewTask(
+		WithName("Stopped"),
+		WithJob(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			causeCh <- context.Cause(ctx)
+			return ctx.Err()
+		}),
+	)
+	if reason := stopped.CancellationReason(); reason != "" {
+		t.Errorf("Expected no cancellation reason before running, got %q", reason)
+	}
+
+	stopped.Run()
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for job to start")
+	}
+	stopped.Stop()
+
+	select {
+	case cause := <-causeCh:
+		if !errors.Is(cause, ErrTaskStopped) {
+			t.Errorf("Expected context.Cause to be ErrTaskStopped, got %v", cause)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for job to observe cancellation")
+	}
+	if !errors.Is(stopped.CancellationCause(), ErrTaskStopped) {
+		t.Errorf("Expected ErrTaskStopped, got %v", stopped.CancellationCause())
+	}
+
+	errCh := make(chan error, 1)
+	timedOut := NewTask(
+		WithName("TimedOut"),
+		WithJob(func(ctx context.Context) error {
+			<-ctx.Done()
+			causeCh <- context.Cause(ctx)
+			return ctx.Err()
+		}),
+		WithTimeout(20*time.Millisecond),
+		WithErrorHandler(func(err error) {
+			errCh <- err
+		}),
+	)
+	timedOut.Run()
+	defer timedOut.Stop()
+
+	select {
+	case <-errCh:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for error handler")
+	}
+	if cause := <-causeCh; !errors.Is(cause, ErrTaskTimeout) {
+		t.Errorf("Expected context.Cause to be a timeout, got %v", cause)
+	}
+	if !errors.Is(timedOut.CancellationCause(), ErrTaskTimeout) {
+		t.Errorf("Expected a timeout cause, got %v", timedOut.CancellationCause())
+	}
+
+	stopReason, timeoutReason := stopped.CancellationReason(), timedOut.CancellationReason()
+	if stopReason == timeoutReason {
+		t.Errorf("Expected distinct reasons, both were %q", stopReason)
+	}
+	if !strings.Contains(timeoutReason, "timed out") {
+		t.Errorf("Expected timeout reason to mention the timeout, got %q", timeoutReason)
+	}
+}
+
 // TestTaskErrorHandler 测试错误处理器
 func TestTaskErrorHandler(t *testing.T) {
 	handlerCalled := false
@@ -268,3 +446,892 @@ func TestTaskMetricCollector(t *testing.T) {
 		t.Errorf("Expected result error to be nil, got '%v'", result.Err)
 	}
 }
+
+// TestTaskResultCache 测试结果缓存
+func TestTaskResultCache(t *testing.T) {
+	executions := 0
+	task := NewTask(
+		WithName("CachedTask"),
+		WithSync(true),
+		WithResultCache(100*time.Millisecond),
+		WithJob(func(ctx context.Context) error {
+			executions++
+			return nil
+		}),
+	)
+
+	// 第一次运行，执行任务并缓存结果
+	if _, cached := task.Run(); cached {
+		t.Error("Expected first run not to be served from cache")
+	}
+	if executions != 1 {
+		t.Fatalf("Expected 1 execution, got %d", executions)
+	}
+
+	result, ok := task.CachedResult()
+	if !ok {
+		t.Fatal("Expected cached result to be available, but it wasn't")
+	}
+	if !result.Success || result.Name != "CachedTask" {
+		t.Errorf("Expected successful cached result for CachedTask, got %+v", result)
+	}
+
+	// 有效期内再次运行，不应重新执行，直接返回缓存结果
+	var posts int
+	task.postHook = func() { posts++ }
+	cachedRun, cached := task.Run()
+	if !cached || cachedRun != result {
+		t.Errorf("Expected Run to return the cached result, got %+v, cached: %v", cachedRun, cached)
+	}
+	if posts != 1 {
+		t.Errorf("Expected cache hit to call the post hook once, got %d", posts)
+	}
+	if executions != 1 {
+		t.Errorf("Expected cached run to skip execution, got %d executions", executions)
+	}
+	if task.GetState() != TaskStateCompleted {
+		t.Errorf("Expected state to be completed, got %v", task.GetState())
+	}
+
+	// 有效期过后再次运行，应重新执行
+	time.Sleep(150 * time.Millisecond)
+	if _, ok := task.CachedResult(); ok {
+		t.Error("Expected cached result to expire, but it didn't")
+	}
+
+	task.Run()
+	if executions != 2 {
+		t.Errorf("Expected task to re-execute after TTL, got %d executions", executions)
+	}
+}
+
+// TestTaskResultCacheRepeat 测试周期性任务命中缓存后仍按周期执行并计入运行次数
+func TestTaskResultCacheRepeat(t *testing.T) {
+	var executions, metrics int32
+	task := NewTask(
+		WithName("CachedRepeatTask"),
+		WithRepeat(10*time.Millisecond),
+		WithMaxRuns(3),
+		WithResultCache(time.Minute),
+		WithMetricCollector(func(result JobResult) {
+			atomic.AddInt32(&metrics, 1)
+		}),
+		WithJob(func(ctx context.Context) error {
+			atomic.AddInt32(&executions, 1)
+			return nil
+		}),
+	)
+	task.Run()
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for task to reach max runs")
+	}
+	if state := task.GetState(); state != TaskStateCompleted {
+		t.Errorf("Expected state Completed, got %v", state)
+	}
+	if n := task.GetRunCount(); n != 3 {
+		t.Errorf("Expected 3 runs, got %d", n)
+	}
+	if n := atomic.LoadInt32(&executions); n != 1 {
+		t.Errorf("Expected cached runs to skip the job, got %d executions", n)
+	}
+	if n := atomic.LoadInt32(&metrics); n != 3 {
+		t.Errorf("Expected metrics for every run, got %d", n)
+	}
+}
+
+// TestTaskDoneErr 测试通过 Done 和 Err 监听任务生命周期
+func TestTaskDoneErr(t *testing.T) {
+	task := NewTask(
+		WithName("DoneTask"),
+		WithJob(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+
+	if task.Err() != nil {
+		t.Errorf("Expected nil error before stop, got %v", task.Err())
+	}
+
+	task.Run()
+
+	select {
+	case <-task.Done():
+		t.Fatal("Expected Done not to fire while task is running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	task.Stop()
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected Done to fire after task was stopped")
+	}
+
+	if !errors.Is(task.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled after stop, got %v", task.Err())
+	}
+}
+
+// TestTaskFixedRateFrom 测试锚点对齐的固定频率调度
+func TestTaskFixedRateFrom(t *testing.T) {
+	interval := 100 * time.Millisecond
+	anchor := time.Now()
+
+	var mu sync.Mutex
+	var starts []time.Time
+	done := make(chan struct{})
+
+	task := NewTask(
+		WithName("FixedRateTask"),
+		WithJob(func(ctx context.Context) error {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			if len(starts) == 3 {
+				close(done)
+			}
+			mu.Unlock()
+
+			// 执行时间超过间隔，下一个边界会被跳过
+			time.Sleep(130 * time.Millisecond)
+			return nil
+		}),
+		WithFixedRateFrom(anchor, interval),
+		WithMaxRuns(3),
+	)
+
+	task.Run()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for fixed-rate runs")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// 期望在 0、200ms、400ms 附近执行，而不是随执行时长漂移到 0、230ms、460ms
+	for i, start := range starts {
+		offset := start.Sub(anchor)
+		expected := time.Duration(i) * 2 * interval
+		if offset < expected || offset > expected+40*time.Millisecond {
+			t.Errorf("Expected run %d to start at about %v, got %v", i, expected, offset)
+		}
+	}
+}
+
+// spanKey 是测试中追踪 span 的上下文键类型
+type spanKey struct{}
+
+// TestTaskTraceHooks 测试每次执行前后调用追踪钩子
+func TestTaskTraceHooks(t *testing.T) {
+	type span struct {
+		name string
+		err  error
+	}
+
+	var mu sync.Mutex
+	var spans []span
+	var jobSawSpan bool
+
+	failures := 1
+	task := NewTask(
+		WithName("TracedTask"),
+		WithSync(true),
+		WithRetry(1),
+		WithJob(func(ctx context.Context) error {
+			jobSawSpan = ctx.Value(spanKey{}) != nil
+			if failures > 0 {
+				failures--
+				return errors.New("first attempt failed")
+			}
+			return nil
+		}),
+		WithTraceHooks(
+			func(ctx context.Context, name string) context.Context {
+				return context.WithValue(ctx, spanKey{}, name)
+			},
+			func(ctx context.Context, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				name, _ := ctx.Value(spanKey{}).(string)
+				spans = append(spans, span{name, err})
+			},
+		),
+	)
+	task.Run()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !jobSawSpan {
+		t.Error("Expected job to receive the context returned by the start hook")
+	}
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans (one per attempt), got %d", len(spans))
+	}
+	if spans[0].name != "TracedTask" || spans[0].err == nil {
+		t.Errorf("Expected first span to record the failure, got %+v", spans[0])
+	}
+	if spans[1].name != "TracedTask" || spans[1].err != nil {
+		t.Errorf("Expected second span to succeed, got %+v", spans[1])
+	}
+}
+
+// TestTaskDeadline 测试超过截止时间后任务停止重试并失败
+func TestTaskDeadline(t *testing.T) {
+	deadline := time.Now().Add(300 * time.Millisecond)
+
+	var attempts int64
+	task := NewTask(
+		WithName("DeadlineTask"),
+		WithDeadline(deadline),
+		WithRetry(1000),
+		WithRepeat(10*time.Millisecond),
+		WithJob(func(ctx context.Context) error {
+			atomic.AddInt64(&attempts, 1)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(20 * time.Millisecond):
+				return errors.New("always fails")
+			}
+		}),
+	)
+	task.Run()
+
+	select {
+	case <-task.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected task to stop at the deadline")
+	}
+	if late := time.Since(deadline); late > 200*time.Millisecond {
+		t.Errorf("Expected task to stop by the deadline, stopped %v late", late)
+	}
+
+	// 等待状态更新
+	time.Sleep(50 * time.Millisecond)
+	if state := task.GetState(); state != TaskStateFailed {
+		t.Errorf("Expected state Failed, got %v", state)
+	}
+	if err := task.GetLastError(); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("Expected ErrDeadlineExceeded, got %v", err)
+	}
+	if atomic.LoadInt64(&attempts) < 2 {
+		t.Errorf("Expected multiple attempts before the deadline, got %d", attempts)
+	}
+}
+
+// TestTaskResultJob 测试任务函数返回的结果传给指标收集器并可通过 LastResult 获取
+func TestTaskResultJob(t *testing.T) {
+	var results []JobResult
+	calls := 0
+
+	task := NewTask(
+		WithName("ResultTask"),
+		WithSync(true),
+		WithRetry(1),
+		WithResultJob(func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				return "partial", errors.New("first attempt failed")
+			}
+			return map[string]int{"rows": 42}, nil
+		}),
+		WithMetricCollector(func(result JobResult) {
+			results = append(results, result)
+		}),
+	)
+
+	if task.LastResult() != nil {
+		t.Error("Expected no result before the task runs")
+	}
+
+	task.Run()
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 collected results, got %d", len(results))
+	}
+	if results[0].Value != "partial" || results[0].Success {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if value, ok := results[1].Value.(map[string]int); !ok || value["rows"] != 42 {
+		t.Errorf("Unexpected second result value: %#v", results[1].Value)
+	}
+
+	value, ok := task.LastResult().(map[string]int)
+	if !ok || value["rows"] != 42 {
+		t.Errorf("Expected LastResult to return the successful value, got %#v", task.LastResult())
+	}
+
+	task.Reset()
+	if task.LastResult() != nil {
+		t.Error("Expected Reset to clear the last result")
+	}
+}
+
+// TestTaskCondition 测试执行条件不满足时跳过执行
+func TestTaskCondition(t *testing.T) {
+	var enabled int32
+	var checks, runs int64
+
+	task := NewTask(
+		WithName("ConditionalTask"),
+		WithRepeat(10*time.Millisecond),
+		WithCondition(func(ctx context.Context) bool {
+			atomic.AddInt64(&checks, 1)
+			return atomic.LoadInt32(&enabled) == 1
+		}),
+		WithJob(func(ctx context.Context) error {
+			atomic.AddInt64(&runs, 1)
+			return nil
+		}),
+	)
+	task.Run()
+	defer task.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt64(&runs); n != 0 {
+		t.Fatalf("Expected job not to run while condition is false, ran %d times", n)
+	}
+	if task.GetRunCount() != 0 {
+		t.Errorf("Expected skipped runs not to be counted, got %d", task.GetRunCount())
+	}
+	if atomic.LoadInt64(&checks) < 2 {
+		t.Errorf("Expected condition to be checked on each interval, got %d checks", checks)
+	}
+
+	atomic.StoreInt32(&enabled, 1)
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt64(&runs); n == 0 {
+		t.Error("Expected job to run after condition became true")
+	}
+}
+
+// TestTaskClone 测试克隆的任务与原任务相互独立
+func TestTaskClone(t *testing.T) {
+	var runs int64
+	template := NewTask(
+		WithName("Template"),
+		WithSync(true),
+		WithPriority(PriorityHigh),
+		WithMaxRuns(3),
+		WithRepeat(5*time.Millisecond),
+		WithContextValue("tags", []string{"a", "b"}),
+		WithJob(func(ctx context.Context) error {
+			atomic.AddInt64(&runs, 1)
+			return nil
+		}),
+	)
+
+	clone := template.Clone(WithName("Copy"), WithMaxRuns(2))
+	if clone.GetName() != "Copy" || clone.priority != PriorityHigh || clone.interval != 5*time.Millisecond {
+		t.Errorf("Expected configuration to be copied, got name=%s priority=%d interval=%v",
+			clone.GetName(), clone.priority, clone.interval)
+	}
+
+	// 修改副本的上下文不影响原任务
+	tags, _ := clone.GetContextValue("tags")
+	tags.([]string)[0] = "changed"
+	clone.SetContextValue("extra", true)
+	if tags, _ := template.GetContextValue("tags"); tags.([]string)[0] != "a" {
+		t.Errorf("Expected template context to be unaffected, got %v", tags)
+	}
+	if template.GetContext().Has("extra") {
+		t.Error("Expected template context not to contain the clone's key")
+	}
+
+	template.Run()
+	clone.Run()
+
+	if template.GetRunCount() != 3 {
+		t.Errorf("Expected template to run 3 times, got %d", template.GetRunCount())
+	}
+	if clone.GetRunCount() != 2 {
+		t.Errorf("Expected clone to run 2 times, got %d", clone.GetRunCount())
+	}
+	if n := atomic.LoadInt64(&runs); n != 5 {
+		t.Errorf("Expected shared job to run 5 times in total, got %d", n)
+	}
+}
+
+// TestTaskPauseResume 测试暂停的周期性任务不再执行，恢复后继续执行
+func TestTaskPauseResume(t *testing.T) {
+	var runs int64
+	task := NewTask(
+		WithName("PausableTask"),
+		WithRepeat(10*time.Millisecond),
+		WithJob(func(ctx context.Context) error {
+			atomic.AddInt64(&runs, 1)
+			return nil
+		}),
+	)
+	task.Run()
+	defer task.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	if !task.Pause() {
+		t.Fatal("Expected running task to be paused")
+	}
+	if task.GetState() != TaskStatePaused {
+		t.Errorf("Expected state Paused, got %v", task.GetState())
+	}
+
+	// 等待正在进行的执行结束
+	time.Sleep(20 * time.Millisecond)
+	paused := atomic.LoadInt64(&runs)
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt64(&runs); n != paused {
+		t.Errorf("Expected no runs while paused, runs went from %d to %d", paused, n)
+	}
+
+	if !task.Resume() {
+		t.Fatal("Expected paused task to be resumed")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt64(&runs); n <= paused {
+		t.Errorf("Expected task to run again after resume, runs stayed at %d", n)
+	}
+
+	// 暂停的任务不能通过 Run 重新启动，只能通过 Resume 恢复
+	task.Pause()
+	task.Run()
+	if state := task.GetState(); state != TaskStatePaused {
+		t.Errorf("Expected Run to leave a paused task paused, got %v", state)
+	}
+
+	// 暂停的任务可以被停止
+	task.Stop()
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Error("Expected paused task to stop")
+	}
+}
+
+// TestTaskRunOnce 测试 RunOnce 同步执行一次并返回错误
+func TestTaskRunOnce(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var runs int64
+		task := NewTask(
+			WithName("RunOnceTask"),
+			WithRepeat(time.Hour),
+			WithJob(func(ctx context.Context) error {
+				atomic.AddInt64(&runs, 1)
+				return nil
+			}),
+		)
+
+		if err := task.RunOnce(context.Background()); err != nil {
+			t.Fatalf("Expected success, got %v", err)
+		}
+		if n := atomic.LoadInt64(&runs); n != 1 {
+			t.Errorf("Expected job to run once, ran %d times", n)
+		}
+		if task.GetState() != TaskStateIdle || task.GetRunCount() != 0 {
+			t.Errorf("Expected task state to be untouched, got state=%v runs=%d", task.GetState(), task.GetRunCount())
+		}
+
+		// 之后仍可以正常运行
+		task.Run()
+		defer task.Stop()
+		time.Sleep(50 * time.Millisecond)
+		if task.GetRunCount() != 1 || atomic.LoadInt64(&runs) != 2 {
+			t.Errorf("Expected normal run afterwards, got run count %d", task.GetRunCount())
+		}
+	})
+
+	t.Run("FailureAfterRetries", func(t *testing.T) {
+		var attempts int64
+		task := NewTask(
+			WithName("RunOnceFailing"),
+			WithRetryStrategy(NewFixedDelayRetryStrategy(time.Millisecond, 2)),
+			WithJob(func(ctx context.Context) error {
+				atomic.AddInt64(&attempts, 1)
+				return errors.New("always fails")
+			}),
+		)
+
+		err := task.RunOnce(context.Background())
+		if err == nil || err.Error() != "always fails" {
+			t.Errorf("Expected final job error, got %v", err)
+		}
+		if n := atomic.LoadInt64(&attempts); n != 3 {
+			t.Errorf("Expected 3 attempts, got %d", n)
+		}
+	})
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		task := NewTask(
+			WithName("RunOnceCancelled"),
+			WithRetry(5),
+			WithJob(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		err := task.RunOnce(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected RunOnce to return promptly after cancellation, took %v", elapsed)
+		}
+	})
+}
+
+// TestTaskMaxConcurrentRuns 测试并发执行数不超过上限
+func TestTaskMaxConcurrentRuns(t *testing.T) {
+	for _, policy := range []OverlapPolicy{OverlapWait, OverlapSkip} {
+		var running, peak, runs int64
+		task := NewTask(
+			WithName("OverlappingTask"),
+			WithRepeat(10*time.Millisecond),
+			WithMaxConcurrentRuns(2),
+			WithOverlapPolicy(policy),
+			WithMaxRuns(8),
+			WithJob(func(ctx context.Context) error {
+				n := atomic.AddInt64(&running, 1)
+				for {
+					p := atomic.LoadInt64(&peak)
+					if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(50 * time.Millisecond)
+				atomic.AddInt64(&running, -1)
+				atomic.AddInt64(&runs, 1)
+				return nil
+			}),
+		)
+		task.Run()
+
+		select {
+		case <-task.Done():
+		case <-time.After(3 * time.Second):
+			t.Fatalf("Policy %d: timeout waiting for task to finish", policy)
+		}
+
+		if p := atomic.LoadInt64(&peak); p != 2 {
+			t.Errorf("Policy %d: expected peak concurrency of 2, got %d", policy, p)
+		}
+		if n := atomic.LoadInt64(&runs); n != 8 {
+			t.Errorf("Policy %d: expected all 8 runs to finish before completion, got %d", policy, n)
+		}
+		if task.GetState() != TaskStateCompleted || task.GetRunCount() != 8 {
+			t.Errorf("Policy %d: expected completed task with 8 runs, got state=%v runs=%d",
+				policy, task.GetState(), task.GetRunCount())
+		}
+	}
+}
+
+// TestTaskValidate 测试任务配置校验
+func TestTaskValidate(t *testing.T) {
+	job := WithJob(func(ctx context.Context) error { return nil })
+
+	tests := []struct {
+		name     string
+		options  []TaskOption
+		expected error
+	}{
+		{"missing job", nil, ErrJobNotSet},
+		{"negative max runs", []TaskOption{job, WithMaxRuns(-1)}, ErrInvalidMaxRuns},
+		{"negative retry", []TaskOption{job, WithRetry(-2)}, ErrInvalidRetry},
+		{"negative timeout", []TaskOption{job, WithTimeout(-time.Second)}, ErrInvalidTimeout},
+		{"timeout too large", []TaskOption{job, WithTimeout(MaxTaskTimeout + time.Second)}, ErrInvalidTimeout},
+		{"zero repeat without max runs", []TaskOption{job, WithRepeat(0)}, ErrInvalidInterval},
+		{"negative fixed rate", []TaskOption{job, WithFixedRateFrom(time.Now(), -time.Second)}, ErrInvalidInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := NewTask(tt.options...)
+			if err := task.Validate(); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// 有效配置
+	valid := NewTask(job, WithRepeat(time.Second), WithMaxRuns(3), WithRetry(1), WithTimeout(time.Minute))
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid configuration, got %v", err)
+	}
+	if err := NewTask(job, WithRepeat(0), WithMaxRuns(1)).Validate(); err != nil {
+		t.Errorf("Expected zero repeat with max runs to be valid, got %v", err)
+	}
+
+	// 配置无效时 Run 使任务失败，RunOnce 返回错误
+	invalid := NewTask(job, WithMaxRuns(-1))
+	invalid.Run()
+	if invalid.GetState() != TaskStateFailed || !errors.Is(invalid.GetLastError(), ErrInvalidMaxRuns) {
+		t.Errorf("Expected Run to fail with ErrInvalidMaxRuns, got state=%v err=%v", invalid.GetState(), invalid.GetLastError())
+	}
+	if err := NewTask(job, WithRetry(-1)).RunOnce(context.Background()); !errors.Is(err, ErrInvalidRetry) {
+		t.Errorf("Expected RunOnce to return ErrInvalidRetry, got %v", err)
+	}
+}
+
+// TestTaskMetrics 测试任务执行统计
+func TestTaskMetrics(t *testing.T) {
+	var calls int64
+	jobErr := errors.New("odd run failed")
+	task := NewTask(
+		WithName("MetricsTask"),
+		WithRepeat(10*time.Millisecond),
+		WithMaxRuns(6),
+		WithJob(func(ctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			if atomic.AddInt64(&calls, 1)%2 == 1 {
+				return jobErr
+			}
+			return nil
+		}),
+	)
+
+	if m := task.Metrics(); m != (TaskMetrics{}) {
+		t.Errorf("Expected empty metrics before running, got %+v", m)
+	}
+
+	task.Run()
+	select {
+	case <-task.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timeout waiting for task to finish")
+	}
+
+	m := task.Metrics()
+	if m.RunCount != 6 || m.SuccessCount != 3 || m.FailureCount != 3 {
+		t.Errorf("Expected 6 runs with 3 successes and 3 failures, got %+v", m)
+	}
+	if m.LastDuration < 5*time.Millisecond || m.AverageDuration < 5*time.Millisecond {
+		t.Errorf("Expected durations of at least 5ms, got last=%v average=%v", m.LastDuration, m.AverageDuration)
+	}
+	if !errors.Is(m.LastError, jobErr) {
+		t.Errorf("Expected last error %v, got %v", jobErr, m.LastError)
+	}
+
+	task.Reset()
+	if m := task.Metrics(); m != (TaskMetrics{}) {
+		t.Errorf("Expected metrics to be cleared after reset, got %+v", m)
+	}
+}
+
+// TestTaskOnComplete 测试任务进入各结束状态时结束回调只调用一次
+func TestTaskOnComplete(t *testing.T) {
+	jobErr := errors.New("job failed")
+
+	tests := []struct {
+		name          string
+		options       []TaskOption
+		stop          bool
+		expectedState TaskState
+		expectedErr   string
+	}{
+		{
+			name:          "completed",
+			options:       []TaskOption{WithJob(func(ctx context.Context) error { return nil })},
+			expectedState: TaskStateCompleted,
+		},
+		{
+			name: "failed",
+			options: []TaskOption{
+				WithJob(func(ctx context.Context) error { return jobErr }),
+				WithCancelOnFailure(true),
+			},
+			expectedState: TaskStateFailed,
+			expectedErr:   jobErr.Error(),
+		},
+		{
+			name: "cancelled",
+			options: []TaskOption{
+				WithRepeat(20 * time.Millisecond),
+				WithJob(func(ctx context.Context) error { return nil }),
+			},
+			stop:          true,
+			expectedState: TaskStateCancelled,
+		},
+		{
+			name:          "panic",
+			options:       []TaskOption{WithJob(func(ctx context.Context) error { panic("boom") })},
+			expectedState: TaskStateFailed,
+			expectedErr:   "panic: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var states []TaskState
+			var errs []error
+
+			task := NewTask(append([]TaskOption{
+				WithName("OnComplete-" + tt.name),
+				WithOnComplete(func(state TaskState, lastErr error) {
+					mu.Lock()
+					states = append(states, state)
+					errs = append(errs, lastErr)
+					mu.Unlock()
+				}),
+			}, tt.options...)...)
+
+			task.Run()
+			if tt.stop {
+				time.Sleep(50 * time.Millisecond)
+				task.Stop()
+			}
+			time.Sleep(100 * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if len(states) != 1 {
+				t.Fatalf("Expected exactly one OnComplete call, got %d (%v)", len(states), states)
+			}
+			if states[0] != tt.expectedState {
+				t.Errorf("Expected state %v, got %v", tt.expectedState, states[0])
+			}
+			switch {
+			case tt.expectedErr == "" && errs[0] != nil:
+				t.Errorf("Expected no error, got %v", errs[0])
+			case tt.expectedErr != "" && (errs[0] == nil || errs[0].Error() != tt.expectedErr):
+				t.Errorf("Expected error %q, got %v", tt.expectedErr, errs[0])
+			}
+		})
+	}
+}
+
+// TestTaskRateLimit 测试任务函数的调用速率受到限制
+func TestTaskRateLimit(t *testing.T) {
+	var calls int64
+	task := NewTask(
+		WithName("RateLimitedTask"),
+		WithRepeat(time.Millisecond),
+		WithMaxRuns(5),
+		WithRateLimit(2, 1),
+		WithJob(func(ctx context.Context) error {
+			atomic.AddInt64(&calls, 1)
+			return nil
+		}),
+	)
+
+	start := time.Now()
+	task.Run()
+	select {
+	case <-task.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for task to finish")
+	}
+	elapsed := time.Since(start)
+
+	// 第一次执行立即进行，之后每 500ms 一次
+	if elapsed < 1900*time.Millisecond {
+		t.Errorf("Expected 5 invocations at 2/sec to take at least ~2s, took %v", elapsed)
+	}
+	if n := atomic.LoadInt64(&calls); n != 5 {
+		t.Errorf("Expected 5 invocations, got %d", n)
+	}
+
+	// 等待令牌期间上下文结束时停止等待
+	limited := NewTask(
+		WithName("StoppedWhileLimited"),
+		WithRateLimit(0.1, 1),
+		WithJob(func(ctx context.Context) error { return nil }),
+	)
+	if err := limited.RunOnce(context.Background()); err != nil {
+		t.Fatalf("Expected first run to use the burst token, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limited.RunOnce(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected waiting for a token to stop with the context, got %v", err)
+	}
+}
+
+// TestTaskRunUntil 测试周期性任务在结束时间后正常完成
+func TestTaskRunUntil(t *testing.T) {
+	var runs int64
+	task := NewTask(
+		WithName("RunUntilTask"),
+		WithRepeat(30*time.Millisecond),
+		WithRunUntil(time.Now().Add(120*time.Millisecond)),
+		WithJob(func(ctx context.Context) error {
+			atomic.AddInt64(&runs, 1)
+			return nil
+		}),
+	)
+	task.Run()
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for task to stop at the end time")
+	}
+
+	if state := task.GetState(); state != TaskStateCompleted {
+		t.Errorf("Expected state Completed, got %v", state)
+	}
+	if task.GetLastError() != nil {
+		t.Errorf("Expected no error, got %v", task.GetLastError())
+	}
+	// 执行时间约为 0、30、60、90ms
+	if n := atomic.LoadInt64(&runs); n < 3 || n > 4 {
+		t.Errorf("Expected about 4 runs, got %d", n)
+	}
+}
+
+// TestTaskSetInterval 测试运行中修改执行间隔
+func TestTaskSetInterval(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	var task *Task
+	task = NewTask(
+		WithName("AdaptiveTask"),
+		WithRepeat(80*time.Millisecond),
+		WithMaxRuns(7),
+		WithJob(func(ctx context.Context) error {
+			mu.Lock()
+			times = append(times, time.Now())
+			n := len(times)
+			mu.Unlock()
+
+			// 第 4 次执行后间隔减半
+			if n == 4 {
+				task.SetInterval(40 * time.Millisecond)
+			}
+			return nil
+		}),
+	)
+	task.Run()
+
+	select {
+	case <-task.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timeout waiting for task to finish")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(times) != 7 {
+		t.Fatalf("Expected 7 runs, got %d", len(times))
+	}
+
+	before := times[3].Sub(times[0]) / 3
+	after := times[6].Sub(times[3]) / 3
+	if before < 70*time.Millisecond || after > 60*time.Millisecond {
+		t.Errorf("Expected cadence to change from ~80ms to ~40ms, got %v then %v", before, after)
+	}
+	if task.Report().Interval != 40*time.Millisecond {
+		t.Errorf("Expected report to show the new interval, got %v", task.Report().Interval)
+	}
+}
@@ -3,6 +3,8 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,6 +31,15 @@ type TaskInfo struct {
 	Error     error      // 执行错误（如果有）
 }
 
+// RejectPolicy 定义任务队列已满时的拒绝策略
+type RejectPolicy int
+
+const (
+	RejectBlock RejectPolicy = iota // 阻塞等待队列空间
+	RejectDrop                      // 丢弃任务并记录警告
+	RejectError                     // 返回 ErrQueueFull 错误
+)
+
 // WorkerPool 管理一组工作协程，限制并发执行的任务数量
 type WorkerPool struct {
 	size       int                // 工作池大小（最大并发数）
@@ -40,18 +51,49 @@ type WorkerPool struct {
 	logger     Logger             // 日志记录器
 	mutex      sync.Mutex         // 互斥锁，保护共享数据
 	running    bool               // 工作池是否正在运行
+	schedDone  chan struct{}      // 调度协程退出时关闭，Stop 等待它退出后才关闭任务通道
+	draining   bool               // 工作池是否正在优雅关闭，关闭期间不再接受新任务
+
+	// 动态调整大小
+	quitMutex    sync.Mutex    // 保护 quitChan 和 pendingQuits，工作协程不获取 mutex，避免与 Stop 互相等待
+	quitChan     chan struct{} // 缩容时关闭并替换，唤醒空闲的工作协程检查是否需要退出
+	pendingQuits int           // 缩容后尚未退出的工作协程数量，扩容时先抵消
+	nextWorkerID int           // 下一个工作协程的ID
+
+	// 队列容量控制
+	maxQueueSize int           // 队列最大长度，0 表示不限制
+	rejectPolicy RejectPolicy  // 队列已满时的拒绝策略
+	queueSlots   chan struct{} // 队列槽位信号量，仅在设置了最大长度时使用
+
+	// 提交限流
+	submitLimiter *tokenBucket // 提交速率限制，nil 表示不限制
+
+	// 调度限流
+	dispatchLimiter *tokenBucket // 任务开始执行的速率限制，nil 表示不限制
+
+	// 基础上下文
+	baseCtx context.Context // 工作池及其任务上下文的父上下文，nil 表示使用 context.Background()
+
+	// 提交去重
+	dedup bool // 是否忽略与等待中或执行中的任务同名的提交
 
 	// 任务状态跟踪
 	tasksMutex sync.RWMutex         // 保护任务状态映射的互斥锁
-	tasks      map[string]*TaskInfo // 任务状态映射，键为任务名称
+	tasks      map[string]*TaskInfo // 任务状态映射，键为任务标识
+	taskNames  map[string]string    // 任务名称到最近一次提交的同名任务标识的映射
+	depReadyAt map[string]time.Time // 设置了依赖延迟的任务在依赖满足后可以调度的时间，键为任务标识
 
 	// 统计信息
 	completedTasks int64 // 已完成任务数量
 	failedTasks    int64 // 失败任务数量
+	activeTasks    int64 // 正在执行的任务数量
+	liveWorkers    int64 // 存活的工作协程数量
+	unfinished     int64 // 已提交但尚未执行结束的任务数量
 
 	// 生命周期回调
 	onTaskStart  func(*Task)        // 任务开始执行时的回调
 	onTaskFinish func(*Task, error) // 任务完成执行时的回调
+	poolRecover  func(*Task, any)   // 任务 panic 时的回调
 }
 
 // WorkerPoolOption 是配置工作池的函数类型
@@ -71,6 +113,64 @@ func WithTaskFinishCallback(callback func(*Task, error)) WorkerPoolOption {
 	}
 }
 
+// WithPoolRecover 设置任务 panic 时的回调函数
+// 无论是否设置回调，panic 的任务都会被标记为失败，执行它的工作协程继续处理后续任务
+func WithPoolRecover(callback func(task *Task, r any)) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.poolRecover = callback
+	}
+}
+
+// WithMaxQueueSize 设置任务队列的最大长度，n <= 0 表示不限制
+func WithMaxQueueSize(n int) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.maxQueueSize = n
+	}
+}
+
+// WithRejectPolicy 设置任务队列已满时的拒绝策略
+func WithRejectPolicy(policy RejectPolicy) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.rejectPolicy = policy
+	}
+}
+
+// WithSubmitRateLimit 限制每 per 时间最多接受 rate 次提交
+// 超出速率时按拒绝策略处理：RejectBlock 阻塞等待，RejectDrop 丢弃任务，
+// RejectError 返回 ErrRateLimited
+func WithSubmitRateLimit(rate int, per time.Duration) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.submitLimiter = newTokenBucket(rate, per)
+	}
+}
+
+// WithPoolRateLimit 限制工作池开始执行任务的总速率
+// 调度协程在分发每个任务前等待令牌，令牌以每秒 eventsPerSecond 个的速率生成，最多积累 burst 个，
+// 因此无论工作协程有多少，任务开始执行的总速率都不会超过该限制。工作池停止时等待随之结束
+func WithPoolRateLimit(eventsPerSecond float64, burst int) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.dispatchLimiter = newRateLimitBucket(eventsPerSecond, burst)
+	}
+}
+
+// WithBaseContext 设置工作池的基础上下文
+// 工作池执行的任务的上下文都从该上下文派生，任务函数可以读取其中的值；
+// 基础上下文被取消时，工作池停止调度并停止正在运行的任务
+func WithBaseContext(ctx context.Context) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.baseCtx = ctx
+	}
+}
+
+// WithSubmitDedup 设置是否对提交去重
+// 启用后，如果已有同名任务在等待或执行，Submit 和 TrySubmit 忽略新提交的任务并记录警告，
+// Submit 返回 nil，TrySubmit 返回 false
+func WithSubmitDedup(enabled bool) WorkerPoolOption {
+	return func(wp *WorkerPool) {
+		wp.dedup = enabled
+	}
+}
+
 // NewWorkerPool 创建一个新的工作池
 func NewWorkerPool(size int, logger Logger, opts ...WorkerPoolOption) *WorkerPool {
 	if size <= 0 {
@@ -81,19 +181,18 @@ func NewWorkerPool(size int, logger Logger, opts ...WorkerPoolOption) *WorkerPoo
 		logger = defaultLoggerInstance
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-
 	wp := &WorkerPool{
-		size:       size,
-		taskQueue:  NewPriorityQueue(),
-		taskChan:   make(chan *Task, size*2), // 缓冲区大小为工作池大小的两倍
-		ctx:        ctx,
-		cancelFunc: cancel,
-		logger:     logger,
-		running:    false,
+		size:      size,
+		taskQueue: NewPriorityQueue(),
+		taskChan:  make(chan *Task, size*2), // 缓冲区大小为工作池大小的两倍
+		logger:    logger,
+		running:   false,
+		quitChan:  make(chan struct{}),
 
 		// 初始化任务状态跟踪
-		tasks: make(map[string]*TaskInfo),
+		tasks:      make(map[string]*TaskInfo),
+		taskNames:  make(map[string]string),
+		depReadyAt: make(map[string]time.Time),
 
 		// 默认回调函数
 		onTaskStart: func(t *Task) {
@@ -109,6 +208,18 @@ func NewWorkerPool(size int, logger Logger, opts ...WorkerPoolOption) *WorkerPoo
 		opt(wp)
 	}
 
+	// 创建工作池上下文
+	parent := wp.baseCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	wp.ctx, wp.cancelFunc = context.WithCancel(parent)
+
+	// 初始化队列槽位
+	if wp.maxQueueSize > 0 {
+		wp.queueSlots = make(chan struct{}, wp.maxQueueSize)
+	}
+
 	return wp
 }
 
@@ -125,13 +236,78 @@ func (wp *WorkerPool) Start() {
 	wp.running = true
 
 	// 启动调度协程，将任务从优先级队列移动到任务通道
+	wp.schedDone = make(chan struct{})
 	go wp.scheduler()
 
 	// 启动工作协程
-	wp.wg.Add(wp.size)
-	for i := 0; i < wp.size; i++ {
-		go wp.worker(i)
+	wp.spawnWorkers(wp.size)
+}
+
+// spawnWorkers 启动 n 个工作协程（调用方需持有 wp.mutex）
+func (wp *WorkerPool) spawnWorkers(n int) {
+	wp.wg.Add(n)
+	atomic.AddInt64(&wp.liveWorkers, int64(n))
+	for i := 0; i < n; i++ {
+		go wp.worker(wp.nextWorkerID)
+		wp.nextWorkerID++
+	}
+}
+
+// Resize 在运行时调整工作池大小
+// 扩容时立即启动新的工作协程；缩容时通知多余的工作协程在完成当前任务后退出
+func (wp *WorkerPool) Resize(newSize int) {
+	if newSize <= 0 {
+		newSize = 1 // 至少有一个工作协程
+	}
+
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	oldSize := wp.size
+	wp.size = newSize
+
+	if !wp.running || newSize == oldSize {
+		return
+	}
+
+	wp.logger.Info("Resizing worker pool from %d to %d workers", oldSize, newSize)
+
+	wp.quitMutex.Lock()
+	defer wp.quitMutex.Unlock()
+
+	if newSize > oldSize {
+		// 先取消尚未执行的缩容，再启动不足的工作协程
+		grow := newSize - oldSize
+		cancelled := min(wp.pendingQuits, grow)
+		wp.pendingQuits -= cancelled
+		wp.spawnWorkers(grow - cancelled)
+		return
+	}
+
+	// 记录需要退出的工作协程数量，并唤醒所有空闲的工作协程；
+	// 忙碌的工作协程在完成当前任务后检查，不在持有锁时等待它们
+	wp.pendingQuits += oldSize - newSize
+	close(wp.quitChan)
+	wp.quitChan = make(chan struct{})
+}
+
+// claimQuit 在有待退出的名额时占用一个并返回 true，否则返回当前的唤醒通道
+func (wp *WorkerPool) claimQuit() (bool, <-chan struct{}) {
+	wp.quitMutex.Lock()
+	defer wp.quitMutex.Unlock()
+
+	if wp.pendingQuits > 0 {
+		wp.pendingQuits--
+		return true, nil
 	}
+	return false, wp.quitChan
+}
+
+// Size 返回工作池当前的目标大小
+func (wp *WorkerPool) Size() int {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+	return wp.size
 }
 
 // Stop 停止工作池
@@ -145,58 +321,393 @@ func (wp *WorkerPool) Stop() {
 
 	wp.logger.Info("Stopping worker pool")
 	wp.running = false
-	wp.cancelFunc()    // 取消所有工作协程
+	wp.cancelFunc() // 取消调度协程和所有工作协程
+
+	// 调度协程可能正在向任务通道发送任务，等待它退出后再关闭通道
+	<-wp.schedDone
 	close(wp.taskChan) // 关闭任务通道
 	wp.wg.Wait()       // 等待所有工作协程完成
+	wp.draining = false
+}
+
+// Shutdown 优雅地关闭工作池
+// 立即停止接受新任务，等待正在执行和已在队列中的任务全部完成后停止工作池。
+// 如果 ctx 在任务完成前结束，则放弃剩余任务、停止工作池并返回 ctx 的错误
+func (wp *WorkerPool) Shutdown(ctx context.Context) error {
+	wp.mutex.Lock()
+	if !wp.running {
+		wp.mutex.Unlock()
+		return nil
+	}
+	wp.logger.Info("Shutting down worker pool, draining %d tasks", atomic.LoadInt64(&wp.unfinished))
+	wp.draining = true
+	wp.mutex.Unlock()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for atomic.LoadInt64(&wp.unfinished) > 0 {
+		select {
+		case <-ctx.Done():
+			wp.logger.Warn("Worker pool shutdown interrupted, abandoning %d tasks", atomic.LoadInt64(&wp.unfinished))
+			wp.Stop()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	wp.Stop()
+	return nil
 }
 
 // Submit 提交任务到工作池
-func (wp *WorkerPool) Submit(task *Task) {
+// 如果设置了最大队列长度，队列已满时按拒绝策略处理：
+// RejectBlock 阻塞直到有空间或工作池停止，RejectDrop 丢弃任务并返回 nil，
+// RejectError 返回 ErrQueueFull。提交速率超限时按同样的策略处理
+func (wp *WorkerPool) Submit(task *Task) error {
+	if !wp.isRunning() {
+		wp.logger.Warn("Worker pool is stopped, cannot submit task: %s", task.name)
+		return ErrPoolStopped
+	}
+
+	if wp.dedup && wp.hasActiveTask(task.name) {
+		wp.logger.Warn("Task with the same name is already pending or running, ignoring: %s", task.name)
+		return nil
+	}
+
+	if reserved, err := wp.reserve(task); !reserved {
+		return err
+	}
+
+	wp.enqueue(task)
+	return nil
+}
+
+// reserve 按提交速率限制和队列长度为任务占用一个队列槽位，返回是否占用成功以及失败时的错误
+// 速率超限或队列已满时按拒绝策略处理，RejectDrop 策略下被丢弃的任务不返回错误
+func (wp *WorkerPool) reserve(task *Task) (bool, error) {
+	if admitted, err := wp.admit(task); !admitted {
+		return false, err
+	}
+
+	switch wp.rejectPolicy {
+	case RejectDrop:
+		if !wp.tryAcquireSlot() {
+			wp.logger.Warn("Task queue is full, dropping task: %s", task.name)
+			return false, nil
+		}
+	case RejectError:
+		if !wp.tryAcquireSlot() {
+			return false, ErrQueueFull
+		}
+	default:
+		if !wp.acquireSlot() {
+			wp.logger.Warn("Worker pool is stopped, cannot submit task: %s", task.name)
+			return false, ErrPoolStopped
+		}
+	}
+
+	return true, nil
+}
+
+// requeue 将已离开队列的任务放回队列，与 Submit 一样经过提交速率限制和队列长度检查
+// 被拒绝的任务标记为已取消并计为结束，避免 Shutdown 一直等待
+func (wp *WorkerPool) requeue(task *Task) {
+	if reserved, err := wp.reserve(task); !reserved {
+		if err != nil {
+			wp.logger.Warn("Cannot re-enqueue task %s, cancelling it: %v", task.name, err)
+		}
+
+		wp.tasksMutex.Lock()
+		if info, exists := wp.tasks[task.id]; exists {
+			info.Status = TaskStatusCancelled
+			info.EndTime = time.Now()
+		}
+		wp.tasksMutex.Unlock()
+
+		atomic.AddInt64(&wp.unfinished, -1)
+		return
+	}
+
+	wp.taskQueue.Enqueue(task)
+}
+
+// SubmitFunc 将函数包装为只执行一次的任务并提交到工作池，任务使用工作池的日志记录器
+// 返回值与 Submit 相同
+func (wp *WorkerPool) SubmitFunc(name string, priority Priority, fn func(ctx context.Context) error) error {
+	return wp.Submit(NewTask(
+		WithName(name),
+		WithPriority(priority),
+		WithLogger(wp.logger),
+		WithJob(fn),
+	))
+}
+
+// admit 按提交速率限制放行任务，返回任务是否被放行以及未放行时的错误
+// RejectDrop 策略下被丢弃的任务不返回错误
+func (wp *WorkerPool) admit(task *Task) (bool, error) {
+	if wp.submitLimiter == nil {
+		return true, nil
+	}
+
+	switch wp.rejectPolicy {
+	case RejectDrop:
+		if !wp.submitLimiter.take() {
+			wp.logger.Warn("Submission rate limit exceeded, dropping task: %s", task.name)
+			return false, nil
+		}
+	case RejectError:
+		if !wp.submitLimiter.take() {
+			return false, ErrRateLimited
+		}
+	default:
+		if wp.submitLimiter.wait(wp.ctx) != nil {
+			wp.logger.Warn("Worker pool is stopped, cannot submit task: %s", task.name)
+			return false, ErrPoolStopped
+		}
+	}
+
+	return true, nil
+}
+
+// TrySubmit 尝试提交任务到工作池，不会阻塞
+// 如果工作池未运行、队列已满或提交速率超限，返回 false
+func (wp *WorkerPool) TrySubmit(task *Task) bool {
+	if !wp.isRunning() {
+		return false
+	}
+
+	if wp.submitLimiter != nil && !wp.submitLimiter.take() {
+		return false
+	}
+
+	if !wp.tryAcquireSlot() {
+		return false
+	}
+
+	return wp.enqueue(task)
+}
+
+// isRunning 检查工作池是否正在运行
+func (wp *WorkerPool) isRunning() bool {
 	wp.mutex.Lock()
 	defer wp.mutex.Unlock()
+	return wp.running && !wp.draining
+}
 
-	if !wp.running {
-		wp.logger.Warn("Worker pool is stopped, cannot submit task: %s", task.name)
+// acquireSlot 阻塞获取一个队列槽位，工作池停止时返回 false
+func (wp *WorkerPool) acquireSlot() bool {
+	if wp.queueSlots == nil {
+		return true
+	}
+
+	select {
+	case wp.queueSlots <- struct{}{}:
+		return true
+	case <-wp.ctx.Done():
+		return false
+	}
+}
+
+// tryAcquireSlot 非阻塞地获取一个队列槽位
+func (wp *WorkerPool) tryAcquireSlot() bool {
+	if wp.queueSlots == nil {
+		return true
+	}
+
+	select {
+	case wp.queueSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseSlot 释放一个队列槽位
+func (wp *WorkerPool) releaseSlot() {
+	if wp.queueSlots == nil {
 		return
 	}
 
+	select {
+	case <-wp.queueSlots:
+	default:
+	}
+}
+
+// QueueLength 返回当前等待调度的任务数量
+func (wp *WorkerPool) QueueLength() int {
+	return wp.taskQueue.Size()
+}
+
+// hasActiveTask 检查是否有同名任务在等待或执行
+func (wp *WorkerPool) hasActiveTask(name string) bool {
+	wp.tasksMutex.RLock()
+	defer wp.tasksMutex.RUnlock()
+	return wp.isActiveLocked(name)
+}
+
+// isActiveLocked 检查是否有同名任务在等待或执行，调用者需持有 tasksMutex
+// 启用去重时同名任务中只有最近提交的一个可能处于等待或执行状态，因此只需检查它
+func (wp *WorkerPool) isActiveLocked(name string) bool {
+	info, exists := wp.tasks[wp.taskNames[name]]
+	return exists && (info.Status == TaskStatusPending || info.Status == TaskStatusRunning)
+}
+
+// enqueue 记录任务状态并将任务加入优先级队列，返回任务是否被加入
+// 启用去重时，检查和记录在同一把锁内完成，并发提交的同名任务只有一个被加入，被忽略的任务释放已占用的队列槽位
+func (wp *WorkerPool) enqueue(task *Task) bool {
 	// 记录任务状态
 	wp.tasksMutex.Lock()
-	wp.tasks[task.name] = &TaskInfo{
+	if wp.dedup && wp.isActiveLocked(task.name) {
+		wp.tasksMutex.Unlock()
+		wp.releaseSlot()
+		wp.logger.Warn("Task with the same name is already pending or running, ignoring: %s", task.name)
+		return false
+	}
+	wp.taskNames[task.name] = task.id
+	wp.tasks[task.id] = &TaskInfo{
 		Task:      task,
 		Status:    TaskStatusPending,
 		StartTime: time.Time{}, // 零值表示未开始
 	}
 	wp.tasksMutex.Unlock()
 
+	atomic.AddInt64(&wp.unfinished, 1)
+
 	// 将任务添加到优先级队列
 	wp.taskQueue.Enqueue(task)
 	wp.logger.Debug("Task submitted to worker pool: %s (priority: %d)", task.name, task.priority)
+	return true
 }
 
-// GetTaskInfo 获取任务的状态信息
+// GetTaskInfo 按名称获取任务状态信息的快照
+// 同名任务被多次提交时，返回最近一次提交的任务的状态，其他同名任务的状态可以通过 GetTaskInfoByID 获取
 func (wp *WorkerPool) GetTaskInfo(taskName string) (*TaskInfo, bool) {
 	wp.tasksMutex.RLock()
 	defer wp.tasksMutex.RUnlock()
 
-	info, exists := wp.tasks[taskName]
-	return info, exists
+	return wp.snapshotLocked(wp.taskNames[taskName])
+}
+
+// GetTaskInfoByID 按任务标识获取任务状态信息的快照
+func (wp *WorkerPool) GetTaskInfoByID(id string) (*TaskInfo, bool) {
+	wp.tasksMutex.RLock()
+	defer wp.tasksMutex.RUnlock()
+
+	return wp.snapshotLocked(id)
 }
 
-// GetAllTasksInfo 获取所有任务的状态信息
+// snapshotLocked 返回任务状态信息的副本，调用者需持有 tasksMutex
+func (wp *WorkerPool) snapshotLocked(id string) (*TaskInfo, bool) {
+	info, exists := wp.tasks[id]
+	if !exists {
+		return nil, false
+	}
+	snapshot := *info
+	return &snapshot, true
+}
+
+// GetAllTasksInfo 获取所有任务状态信息的快照，键为任务标识
 func (wp *WorkerPool) GetAllTasksInfo() map[string]*TaskInfo {
 	wp.tasksMutex.RLock()
 	defer wp.tasksMutex.RUnlock()
 
-	// 创建一个副本以避免并发访问问题
+	// 复制状态信息以避免与工作协程的更新并发访问
 	result := make(map[string]*TaskInfo, len(wp.tasks))
 	for k, v := range wp.tasks {
-		result[k] = v
+		snapshot := *v
+		result[k] = &snapshot
 	}
 
 	return result
 }
 
+// ListTasks 返回满足条件的任务状态信息的快照，按任务创建顺序排列，pred 为 nil 时返回所有任务
+func (wp *WorkerPool) ListTasks(pred func(*Task) bool) []*TaskInfo {
+	var result []*TaskInfo
+	for _, info := range wp.GetAllTasksInfo() {
+		if pred == nil || pred(info.Task) {
+			result = append(result, info)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return lessTaskID(result[i].Task.id, result[j].Task.id)
+	})
+	return result
+}
+
+// lessTaskID 按数值比较两个任务标识
+func lessTaskID(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// CancelWhere 取消所有满足条件的等待中或执行中的任务，返回被取消的任务数量
+// 队列中的任务被直接移除，已调度但尚未开始的任务不再执行，这两类任务不调用任务完成回调；
+// 执行中的任务被停止。被取消的任务状态为 TaskStatusCancelled，不计入完成或失败数量
+func (wp *WorkerPool) CancelWhere(pred func(*Task) bool) int {
+	cancelled := 0
+	now := time.Now()
+
+	// 移除队列中等待调度的任务
+	// 已被取消但因依赖未满足而重新入队的任务不再重复计数
+	removed := wp.taskQueue.RemoveWhere(pred)
+	wp.tasksMutex.Lock()
+	for _, task := range removed {
+		if info, exists := wp.tasks[task.id]; exists && info.Status == TaskStatusPending {
+			info.Status = TaskStatusCancelled
+			info.EndTime = now
+			wp.releaseSlot()
+			atomic.AddInt64(&wp.unfinished, -1)
+			cancelled++
+		}
+	}
+	wp.tasksMutex.Unlock()
+
+	// 在锁外调用 pred，pred 可以调用工作池的其他方法
+	var matched []*Task
+	for _, info := range wp.GetAllTasksInfo() {
+		if (info.Status == TaskStatusPending || info.Status == TaskStatusRunning) && pred(info.Task) {
+			matched = append(matched, info.Task)
+		}
+	}
+
+	// 状态可能在调用 pred 期间发生变化，按当前状态处理
+	var running []*Task
+	wp.tasksMutex.Lock()
+	for _, task := range matched {
+		info, exists := wp.tasks[task.id]
+		if !exists {
+			continue
+		}
+		switch info.Status {
+		case TaskStatusPending:
+			// 已离开队列但尚未开始执行，由工作协程跳过
+			info.Status = TaskStatusCancelled
+			info.EndTime = now
+			cancelled++
+		case TaskStatusRunning:
+			info.Status = TaskStatusCancelled
+			running = append(running, task)
+			cancelled++
+		}
+	}
+	wp.tasksMutex.Unlock()
+
+	for _, task := range running {
+		task.Stop()
+	}
+
+	if cancelled > 0 {
+		wp.logger.Info("Cancelled %d tasks", cancelled)
+	}
+	return cancelled
+}
+
 // GetStats 获取工作池的统计信息
 func (wp *WorkerPool) GetStats() (int, int64, int64) {
 	wp.tasksMutex.RLock()
@@ -212,8 +723,32 @@ func (wp *WorkerPool) GetStats() (int, int64, int64) {
 	return pendingTasks, atomic.LoadInt64(&wp.completedTasks), atomic.LoadInt64(&wp.failedTasks)
 }
 
+// PoolMetrics 是工作池运行状况的快照
+type PoolMetrics struct {
+	Pending     int   // 等待执行的任务数量
+	Running     int   // 正在执行的任务数量
+	Completed   int64 // 累计完成的任务数量
+	Failed      int64 // 累计失败的任务数量
+	QueueLength int   // 优先级队列中等待调度的任务数量
+	Workers     int   // 存活的工作协程数量
+}
+
+// Metrics 返回工作池当前的运行状况
+func (wp *WorkerPool) Metrics() PoolMetrics {
+	pending, completed, failed := wp.GetStats()
+	return PoolMetrics{
+		Pending:     pending,
+		Running:     int(atomic.LoadInt64(&wp.activeTasks)),
+		Completed:   completed,
+		Failed:      failed,
+		QueueLength: wp.QueueLength(),
+		Workers:     int(atomic.LoadInt64(&wp.liveWorkers)),
+	}
+}
+
 // scheduler 是调度协程的主函数，负责将任务从优先级队列移动到任务通道
 func (wp *WorkerPool) scheduler() {
+	defer close(wp.schedDone)
 	wp.logger.Debug("Scheduler started")
 
 	for {
@@ -229,28 +764,47 @@ func (wp *WorkerPool) scheduler() {
 		// 从优先级队列中取出任务
 		task := wp.taskQueue.Dequeue()
 		if task == nil {
-			// 队列为空，等待一段时间
-			time.Sleep(100 * time.Millisecond)
+			// 队列为空，等待一段时间，工作池停止时立即退出
+			if !wp.sleep(100 * time.Millisecond) {
+				wp.logger.Debug("Scheduler stopped: context canceled")
+				return
+			}
 			continue
 		}
 
-		// 检查任务依赖是否满足
-		if !task.AreDependenciesMet() {
-			wp.logger.Debug("Task has unmet dependencies, re-enqueuing: %s", task.name)
+		// 等待期间被取消的任务不再调度
+		if wp.dropCancelled(task) {
+			continue
+		}
+
+		// 检查任务依赖是否满足，以及 RunAfterDelay 设置的延迟是否已过
+		if !wp.dependenciesReady(task) {
+			wp.logger.Debug("Task dependencies are not ready, re-enqueuing: %s", task.name)
 
-			// 设置依赖满足时的回调
+			// 设置依赖满足时的回调，任务仍在队列中，依赖满足后由调度协程分发
 			task.WithOnDependenciesMet(func() {
 				wp.logger.Debug("Dependencies met for task: %s, will be scheduled soon", task.name)
-				// 依赖满足时，重新提交任务
-				wp.Submit(task)
+				wp.markDependenciesMet(task)
 			})
 
 			// 将任务放回队列末尾，避免一直检查同一个任务
-			time.Sleep(500 * time.Millisecond)
+			// 任务发送到任务通道之前一直占用提交时获取的队列槽位，放回队列不需要重新占用
+			sleptFully := wp.sleep(500 * time.Millisecond)
 			wp.taskQueue.Enqueue(task)
+			if !sleptFully {
+				wp.logger.Debug("Scheduler stopped: context canceled")
+				return
+			}
 			continue
 		}
 
+		// 等待调度速率限制的令牌，工作池停止时将任务放回队列
+		if wp.dispatchLimiter != nil && wp.dispatchLimiter.wait(wp.ctx) != nil {
+			wp.taskQueue.Enqueue(task)
+			wp.logger.Debug("Scheduler stopped while waiting for rate limit: %s", task.name)
+			return
+		}
+
 		// 将任务发送到任务通道
 		select {
 		case <-wp.ctx.Done():
@@ -259,55 +813,187 @@ func (wp *WorkerPool) scheduler() {
 			wp.logger.Debug("Scheduler stopped while dispatching task: %s", task.name)
 			return
 		case wp.taskChan <- task:
+			// 任务离开队列，释放槽位
+			wp.releaseSlot()
 			wp.logger.Debug("Task scheduled: %s (priority: %d)", task.name, task.priority)
 		}
 	}
 }
 
+// dropCancelled 丢弃从队列中取出的已取消任务，释放其队列槽位并计为结束，返回任务是否被丢弃
+// 任务可能在调度协程等待依赖、重新入队的间隙被 CancelWhere 或 Task.Stop 取消，此时它已不在 RemoveWhere 的范围内
+func (wp *WorkerPool) dropCancelled(task *Task) bool {
+	wp.tasksMutex.Lock()
+	info, exists := wp.tasks[task.id]
+	poolCancelled := exists && info.Status == TaskStatusCancelled
+	if !poolCancelled && task.GetState() != TaskStateCancelled {
+		wp.tasksMutex.Unlock()
+		return false
+	}
+	if exists && !poolCancelled {
+		info.Status = TaskStatusCancelled
+		info.EndTime = time.Now()
+	}
+	delete(wp.depReadyAt, task.id)
+	wp.tasksMutex.Unlock()
+
+	wp.releaseSlot()
+	atomic.AddInt64(&wp.unfinished, -1)
+	wp.logger.Debug("Dropping cancelled task: %s", task.name)
+	return true
+}
+
+// markDependenciesMet 记录设置了依赖延迟的任务在依赖满足后可以调度的时间
+// 依赖满足的回调可能被多次调用，只记录第一次
+func (wp *WorkerPool) markDependenciesMet(task *Task) {
+	if task.dependencyDelay <= 0 {
+		return
+	}
+
+	wp.tasksMutex.Lock()
+	defer wp.tasksMutex.Unlock()
+
+	if _, exists := wp.depReadyAt[task.id]; !exists {
+		wp.depReadyAt[task.id] = time.Now().Add(task.dependencyDelay)
+	}
+}
+
+// dependenciesReady 检查任务的依赖是否满足，且依赖满足后的延迟已经过去
+// 与直接调用 Run 一致，只有等待过依赖的任务才会延迟，提交时依赖已满足的任务立即调度
+func (wp *WorkerPool) dependenciesReady(task *Task) bool {
+	if !task.AreDependenciesMet() {
+		return false
+	}
+
+	wp.tasksMutex.Lock()
+	defer wp.tasksMutex.Unlock()
+
+	readyAt, waiting := wp.depReadyAt[task.id]
+	if !waiting {
+		return true
+	}
+	if time.Now().Before(readyAt) {
+		return false
+	}
+	delete(wp.depReadyAt, task.id)
+	return true
+}
+
+// sleep 等待 d，工作池停止时提前返回 false
+func (wp *WorkerPool) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-wp.ctx.Done():
+		return false
+	}
+}
+
 // worker 是工作协程的主函数
 func (wp *WorkerPool) worker(id int) {
 	defer wp.wg.Done()
+	defer atomic.AddInt64(&wp.liveWorkers, -1)
 
 	wp.logger.Debug("Worker %d started", id)
 
 	for {
+		quit, wake := wp.claimQuit()
+		if quit {
+			wp.logger.Debug("Worker %d stopped: pool resized", id)
+			return
+		}
+
 		select {
 		case <-wp.ctx.Done():
 			wp.logger.Debug("Worker %d stopped: context canceled", id)
 			return
+		case <-wake:
+			// 工作池已缩容，回到循环开头检查是否需要退出
+			continue
 		case task, ok := <-wp.taskChan:
 			if !ok {
 				wp.logger.Debug("Worker %d stopped: task channel closed", id)
 				return
 			}
 
-			wp.logger.Debug("Worker %d executing task: %s", id, task.name)
+			// 缩容与取到任务同时发生时，将任务放回队列交给其余工作协程后退出
+			if quit, _ := wp.claimQuit(); quit {
+				wp.logger.Debug("Worker %d stopped: pool resized, re-enqueuing task: %s", id, task.name)
+				wp.requeue(task)
+				return
+			}
 
-			// 更新任务状态为运行中
+			// 更新任务状态为运行中，已被 CancelWhere 取消的任务不再执行
 			wp.tasksMutex.Lock()
-			if info, exists := wp.tasks[task.name]; exists {
+			info, exists := wp.tasks[task.id]
+			if exists && info.Status == TaskStatusCancelled {
+				wp.tasksMutex.Unlock()
+				wp.logger.Debug("Worker %d skipping cancelled task: %s", id, task.name)
+				atomic.AddInt64(&wp.unfinished, -1)
+				continue
+			}
+			if exists {
 				info.Status = TaskStatusRunning
 				info.WorkerID = id
 				info.StartTime = time.Now()
 			}
 			wp.tasksMutex.Unlock()
 
+			wp.logger.Debug("Worker %d executing task: %s", id, task.name)
+
+			atomic.AddInt64(&wp.activeTasks, 1)
+
 			// 调用任务开始回调
 			wp.onTaskStart(task)
 
 			// 创建一个通道来接收任务完成信号
+			// 周期性任务每次迭代都会调用后置钩子，因此只关闭一次
+			// taskErr 是首次迭代的结果，在关闭 done 之前写入；之后的迭代只更新 iterationErr
 			done := make(chan struct{})
-			var taskErr error
+			var doneOnce sync.Once
+			var taskErr, iterationErr error
 
 			// 启动一个协程来监控任务执行
 			go func() {
+				// 任务 panic 时标记为失败并结束等待，避免工作协程一直阻塞
+				recoverTask := func(r any) {
+					doneOnce.Do(func() {
+						taskErr = fmt.Errorf("panic: %v", r)
+						close(done)
+					})
+					if wp.poolRecover != nil {
+						wp.poolRecover(task, r)
+					}
+				}
+				defer func() {
+					if r := recover(); r != nil {
+						wp.logger.Error("Worker %d recovered from panic in task %s: %v", id, task.name, r)
+						recoverTask(r)
+					}
+				}()
+
+				// 任务执行中的 panic 由任务自身恢复，通过恢复钩子获知
+				originalRecoverHook := task.recoverHook
+				task.recoverHook = func(r any) {
+					if originalRecoverHook != nil {
+						originalRecoverHook(r)
+					}
+					recoverTask(r)
+				}
+
 				// 设置任务完成回调
 				originalPostHook := task.postHook
 				task.postHook = func() {
 					if originalPostHook != nil {
 						originalPostHook()
 					}
-					close(done)
+					doneOnce.Do(func() {
+						taskErr = iterationErr
+						close(done)
+					})
 				}
 
 				// 设置任务错误处理器
@@ -316,20 +1002,40 @@ func (wp *WorkerPool) worker(id int) {
 					if originalErrorHandler != nil {
 						originalErrorHandler(err)
 					}
-					taskErr = err
+					iterationErr = err
+				}
+
+				// 使任务上下文继承工作池的基础上下文
+				if wp.baseCtx != nil {
+					task.inheritContext(wp.ctx)
 				}
 
 				// 执行任务
 				task.Run()
+
+				// 任务在开始执行前已被停止或配置无效时 Run 直接返回，不会调用后置钩子
+				if state := task.GetState(); state == TaskStateCancelled || state == TaskStateFailed {
+					doneOnce.Do(func() {
+						taskErr = task.GetLastError()
+						if taskErr == nil {
+							taskErr = context.Canceled
+						}
+						close(done)
+					})
+				}
 			}()
 
 			// 等待任务完成或工作池停止
 			select {
 			case <-done:
-				// 任务正常完成
+				atomic.AddInt64(&wp.activeTasks, -1)
+
+				// 任务正常完成，被 CancelWhere 取消的任务保持已取消状态
 				wp.tasksMutex.Lock()
-				if info, exists := wp.tasks[task.name]; exists {
-					if taskErr != nil {
+				if info, exists := wp.tasks[task.id]; exists {
+					if info.Status == TaskStatusCancelled {
+						// 已取消，只记录结束时间
+					} else if taskErr != nil {
 						info.Status = TaskStatusFailed
 						info.Error = taskErr
 						atomic.AddInt64(&wp.failedTasks, 1)
@@ -345,19 +1051,23 @@ func (wp *WorkerPool) worker(id int) {
 				wp.onTaskFinish(task, taskErr)
 
 				wp.logger.Debug("Worker %d completed task: %s, error: %v", id, task.name, taskErr)
+				atomic.AddInt64(&wp.unfinished, -1)
 
 			case <-wp.ctx.Done():
+				atomic.AddInt64(&wp.activeTasks, -1)
+
 				// 工作池停止，取消任务
 				task.Stop()
 
 				wp.tasksMutex.Lock()
-				if info, exists := wp.tasks[task.name]; exists {
+				if info, exists := wp.tasks[task.id]; exists {
 					info.Status = TaskStatusCancelled
 					info.EndTime = time.Now()
 				}
 				wp.tasksMutex.Unlock()
 
 				wp.logger.Debug("Worker %d cancelled task: %s due to pool shutdown", id, task.name)
+				atomic.AddInt64(&wp.unfinished, -1)
 				return
 			}
 		}
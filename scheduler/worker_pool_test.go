@@ -2,7 +2,11 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -106,6 +110,317 @@ func TestWorkerPoolSubmit(t *testing.T) {
 	pool.Stop()
 }
 
+// TestWorkerPoolSubmitFunc 测试直接提交函数到工作池
+func TestWorkerPoolSubmitFunc(t *testing.T) {
+	pool := NewWorkerPool(1, nil)
+	pool.Start()
+
+	var wg sync.WaitGroup
+	var executed int32
+	for _, priority := range []Priority{PriorityLow, PriorityNormal, PriorityHigh} {
+		wg.Add(1)
+		err := pool.SubmitFunc(fmt.Sprintf("Func%d", priority), priority, func(ctx context.Context) error {
+			defer wg.Done()
+			atomic.AddInt32(&executed, 1)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("SubmitFunc failed: %v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Timeout waiting for functions, %d of 3 executed", atomic.LoadInt32(&executed))
+	}
+
+	pool.Stop()
+	err := pool.SubmitFunc("Stopped", PriorityNormal, func(ctx context.Context) error { return nil })
+	if !errors.Is(err, ErrPoolStopped) {
+		t.Errorf("Expected ErrPoolStopped after Stop, got %v", err)
+	}
+}
+
+// TestWorkerPoolSubmitDedup 测试提交去重
+func TestWorkerPoolSubmitDedup(t *testing.T) {
+	pool := NewWorkerPool(1, nil, WithSubmitDedup(true))
+	pool.Start()
+	defer pool.Stop()
+
+	var executed int32
+	release := make(chan struct{})
+	newTask := func() *Task {
+		return NewTask(
+			WithName("Dedup"),
+			WithJob(func(ctx context.Context) error {
+				atomic.AddInt32(&executed, 1)
+				<-release
+				return nil
+			}),
+		)
+	}
+
+	first := newTask()
+	if err := pool.Submit(first); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if err := pool.Submit(first); err != nil {
+		t.Errorf("Expected duplicate submit to be ignored without error, got %v", err)
+	}
+	if pool.TrySubmit(newTask()) {
+		t.Error("Expected TrySubmit of a same-named task to be rejected")
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		info, ok := pool.GetTaskInfo("Dedup")
+		if ok && info.Status == TaskStatusCompleted {
+			if info.Task != first {
+				t.Error("Expected task info to track the first submitted task")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timeout waiting for task to complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&executed); n != 1 {
+		t.Errorf("Expected 1 execution under dedup, got %d", n)
+	}
+
+	// 之前的任务结束后可以再次提交同名任务
+	if !pool.TrySubmit(newTask()) {
+		t.Error("Expected same-named task to be accepted after the first finished")
+	}
+}
+
+// TestWorkerPoolSameNameTracking 测试同名任务各自拥有独立的状态
+func TestWorkerPoolSameNameTracking(t *testing.T) {
+	pool := NewWorkerPool(2, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	var wg sync.WaitGroup
+	tasks := make([]*Task, 5)
+	for i := range tasks {
+		i := i
+		wg.Add(1)
+		tasks[i] = NewTask(
+			WithName("TestTask"),
+			WithJob(func(ctx context.Context) error {
+				defer wg.Done()
+				if i%2 == 1 {
+					return fmt.Errorf("task %d failed", i)
+				}
+				return nil
+			}),
+		)
+		if err := pool.Submit(tasks[i]); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for i, task := range tasks {
+		want := TaskStatusCompleted
+		if i%2 == 1 {
+			want = TaskStatusFailed
+		}
+		for {
+			info, ok := pool.GetTaskInfoByID(task.ID())
+			if !ok {
+				t.Fatalf("Expected task %d to be tracked", i)
+			}
+			if info.Task != task {
+				t.Fatalf("Expected task %d entry to reference its own task", i)
+			}
+			if info.Status == want {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected task %d status %d, got %d", i, want, info.Status)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if n := len(pool.GetAllTasksInfo()); n != 5 {
+		t.Errorf("Expected 5 independent entries, got %d", n)
+	}
+	if info, ok := pool.GetTaskInfo("TestTask"); !ok || info.Task != tasks[4] {
+		t.Error("Expected name lookup to return the most recently submitted task")
+	}
+}
+
+// TestWorkerPoolCancelWhereThenShutdown 测试取消等待依赖的任务后 Shutdown 能够完成
+// 等待依赖的任务大部分时间由调度协程持有，不在队列中，取消后重新入队时应被丢弃
+func TestWorkerPoolCancelWhereThenShutdown(t *testing.T) {
+	pool := NewWorkerPool(1, nil, WithMaxQueueSize(2))
+	pool.Start()
+	defer pool.Stop()
+
+	// 依赖永远不会执行
+	never := NewTask(WithName("Never"), WithJob(func(ctx context.Context) error { return nil }))
+	cancelled := NewTask(WithName("Cancelled"), WithJob(func(ctx context.Context) error { return nil })).DependsOn(never)
+	stopped := NewTask(WithName("Stopped"), WithJob(func(ctx context.Context) error { return nil })).DependsOn(never)
+	pool.Submit(cancelled)
+	pool.Submit(stopped)
+
+	// 等待调度协程取出任务并开始等待依赖
+	time.Sleep(50 * time.Millisecond)
+	if n := pool.CancelWhere(func(task *Task) bool { return task.GetName() == "Cancelled" }); n != 1 {
+		t.Fatalf("Expected 1 task to be cancelled, got %d", n)
+	}
+	stopped.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected shutdown to complete after cancelling waiting tasks, got %v", err)
+	}
+	if n := pool.QueueLength(); n != 0 {
+		t.Errorf("Expected empty queue after shutdown, got %d", n)
+	}
+	if info, _ := pool.GetTaskInfo("Stopped"); info.Status != TaskStatusCancelled {
+		t.Errorf("Expected stopped task to be marked cancelled, got %v", info.Status)
+	}
+}
+
+// TestWorkerPoolCancelWhere 测试按标签取消任务
+func TestWorkerPoolCancelWhere(t *testing.T) {
+	pool := NewWorkerPool(1, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	started, stopped := make(chan struct{}), make(chan struct{})
+	blocker := NewTask(
+		WithName("Blocker"),
+		WithTags("db"),
+		WithJob(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			close(stopped)
+			return ctx.Err()
+		}),
+	)
+	if err := pool.Submit(blocker); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for blocker to start")
+	}
+
+	var dbRuns, webRuns int32
+	for i := 0; i < 2; i++ {
+		pool.Submit(NewTask(WithName(fmt.Sprintf("DB%d", i)), WithTags("db"), WithJob(func(ctx context.Context) error {
+			atomic.AddInt32(&dbRuns, 1)
+			return nil
+		})))
+		pool.Submit(NewTask(WithName(fmt.Sprintf("Web%d", i)), WithTags("web"), WithJob(func(ctx context.Context) error {
+			atomic.AddInt32(&webRuns, 1)
+			return nil
+		})))
+	}
+
+	isDB := func(task *Task) bool { return slices.Contains(task.GetTags(), "db") }
+	if n := len(pool.ListTasks(isDB)); n != 3 {
+		t.Errorf("Expected 3 db tasks listed, got %d", n)
+	}
+	if n := pool.CancelWhere(isDB); n != 3 {
+		t.Errorf("Expected 3 tasks cancelled, got %d", n)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&webRuns) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timeout waiting for web tasks, %d of 2 executed", atomic.LoadInt32(&webRuns))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if n := atomic.LoadInt32(&dbRuns); n != 0 {
+		t.Errorf("Expected cancelled db tasks not to run, got %d runs", n)
+	}
+	for _, info := range pool.ListTasks(isDB) {
+		if info.Status != TaskStatusCancelled {
+			t.Errorf("Expected task %s to be cancelled, got status %d", info.Task.GetName(), info.Status)
+		}
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("Expected running blocker to be stopped")
+	}
+	if n := pool.CancelWhere(isDB); n != 0 {
+		t.Errorf("Expected nothing left to cancel, got %d", n)
+	}
+	if pool.QueueLength() != 0 {
+		t.Errorf("Expected empty queue, got %d", pool.QueueLength())
+	}
+}
+
+// TestWorkerPoolResultCacheHit 测试缓存命中的任务在工作池中正常完成
+func TestWorkerPoolResultCacheHit(t *testing.T) {
+	pool := NewWorkerPool(1, nil)
+	pool.Start()
+
+	var executions int32
+	task := NewTask(
+		WithName("Cached"),
+		WithResultCache(time.Minute),
+		WithJob(func(ctx context.Context) error {
+			atomic.AddInt32(&executions, 1)
+			return nil
+		}),
+	)
+
+	waitCompleted := func() {
+		deadline := time.Now().Add(time.Second)
+		for {
+			if info, ok := pool.GetTaskInfoByID(task.ID()); ok && info.Status == TaskStatusCompleted {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("Timeout waiting for task to complete")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if err := pool.Submit(task); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitCompleted()
+
+	// 缓存有效期内再次提交，任务函数不执行，工作池仍然记录任务完成
+	if err := pool.Submit(task); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitCompleted()
+	if n := atomic.LoadInt32(&executions); n != 1 {
+		t.Errorf("Expected the cached run to skip the job, got %d executions", n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Errorf("Expected shutdown to finish, got %v", err)
+	}
+}
+
 // TestWorkerPoolPriority 测试工作池任务优先级
 func TestWorkerPoolPriority(t *testing.T) {
 	pool := NewWorkerPool(1, nil)
@@ -230,3 +545,620 @@ func TestWorkerPoolConcurrency(t *testing.T) {
 	// 停止工作池
 	pool.Stop()
 }
+
+// newQueueTestTask 创建用于队列容量测试的任务
+func newQueueTestTask(name string) *Task {
+	return NewTask(
+		WithName(name),
+		WithJob(func(ctx context.Context) error {
+			return nil
+		}),
+	)
+}
+
+// TestWorkerPoolRejectError 测试队列已满时返回错误
+func TestWorkerPoolRejectError(t *testing.T) {
+	pool := NewWorkerPool(1, nil, WithMaxQueueSize(2), WithRejectPolicy(RejectError))
+	// 只标记为运行中，不启动调度协程，保证队列不会被消费
+	pool.running = true
+
+	for i := 0; i < 2; i++ {
+		if err := pool.Submit(newQueueTestTask("Task")); err != nil {
+			t.Fatalf("Expected submit %d to succeed, got %v", i, err)
+		}
+	}
+
+	if err := pool.Submit(newQueueTestTask("Overflow")); err != ErrQueueFull {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+
+	if pool.TrySubmit(newQueueTestTask("Overflow")) {
+		t.Error("Expected TrySubmit to fail when queue is full, but it succeeded")
+	}
+
+	if pool.QueueLength() != 2 {
+		t.Errorf("Expected queue length to be 2, got %d", pool.QueueLength())
+	}
+}
+
+// TestWorkerPoolRejectDrop 测试队列已满时丢弃任务
+func TestWorkerPoolRejectDrop(t *testing.T) {
+	pool := NewWorkerPool(1, nil, WithMaxQueueSize(2), WithRejectPolicy(RejectDrop))
+	pool.running = true
+
+	for i := 0; i < 3; i++ {
+		if err := pool.Submit(newQueueTestTask("Task")); err != nil {
+			t.Errorf("Expected submit %d to return nil, got %v", i, err)
+		}
+	}
+
+	if pool.QueueLength() != 2 {
+		t.Errorf("Expected queue length to be 2 after dropping, got %d", pool.QueueLength())
+	}
+}
+
+// TestWorkerPoolRejectBlock 测试队列已满时阻塞提交
+func TestWorkerPoolRejectBlock(t *testing.T) {
+	pool := NewWorkerPool(1, nil, WithMaxQueueSize(2), WithRejectPolicy(RejectBlock))
+	pool.running = true
+
+	pool.Submit(newQueueTestTask("Task1"))
+	pool.Submit(newQueueTestTask("Task2"))
+
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- pool.Submit(newQueueTestTask("Task3"))
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("Expected submit to block when queue is full, but it returned")
+	case <-time.After(50 * time.Millisecond):
+		// 仍在阻塞
+	}
+
+	// 模拟调度协程取走一个任务
+	pool.taskQueue.Dequeue()
+	pool.releaseSlot()
+
+	select {
+	case err := <-submitted:
+		if err != nil {
+			t.Errorf("Expected blocked submit to succeed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected blocked submit to proceed after a slot was freed")
+	}
+
+	if pool.QueueLength() != 2 {
+		t.Errorf("Expected queue length to be 2, got %d", pool.QueueLength())
+	}
+
+	// 工作池停止时阻塞的提交应返回
+	go func() {
+		submitted <- pool.Submit(newQueueTestTask("Task4"))
+	}()
+	time.Sleep(20 * time.Millisecond)
+	pool.cancelFunc()
+
+	select {
+	case err := <-submitted:
+		if err != ErrPoolStopped {
+			t.Errorf("Expected ErrPoolStopped, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected blocked submit to return after the pool was cancelled")
+	}
+}
+
+// runConcurrencyProbe 提交 n 个慢任务并返回观察到的最大并发数
+func runConcurrencyProbe(t *testing.T, pool *WorkerPool, n int, d time.Duration) int {
+	var mu sync.Mutex
+	running, maxRunning, done := 0, 0, 0
+	allDone := make(chan struct{})
+
+	for i := 0; i < n; i++ {
+		task := NewTask(
+			WithName(fmt.Sprintf("ProbeTask-%d", i)),
+			WithJob(func(ctx context.Context) error {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+
+				time.Sleep(d)
+
+				mu.Lock()
+				running--
+				done++
+				if done == n {
+					close(allDone)
+				}
+				mu.Unlock()
+				return nil
+			}),
+		)
+		pool.Submit(task)
+	}
+
+	select {
+	case <-allDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for tasks to complete")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return maxRunning
+}
+
+// TestWorkerPoolResizeGrow 测试运行时扩容工作池
+func TestWorkerPoolResizeGrow(t *testing.T) {
+	pool := NewWorkerPool(2, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	pool.Resize(5)
+	if pool.Size() != 5 {
+		t.Errorf("Expected pool size to be 5, got %d", pool.Size())
+	}
+
+	maxRunning := runConcurrencyProbe(t, pool, 10, 200*time.Millisecond)
+	if maxRunning <= 2 {
+		t.Errorf("Expected concurrency to exceed 2 after resize, got %d", maxRunning)
+	}
+}
+
+// TestWorkerPoolResizeShrink 测试运行时缩容工作池
+func TestWorkerPoolResizeShrink(t *testing.T) {
+	pool := NewWorkerPool(3, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	// 缩容在 Resize 返回时即生效，多余的工作协程取到任务时会放回队列并退出
+	pool.Resize(1)
+
+	maxRunning := runConcurrencyProbe(t, pool, 4, 50*time.Millisecond)
+	if maxRunning != 1 {
+		t.Errorf("Expected concurrency to be 1 after shrinking, got %d", maxRunning)
+	}
+}
+
+// TestWorkerPoolResizeShrinkThenGrow 测试缩容后立即扩容不会丢失工作协程
+func TestWorkerPoolResizeShrinkThenGrow(t *testing.T) {
+	pool := NewWorkerPool(3, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	pool.Resize(1)
+	pool.Resize(3)
+
+	maxRunning := runConcurrencyProbe(t, pool, 6, 200*time.Millisecond)
+	if maxRunning != 3 {
+		t.Errorf("Expected concurrency to be 3 after shrinking and growing, got %d", maxRunning)
+	}
+	if workers := pool.Metrics().Workers; workers != 3 {
+		t.Errorf("Expected 3 live workers, got %d", workers)
+	}
+}
+
+// TestWorkerPoolRequeueQueueFull 测试放回队列的任务与 Submit 一样受队列长度限制
+func TestWorkerPoolRequeueQueueFull(t *testing.T) {
+	pool := NewWorkerPool(1, nil, WithMaxQueueSize(1), WithRejectPolicy(RejectError))
+	pool.running = true
+
+	// 模拟任务 A 已被调度离开队列，随后任务 B 占满队列
+	if err := pool.Submit(newQueueTestTask("A")); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	dispatched := pool.taskQueue.Dequeue()
+	pool.releaseSlot()
+	if err := pool.Submit(newQueueTestTask("B")); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// 队列已满时 A 不能放回队列，被标记为已取消
+	pool.requeue(dispatched)
+	if n := pool.QueueLength(); n != 1 {
+		t.Errorf("Expected queue length to stay at 1, got %d", n)
+	}
+	if info, _ := pool.GetTaskInfo("A"); info.Status != TaskStatusCancelled {
+		t.Errorf("Expected rejected task to be cancelled, got %v", info.Status)
+	}
+	if n := atomic.LoadInt64(&pool.unfinished); n != 1 {
+		t.Errorf("Expected 1 unfinished task, got %d", n)
+	}
+}
+
+// TestWorkerPoolSubmitRateLimit 测试提交速率限制
+func TestWorkerPoolSubmitRateLimit(t *testing.T) {
+	pool := NewWorkerPool(1, nil, WithSubmitRateLimit(5, 100*time.Millisecond))
+	pool.running = true
+
+	// 前 5 次为突发容量，之后每 20ms 放行一次
+	start := time.Now()
+	for i := 0; i < 15; i++ {
+		if err := pool.Submit(newQueueTestTask(fmt.Sprintf("Task-%d", i))); err != nil {
+			t.Fatalf("Expected submit %d to succeed, got %v", i, err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < 180*time.Millisecond {
+		t.Errorf("Expected 15 submissions to be paced over at least 180ms, took %v", elapsed)
+	}
+	if elapsed > time.Second {
+		t.Errorf("Expected submissions to finish in about 200ms, took %v", elapsed)
+	}
+}
+
+// TestWorkerPoolSubmitRateLimitError 测试提交速率超限时返回错误
+func TestWorkerPoolSubmitRateLimitError(t *testing.T) {
+	pool := NewWorkerPool(1, nil, WithSubmitRateLimit(3, time.Second), WithRejectPolicy(RejectError))
+	pool.running = true
+
+	for i := 0; i < 3; i++ {
+		if err := pool.Submit(newQueueTestTask("Task")); err != nil {
+			t.Fatalf("Expected submit %d to succeed, got %v", i, err)
+		}
+	}
+
+	if err := pool.Submit(newQueueTestTask("Overflow")); err != ErrRateLimited {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if pool.TrySubmit(newQueueTestTask("Overflow")) {
+		t.Error("Expected TrySubmit to fail when rate limited, but it succeeded")
+	}
+	if pool.QueueLength() != 3 {
+		t.Errorf("Expected queue length to be 3, got %d", pool.QueueLength())
+	}
+}
+
+// baseContextKey 是测试中基础上下文值的键类型
+type baseContextKey struct{}
+
+// TestWorkerPoolBaseContext 测试任务继承工作池的基础上下文
+func TestWorkerPoolBaseContext(t *testing.T) {
+	base, cancel := context.WithCancel(context.WithValue(context.Background(), baseContextKey{}, "tenant-a"))
+	defer cancel()
+
+	pool := NewWorkerPool(2, nil, WithBaseContext(base))
+	pool.Start()
+	defer pool.Stop()
+
+	values := make(chan interface{}, 1)
+	pool.Submit(NewTask(
+		WithName("ValueTask"),
+		WithJob(func(ctx context.Context) error {
+			values <- ctx.Value(baseContextKey{})
+			return nil
+		}),
+	))
+
+	select {
+	case value := <-values:
+		if value != "tenant-a" {
+			t.Errorf("Expected job to read base context value tenant-a, got %v", value)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for task to run")
+	}
+
+	// 取消基础上下文应停止正在运行的任务
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	task := NewTask(
+		WithName("LongTask"),
+		WithJob(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			close(stopped)
+			return ctx.Err()
+		}),
+	)
+	pool.Submit(task)
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for long task to start")
+	}
+
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected base context cancellation to stop the running task")
+	}
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Error("Expected task Done channel to close after base cancellation")
+	}
+}
+
+// TestWorkerPoolRecover 测试任务 panic 后工作协程继续处理后续任务
+func TestWorkerPoolRecover(t *testing.T) {
+	recovered := make(chan any, 2)
+	pool := NewWorkerPool(1, nil, WithPoolRecover(func(task *Task, r any) {
+		recovered <- r
+	}))
+	pool.Start()
+	defer pool.Stop()
+
+	pool.Submit(NewTask(
+		WithName("PanicTask"),
+		WithJob(func(ctx context.Context) error {
+			panic("boom")
+		}),
+	))
+	// 没有设置任务函数的任务在 Run 中 panic
+	pool.Submit(NewTask(WithName("NoJobTask")))
+
+	executed := make(chan struct{})
+	pool.Submit(NewTask(
+		WithName("NormalTask"),
+		WithJob(func(ctx context.Context) error {
+			close(executed)
+			return nil
+		}),
+	))
+
+	select {
+	case <-executed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected normal task to run after panicking tasks")
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-recovered:
+		case <-time.After(time.Second):
+			t.Fatal("Expected recover callback to be called")
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	_, completed, failed := pool.GetStats()
+	if failed != 2 || completed != 1 {
+		t.Errorf("Expected 2 failed and 1 completed tasks, got %d and %d", failed, completed)
+	}
+	if info, _ := pool.GetTaskInfo("PanicTask"); info == nil || info.Status != TaskStatusFailed || info.Error == nil {
+		t.Errorf("Expected PanicTask to be marked failed, got %+v", info)
+	}
+}
+
+// TestWorkerPoolMetrics 测试工作池运行状况快照
+func TestWorkerPoolMetrics(t *testing.T) {
+	pool := NewWorkerPool(2, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(NewTask(
+		WithName("Blocking"),
+		WithJob(func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		}),
+	))
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for blocking task to start")
+	}
+	if m := pool.Metrics(); m.Running != 1 || m.Workers != 2 {
+		t.Errorf("Expected 1 running task and 2 workers, got %+v", m)
+	}
+	close(release)
+
+	for i := 0; i < 4; i++ {
+		fail := i%2 == 1
+		pool.Submit(NewTask(
+			WithName(fmt.Sprintf("Task%d", i)),
+			WithJob(func(ctx context.Context) error {
+				if fail {
+					return errors.New("failed")
+				}
+				return nil
+			}),
+		))
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		m := pool.Metrics()
+		if m.Completed+m.Failed == 5 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timeout waiting for tasks to finish: %+v", m)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	want := PoolMetrics{Completed: 3, Failed: 2, Workers: 2}
+	if m := pool.Metrics(); m != want {
+		t.Errorf("Expected %+v, got %+v", want, m)
+	}
+}
+
+// TestWorkerPoolShutdown 测试优雅关闭时执行完队列中的任务
+func TestWorkerPoolShutdown(t *testing.T) {
+	pool := NewWorkerPool(1, nil)
+	pool.Start()
+
+	var completed int32
+	for i := 0; i < 4; i++ {
+		pool.Submit(NewTask(
+			WithName(fmt.Sprintf("Task%d", i)),
+			WithJob(func(ctx context.Context) error {
+				select {
+				case <-time.After(50 * time.Millisecond):
+					atomic.AddInt32(&completed, 1)
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}),
+		))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected shutdown to succeed, got %v", err)
+	}
+
+	if n := atomic.LoadInt32(&completed); n != 4 {
+		t.Errorf("Expected 4 completed tasks, got %d", n)
+	}
+	for name, info := range pool.GetAllTasksInfo() {
+		if info.Status != TaskStatusCompleted {
+			t.Errorf("Expected task %s to be completed, got status %d", name, info.Status)
+		}
+	}
+	if pool.isRunning() {
+		t.Error("Expected pool to be stopped after shutdown")
+	}
+	if err := pool.Submit(NewTask(WithName("Late"))); !errors.Is(err, ErrPoolStopped) {
+		t.Errorf("Expected ErrPoolStopped after shutdown, got %v", err)
+	}
+}
+
+// TestWorkerPoolShutdownTimeout 测试优雅关闭超时时返回上下文错误
+func TestWorkerPoolShutdownTimeout(t *testing.T) {
+	pool := NewWorkerPool(1, nil)
+	pool.Start()
+
+	pool.Submit(NewTask(
+		WithName("Slow"),
+		WithJob(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if pool.isRunning() {
+		t.Error("Expected pool to be stopped after shutdown timeout")
+	}
+}
+
+// TestWorkerPoolRateLimit 测试工作池限制任务开始执行的总速率
+func TestWorkerPoolRateLimit(t *testing.T) {
+	pool := NewWorkerPool(4, nil, WithPoolRateLimit(5, 1))
+	pool.Start()
+
+	var completed int32
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		pool.Submit(NewTask(
+			WithName(fmt.Sprintf("Task%d", i)),
+			WithJob(func(ctx context.Context) error {
+				atomic.AddInt32(&completed, 1)
+				return nil
+			}),
+		))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected shutdown to succeed, got %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if n := atomic.LoadInt32(&completed); n != 10 {
+		t.Errorf("Expected 10 completed tasks, got %d", n)
+	}
+	// 第一个任务立即开始，之后每 200ms 开始一个
+	if elapsed < time.Second {
+		t.Errorf("Expected 10 tasks at 5/sec to span at least ~1s, took %v", elapsed)
+	}
+
+	// 等待令牌期间停止工作池
+	slow := NewWorkerPool(1, nil, WithPoolRateLimit(0.1, 1))
+	slow.Start()
+	for i := 0; i < 3; i++ {
+		slow.Submit(NewTask(
+			WithName(fmt.Sprintf("Slow%d", i)),
+			WithJob(func(ctx context.Context) error { return nil }),
+		))
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		slow.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("Expected Stop to unblock the rate limited scheduler")
+	}
+}
+
+// TestWorkerPoolRunAfterDelay 测试通过工作池执行时同样遵守 RunAfterDelay 设置的延迟
+func TestWorkerPoolRunAfterDelay(t *testing.T) {
+	pool := NewWorkerPool(2, nil)
+	pool.Start()
+	defer pool.Stop()
+
+	var mu sync.Mutex
+	var upstreamDone, downstreamStart time.Time
+
+	upstream := NewTask(
+		WithName("Upstream"),
+		WithJob(func(ctx context.Context) error {
+			mu.Lock()
+			upstreamDone = time.Now()
+			mu.Unlock()
+			return nil
+		}),
+	)
+
+	started := make(chan struct{})
+	downstream := NewTask(
+		WithName("Downstream"),
+		WithJob(func(ctx context.Context) error {
+			mu.Lock()
+			downstreamStart = time.Now()
+			mu.Unlock()
+			close(started)
+			return nil
+		}),
+	)
+
+	// 延迟长于调度协程重新检查依赖的间隔，确保等待来自延迟本身
+	delay := 800 * time.Millisecond
+	RunAfterDelay(downstream, delay, upstream)
+
+	pool.Submit(downstream)
+	pool.Submit(upstream)
+
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timeout waiting for downstream task to start")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if elapsed := downstreamStart.Sub(upstreamDone); elapsed < delay {
+		t.Errorf("Expected downstream to start at least %v after upstream, got %v", delay, elapsed)
+	}
+}
@@ -0,0 +1,71 @@
+// storage/export.go
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ExportTasks 将存储中的所有任务以 JSON 数组的形式写入 w
+func ExportTasks(s Storage, w io.Writer) error {
+	tasks, err := s.ListTasks()
+	if err != nil {
+		return err
+	}
+	if tasks == nil {
+		tasks = []*TaskInfo{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(tasks)
+}
+
+// ImportTasks 从 r 读取 ExportTasks 导出的 JSON 数组并保存到存储中，返回导入的任务数
+// 新任务会重新分配ID；名称已存在的任务在 overwrite 为 true 时覆盖原任务，否则跳过
+func ImportTasks(s Storage, r io.Reader, overwrite bool) (int, error) {
+	var tasks []*TaskInfo
+	if err := json.NewDecoder(r).Decode(&tasks); err != nil {
+		return 0, fmt.Errorf("failed to decode tasks: %w", err)
+	}
+
+	existing, err := s.ListTasks()
+	if err != nil {
+		return 0, err
+	}
+	byName := make(map[string]*TaskInfo, len(existing))
+	for _, task := range existing {
+		byName[task.Name] = task
+	}
+
+	imported := 0
+	for _, task := range tasks {
+		if task == nil || task.Name == "" {
+			continue
+		}
+
+		if current, exists := byName[task.Name]; exists {
+			if !overwrite {
+				continue
+			}
+			task.ID = current.ID
+			task.CreatedAt = current.CreatedAt
+		} else {
+			task.ID = 0
+		}
+
+		// 导出时正在运行的任务在新环境中并未运行
+		if task.Status == TaskStatusRunning {
+			task.Status = TaskStatusIdle
+		}
+
+		if err := s.SaveTask(task); err != nil {
+			return imported, fmt.Errorf("failed to import task %q: %w", task.Name, err)
+		}
+		byName[task.Name] = task
+		imported++
+	}
+
+	return imported, nil
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExportImportTasks 测试导出任务并导入到空存储
+func TestExportImportTasks(t *testing.T) {
+	src := newTestStorage(t)
+	seedTasks(t, src, 4)
+
+	var buf bytes.Buffer
+	if err := ExportTasks(src, &buf); err != nil {
+		t.Fatalf("Failed to export tasks: %v", err)
+	}
+
+	dest := newTestStorage(t)
+	// 目标存储中已有任务，导入的任务应重新分配ID
+	if err := dest.SaveTask(newTestTask("existing")); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	n, err := ImportTasks(dest, bytes.NewReader(buf.Bytes()), false)
+	if err != nil {
+		t.Fatalf("Failed to import tasks: %v", err)
+	}
+	if n != 4 {
+		t.Errorf("Expected 4 imported tasks, got %d", n)
+	}
+
+	want, _ := src.ListTasks()
+	got, _ := dest.ListTasks()
+	if len(got) != len(want)+1 {
+		t.Fatalf("Expected %d tasks in destination, got %d", len(want)+1, len(got))
+	}
+	for i, task := range got[1:] {
+		if task.ID == want[i].ID {
+			t.Errorf("Expected task %q to get a new ID", task.Name)
+		}
+		if task.Name != want[i].Name || task.Type != want[i].Type || task.Content != want[i].Content ||
+			!reflect.DeepEqual(task.Tags, want[i].Tags) {
+			t.Errorf("Imported task differs: got %+v, want %+v", task, want[i])
+		}
+		// 运行中的任务导入后为空闲状态
+		if task.Status != TaskStatusIdle {
+			t.Errorf("Expected imported task %q to be idle, got %s", task.Name, task.Status)
+		}
+	}
+
+	// 不覆盖时跳过同名任务
+	n, err = ImportTasks(dest, bytes.NewReader(buf.Bytes()), false)
+	if err != nil || n != 0 {
+		t.Errorf("Expected 0 tasks imported without overwrite, got %d (%v)", n, err)
+	}
+}
+
+// TestImportTasksOverwrite 测试覆盖导入同名任务
+func TestImportTasksOverwrite(t *testing.T) {
+	s := NewMemoryStorage()
+
+	task := newTestTask("report", "daily")
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	input := `[{"name": "report", "type": "shell", "content": "echo updated", "status": "idle"},
+		{"name": "fresh", "type": "lua", "content": "print(1)", "status": "idle"}]`
+	n, err := ImportTasks(s, strings.NewReader(input), true)
+	if err != nil {
+		t.Fatalf("Failed to import tasks: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 imported tasks, got %d", n)
+	}
+
+	got, err := s.GetTaskByName("report")
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if got.ID != task.ID || got.Content != "echo updated" || !got.CreatedAt.Equal(task.CreatedAt) {
+		t.Errorf("Expected task to be updated in place, got %+v", got)
+	}
+
+	if _, err := ImportTasks(s, strings.NewReader("not json"), true); err == nil {
+		t.Error("Expected error for invalid input")
+	}
+}
@@ -0,0 +1,159 @@
+// storage/memory.go
+package storage
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryStorage 是基于内存的任务存储，适用于测试和不需要持久化的场景
+type MemoryStorage struct {
+	tasks  map[int64]*TaskInfo
+	nextID int64 // 最近分配的任务ID
+	mutex  sync.RWMutex
+}
+
+// 确保 MemoryStorage 实现了 Storage 接口
+var _ Storage = (*MemoryStorage)(nil)
+
+// NewMemoryStorage 创建一个新的内存存储
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		tasks: make(map[int64]*TaskInfo),
+	}
+}
+
+// cloneTask 复制任务信息，避免调用方修改存储中的数据
+func cloneTask(task *TaskInfo) *TaskInfo {
+	clone := *task
+	if task.Tags != nil {
+		clone.Tags = append([]string(nil), task.Tags...)
+	}
+	if task.Args != nil {
+		clone.Args = append([]string(nil), task.Args...)
+	}
+	return &clone
+}
+
+// SaveTask 保存任务
+func (s *MemoryStorage) SaveTask(task *TaskInfo) error {
+	if task == nil {
+		return errors.New("task is nil")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if task.ID == 0 {
+		// 新任务
+		task.ID = atomic.AddInt64(&s.nextID, 1)
+		task.CreatedAt = now
+	} else if existing, exists := s.tasks[task.ID]; exists {
+		// 更新任务，创建时间保持不变
+		task.CreatedAt = existing.CreatedAt
+	} else {
+		// 按原ID创建，之后分配的ID从该ID之后开始
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		if task.ID > atomic.LoadInt64(&s.nextID) {
+			atomic.StoreInt64(&s.nextID, task.ID)
+		}
+	}
+	task.UpdatedAt = now
+
+	s.tasks[task.ID] = cloneTask(task)
+	return nil
+}
+
+// GetTask 获取任务
+func (s *MemoryStorage) GetTask(id int64) (*TaskInfo, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	task, exists := s.tasks[id]
+	if !exists {
+		return nil, ErrTaskNotFound
+	}
+	return cloneTask(task), nil
+}
+
+// GetTaskByName 根据名称获取任务，名称重复时返回ID最小的任务
+func (s *MemoryStorage) GetTaskByName(name string) (*TaskInfo, error) {
+	for _, task := range s.sortedTasks() {
+		if task.Name == name {
+			return task, nil
+		}
+	}
+	return nil, ErrTaskNotFound
+}
+
+// ListTasks 列出所有任务
+func (s *MemoryStorage) ListTasks() ([]*TaskInfo, error) {
+	return s.sortedTasks(), nil
+}
+
+// GetTasksByStatus 按ID顺序列出指定状态的任务
+func (s *MemoryStorage) GetTasksByStatus(status TaskStatus) ([]*TaskInfo, error) {
+	var tasks []*TaskInfo
+	for _, task := range s.sortedTasks() {
+		if task.Status == status {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// sortedTasks 返回按ID排序的任务副本
+func (s *MemoryStorage) sortedTasks() []*TaskInfo {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tasks := make([]*TaskInfo, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		tasks = append(tasks, cloneTask(task))
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
+
+// DeleteTask 删除任务
+func (s *MemoryStorage) DeleteTask(id int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.tasks, id)
+	return nil
+}
+
+// UpdateTaskStatus 更新任务状态
+func (s *MemoryStorage) UpdateTaskStatus(id int64, status TaskStatus) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if task, exists := s.tasks[id]; exists {
+		task.Status = status
+		task.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// UpdateTaskRunInfo 更新任务运行信息
+func (s *MemoryStorage) UpdateTaskRunInfo(id int64, runCount int, lastRunAt time.Time, lastError string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if task, exists := s.tasks[id]; exists {
+		task.RunCount = runCount
+		task.LastRunAt = lastRunAt
+		task.LastError = lastError
+		task.UpdatedAt = time.Now()
+	}
+	return nil
+}
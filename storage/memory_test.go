@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMemoryStorageCRUD 测试内存存储的增删改查
+func TestMemoryStorageCRUD(t *testing.T) {
+	s := NewMemoryStorage()
+
+	task := newTestTask("backup", "daily")
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	if task.ID != 1 || task.CreatedAt.IsZero() {
+		t.Errorf("Expected ID 1 and creation time to be set, got %+v", task)
+	}
+
+	// 修改返回值不影响存储
+	got, err := s.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	got.Tags[0] = "changed"
+	got, _ = s.GetTask(task.ID)
+	if !reflect.DeepEqual(got.Tags, []string{"daily"}) {
+		t.Errorf("Expected stored tags to be unaffected, got %v", got.Tags)
+	}
+
+	// 更新
+	task.Content = "print('updated')"
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	got, _ = s.GetTaskByName("backup")
+	if got.Content != "print('updated')" {
+		t.Errorf("Expected updated content, got %q", got.Content)
+	}
+
+	if err := s.UpdateTaskStatus(task.ID, TaskStatusRunning); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	lastRun := time.Now()
+	if err := s.UpdateTaskRunInfo(task.ID, 3, lastRun, "boom"); err != nil {
+		t.Fatalf("Failed to update run info: %v", err)
+	}
+	got, _ = s.GetTask(task.ID)
+	if got.Status != TaskStatusRunning || got.RunCount != 3 || got.LastError != "boom" || !got.LastRunAt.Equal(lastRun) {
+		t.Errorf("Unexpected task after updates: %+v", got)
+	}
+
+	// 列表按ID排序
+	s.SaveTask(newTestTask("cleanup"))
+	tasks, err := s.ListTasks()
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if names := taskNames(tasks); !reflect.DeepEqual(names, []string{"backup", "cleanup"}) {
+		t.Errorf("Expected tasks [backup cleanup], got %v", names)
+	}
+
+	// 删除
+	if err := s.DeleteTask(task.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if _, err := s.GetTask(task.ID); err == nil || err.Error() != "task not found" {
+		t.Errorf("Expected task not found error, got %v", err)
+	}
+	if _, err := s.GetTaskByName("backup"); err == nil {
+		t.Error("Expected error for deleted task name, got nil")
+	}
+}
+
+// TestMemoryStorageSaveWithID 测试按指定ID保存任务
+func TestMemoryStorageSaveWithID(t *testing.T) {
+	s := NewMemoryStorage()
+
+	task := newTestTask("imported")
+	task.ID = 42
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	next := newTestTask("next")
+	s.SaveTask(next)
+	if next.ID != 43 {
+		t.Errorf("Expected next ID to be 43, got %d", next.ID)
+	}
+}
+
+// TestMemoryStorageConcurrent 测试内存存储的并发访问
+func TestMemoryStorageConcurrent(t *testing.T) {
+	s := NewMemoryStorage()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				task := newTestTask(fmt.Sprintf("task-%d-%d", i, j))
+				if err := s.SaveTask(task); err != nil {
+					t.Errorf("Failed to save task: %v", err)
+					return
+				}
+				s.UpdateTaskStatus(task.ID, TaskStatusRunning)
+				s.GetTask(task.ID)
+				s.ListTasks()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	tasks, _ := s.ListTasks()
+	if len(tasks) != 200 {
+		t.Fatalf("Expected 200 tasks, got %d", len(tasks))
+	}
+
+	seen := make(map[int64]bool)
+	for _, task := range tasks {
+		if seen[task.ID] {
+			t.Fatalf("Duplicate task ID %d", task.ID)
+		}
+		seen[task.ID] = true
+	}
+}
@@ -5,20 +5,76 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// taskColumns 是查询任务时读取的列，顺序与 scanTask 一致
+// 迁移添加的新列需要同时追加到这里和 scanTask 中
+const taskColumns = `id, name, type, content, status, interval, max_runs, retry_times, timeout,
+	created_at, updated_at, last_run_at, run_count, last_error, description, tags, options,
+	priority, cron, deleted_at, args`
+
+// notDeleted 是排除软删除任务的查询条件
+const notDeleted = `deleted_at IS NULL`
+
 // SQLiteStorage 是基于 SQLite 的任务存储
 type SQLiteStorage struct {
 	db *sql.DB
 }
 
-// NewSQLiteStorage 创建一个新的 SQLite 存储
+// SQLiteOptions 表示 SQLite 连接的配置
+type SQLiteOptions struct {
+	JournalMode  string        // 日志模式，例如 "WAL"、"DELETE"，为空时使用 SQLite 默认值
+	BusyTimeout  time.Duration // 数据库被锁定时的最长等待时间，0 表示不等待
+	MaxOpenConns int           // 最大打开连接数，0 表示不限制
+	MaxIdleConns int           // 最大空闲连接数
+}
+
+// DefaultSQLiteOptions 返回默认配置：WAL 日志模式，锁定时最多等待 5 秒
+// WAL 模式下读操作不会阻塞写操作，适合多个任务同时更新状态的场景
+func DefaultSQLiteOptions() SQLiteOptions {
+	return SQLiteOptions{
+		JournalMode:  "WAL",
+		BusyTimeout:  5 * time.Second,
+		MaxOpenConns: 8,
+		MaxIdleConns: 4,
+	}
+}
+
+// dsn 返回带连接参数的数据源名称，参数对连接池中的每个连接都生效
+func (o SQLiteOptions) dsn(dbPath string) string {
+	params := url.Values{}
+	if o.JournalMode != "" {
+		params.Set("_journal_mode", o.JournalMode)
+	}
+	if o.BusyTimeout > 0 {
+		params.Set("_busy_timeout", strconv.FormatInt(o.BusyTimeout.Milliseconds(), 10))
+	}
+	// 事务开始时即获取写锁，避免读锁升级为写锁时因并发写入直接失败
+	params.Set("_txlock", "immediate")
+
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+	return dbPath + separator + params.Encode()
+}
+
+// NewSQLiteStorage 使用默认配置创建一个新的 SQLite 存储
 func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
+	return NewSQLiteStorageWithOptions(dbPath, DefaultSQLiteOptions())
+}
+
+// NewSQLiteStorageWithOptions 使用指定配置创建一个新的 SQLite 存储
+func NewSQLiteStorageWithOptions(dbPath string, opts SQLiteOptions) (*SQLiteStorage, error) {
 	if dbPath == "" {
 		// 如果未指定数据库路径，使用默认路径
 		homeDir, err := os.UserHomeDir()
@@ -32,10 +88,12 @@ func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
 	}
 
 	// 打开数据库
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", opts.dsn(dbPath))
 	if err != nil {
 		return nil, err
 	}
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxIdleConns)
 
 	// 初始化存储
 	storage := &SQLiteStorage{db: db}
@@ -86,6 +144,107 @@ func (s *SQLiteStorage) initialize() error {
 		return err
 	}
 
+	// 创建标签关联表，便于按标签查询
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS task_tags (
+			task_id INTEGER NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (task_id, tag)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags(tag)`)
+	if err != nil {
+		return err
+	}
+
+	// 将旧数据中 JSON 列的标签迁移到标签关联表
+	if err := s.migrateTags(); err != nil {
+		return err
+	}
+
+	// 执行增量结构迁移
+	return s.migrate()
+}
+
+// migrateTags 将尚未写入标签关联表的任务标签从 JSON 列迁移过来
+func (s *SQLiteStorage) migrateTags() error {
+	rows, err := s.db.Query(`
+		SELECT id, tags FROM tasks
+		WHERE tags IS NOT NULL AND tags NOT IN ('', 'null', '[]')
+			AND id NOT IN (SELECT DISTINCT task_id FROM task_tags)
+	`)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[int64][]string)
+	for rows.Next() {
+		var id int64
+		var tagsJSON string
+		if err := rows.Scan(&id, &tagsJSON); err != nil {
+			rows.Close()
+			return err
+		}
+
+		var tags []string
+		if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+			rows.Close()
+			return err
+		}
+		pending[id] = tags
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for id, tags := range pending {
+		if err := replaceTaskTags(tx, id, tags); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// marshalArgs 将任务参数序列化为 JSON，没有参数时为空数组
+func marshalArgs(args []string) (string, error) {
+	if args == nil {
+		args = []string{}
+	}
+	data, err := json.Marshal(args)
+	return string(data), err
+}
+
+// replaceTaskTags 在事务中用给定的标签替换任务的全部标签
+func replaceTaskTags(tx *sql.Tx, taskID int64, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO task_tags (task_id, tag) VALUES (?, ?)`, taskID, tag); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -95,27 +254,96 @@ func (s *SQLiteStorage) SaveTask(task *TaskInfo) error {
 		return errors.New("task is nil")
 	}
 
-	// 序列化标签
+	// 在同一个事务中保存任务和标签
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveTaskTx(tx, task, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SaveTasks 在一个事务中批量保存任务，规则与 SaveTask 相同
+// 任意一个任务保存失败时整批回滚，已回填的 ID 和时间戳恢复为调用前的值
+func (s *SQLiteStorage) SaveTasks(tasks []*TaskInfo) error {
+	// 记录原始值，回滚时恢复
+	type savedFields struct {
+		id                   int64
+		createdAt, updatedAt time.Time
+	}
+	originals := make([]savedFields, len(tasks))
+	for i, task := range tasks {
+		if task != nil {
+			originals[i] = savedFields{task.ID, task.CreatedAt, task.UpdatedAt}
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = func() error {
+		now := time.Now()
+		for i, task := range tasks {
+			if task == nil {
+				return fmt.Errorf("task %d: task is nil", i)
+			}
+			if err := saveTaskTx(tx, task, now); err != nil {
+				return fmt.Errorf("task %d (%s): %w", i, task.Name, err)
+			}
+		}
+		return tx.Commit()
+	}()
+	if err != nil {
+		for i, task := range tasks {
+			if task == nil {
+				continue
+			}
+			task.ID = originals[i].id
+			task.CreatedAt = originals[i].createdAt
+			task.UpdatedAt = originals[i].updatedAt
+		}
+		return err
+	}
+
+	return nil
+}
+
+// saveTaskTx 在事务中保存任务和标签，新任务回填 ID
+func saveTaskTx(tx *sql.Tx, task *TaskInfo, now time.Time) error {
+	// 序列化标签和参数
 	tagsJSON, err := json.Marshal(task.Tags)
 	if err != nil {
 		return err
 	}
+	argsJSON, err := marshalArgs(task.Args)
+	if err != nil {
+		return err
+	}
 
-	now := time.Now()
 	if task.ID == 0 {
 		// 新任务
 		task.CreatedAt = now
 		task.UpdatedAt = now
 
-		result, err := s.db.Exec(`
+		result, err := tx.Exec(`
 			INSERT INTO tasks (
 				name, type, content, status, interval, max_runs, retry_times, timeout,
-				created_at, updated_at, run_count, last_error, description, tags, options
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				created_at, updated_at, run_count, last_error, description, tags, options,
+				priority, cron, args
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`,
 			task.Name, task.Type, task.Content, task.Status, task.Interval, task.MaxRuns,
 			task.RetryTimes, task.Timeout, task.CreatedAt, task.UpdatedAt, task.RunCount,
 			task.LastError, task.Description, string(tagsJSON), task.Options,
+			task.Priority, task.Cron, argsJSON,
 		)
 		if err != nil {
 			return err
@@ -130,45 +358,190 @@ func (s *SQLiteStorage) SaveTask(task *TaskInfo) error {
 		// 更新任务
 		task.UpdatedAt = now
 
-		_, err := s.db.Exec(`
+		result, err := tx.Exec(`
 			UPDATE tasks SET
 				name = ?, type = ?, content = ?, status = ?, interval = ?, max_runs = ?,
 				retry_times = ?, timeout = ?, updated_at = ?, last_run_at = ?, run_count = ?,
-				last_error = ?, description = ?, tags = ?, options = ?
+				last_error = ?, description = ?, tags = ?, options = ?, priority = ?, cron = ?,
+				args = ?
 			WHERE id = ?
 		`,
 			task.Name, task.Type, task.Content, task.Status, task.Interval, task.MaxRuns,
 			task.RetryTimes, task.Timeout, task.UpdatedAt, task.LastRunAt, task.RunCount,
-			task.LastError, task.Description, string(tagsJSON), task.Options, task.ID,
+			task.LastError, task.Description, string(tagsJSON), task.Options,
+			task.Priority, task.Cron, argsJSON, task.ID,
 		)
 		if err != nil {
 			return err
 		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		// 记录不存在时按原ID插入，便于在存储之间迁移任务
+		if affected == 0 {
+			if task.CreatedAt.IsZero() {
+				task.CreatedAt = now
+			}
+
+			_, err := tx.Exec(`
+				INSERT INTO tasks (
+					id, name, type, content, status, interval, max_runs, retry_times, timeout,
+					created_at, updated_at, last_run_at, run_count, last_error, description, tags, options,
+					priority, cron, args
+				) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`,
+				task.ID, task.Name, task.Type, task.Content, task.Status, task.Interval, task.MaxRuns,
+				task.RetryTimes, task.Timeout, task.CreatedAt, task.UpdatedAt, task.LastRunAt, task.RunCount,
+				task.LastError, task.Description, string(tagsJSON), task.Options,
+				task.Priority, task.Cron, argsJSON,
+			)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	return nil
+	// 更新标签关联表
+	return replaceTaskTags(tx, task.ID, task.Tags)
 }
 
 // GetTask 获取任务
 func (s *SQLiteStorage) GetTask(id int64) (*TaskInfo, error) {
-	row := s.db.QueryRow(`SELECT * FROM tasks WHERE id = ?`, id)
+	row := s.db.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ? AND `+notDeleted, id)
 	return s.scanTask(row)
 }
 
 // GetTaskByName 根据名称获取任务
 func (s *SQLiteStorage) GetTaskByName(name string) (*TaskInfo, error) {
-	row := s.db.QueryRow(`SELECT * FROM tasks WHERE name = ?`, name)
+	row := s.db.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE name = ? AND `+notDeleted, name)
 	return s.scanTask(row)
 }
 
 // ListTasks 列出所有任务
 func (s *SQLiteStorage) ListTasks() ([]*TaskInfo, error) {
-	rows, err := s.db.Query(`SELECT * FROM tasks ORDER BY id`)
+	rows, err := s.db.Query(`SELECT ` + taskColumns + ` FROM tasks WHERE ` + notDeleted + ` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	return s.collectTasks(rows)
+}
+
+// GetTasksByStatus 按ID顺序列出指定状态的任务
+func (s *SQLiteStorage) GetTasksByStatus(status TaskStatus) ([]*TaskInfo, error) {
+	rows, err := s.db.Query(`SELECT `+taskColumns+` FROM tasks WHERE status = ? AND `+notDeleted+` ORDER BY id`, status)
+	if err != nil {
+		return nil, err
+	}
+	return s.collectTasks(rows)
+}
+
+// ListTasksFiltered 按条件分页列出任务
+func (s *SQLiteStorage) ListTasksFiltered(opts ListOptions) ([]*TaskInfo, error) {
+	where, args := buildListWhere(opts)
+	query := `SELECT ` + taskColumns + ` FROM tasks` + where + ` ORDER BY id`
+
+	// SQLite 中 LIMIT -1 表示不限制，仅指定 OFFSET 时需要
+	if opts.Limit > 0 || opts.Offset > 0 {
+		limit := opts.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, opts.Offset)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return s.collectTasks(rows)
+}
+
+// CountTasks 统计符合条件的任务总数，忽略分页参数
+func (s *SQLiteStorage) CountTasks(opts ListOptions) (int, error) {
+	where, args := buildListWhere(opts)
+
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM tasks`+where, args...).Scan(&count)
+	return count, err
+}
+
+// buildListWhere 根据过滤条件构建 WHERE 子句，所有条件值都通过参数绑定
+func buildListWhere(opts ListOptions) (string, []interface{}) {
+	conditions := []string{notDeleted}
+	var args []interface{}
+
+	if opts.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, opts.Status)
+	}
+	if opts.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, opts.Type)
+	}
+	if opts.NamePrefix != "" {
+		conditions = append(conditions, `name LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(opts.NamePrefix)+"%")
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(s)
+}
+
+// GetTasksByTag 获取带有指定标签的所有任务
+func (s *SQLiteStorage) GetTasksByTag(tag string) ([]*TaskInfo, error) {
+	return s.ListTasksByTag(tag)
+}
+
+// ListTasksByTag 列出带有指定标签的所有任务，按ID排序
+func (s *SQLiteStorage) ListTasksByTag(tag string) ([]*TaskInfo, error) {
+	rows, err := s.db.Query(`
+		SELECT `+taskColumns+` FROM tasks
+		WHERE id IN (SELECT task_id FROM task_tags WHERE tag = ?) AND `+notDeleted+`
+		ORDER BY id
+	`, tag)
+	if err != nil {
+		return nil, err
+	}
+	return s.collectTasks(rows)
+}
+
+// ListTags 列出所有使用中的标签
+func (s *SQLiteStorage) ListTags() ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT DISTINCT tag FROM task_tags
+		WHERE task_id IN (SELECT id FROM tasks WHERE ` + notDeleted + `)
+		ORDER BY tag
+	`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// collectTasks 读取查询结果中的所有任务并关闭结果集
+func (s *SQLiteStorage) collectTasks(rows *sql.Rows) ([]*TaskInfo, error) {
+	defer rows.Close()
+
 	var tasks []*TaskInfo
 	for rows.Next() {
 		task, err := s.scanTaskRows(rows)
@@ -177,16 +550,70 @@ func (s *SQLiteStorage) ListTasks() ([]*TaskInfo, error) {
 		}
 		tasks = append(tasks, task)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return tasks, nil
 }
 
-// DeleteTask 删除任务
+// DeleteTask 软删除任务
+// 任务记录和标签保留在数据库中，但不再出现在查询结果里，可以通过 RestoreTask 恢复，
+// 需要真正删除时使用 PurgeTask
 func (s *SQLiteStorage) DeleteTask(id int64) error {
-	_, err := s.db.Exec(`DELETE FROM tasks WHERE id = ?`, id)
+	_, err := s.db.Exec(`UPDATE tasks SET deleted_at = ? WHERE id = ? AND `+notDeleted, time.Now(), id)
 	return err
 }
 
+// ListDeletedTasks 按ID顺序列出所有已软删除的任务
+func (s *SQLiteStorage) ListDeletedTasks() ([]*TaskInfo, error) {
+	rows, err := s.db.Query(`SELECT ` + taskColumns + ` FROM tasks WHERE deleted_at IS NOT NULL ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	return s.collectTasks(rows)
+}
+
+// RestoreTask 恢复已软删除的任务，任务不存在或未被删除时返回错误
+func (s *SQLiteStorage) RestoreTask(id int64) error {
+	result, err := s.db.Exec(`
+		UPDATE tasks SET
+			deleted_at = NULL,
+			updated_at = ?
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("deleted task %d not found", id)
+	}
+	return nil
+}
+
+// PurgeTask 从数据库中永久删除任务及其标签，无论任务是否已被软删除
+func (s *SQLiteStorage) PurgeTask(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM tasks WHERE id = ?`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // UpdateTaskStatus 更新任务状态
 func (s *SQLiteStorage) UpdateTaskStatus(id int64, status TaskStatus) error {
 	_, err := s.db.Exec(`
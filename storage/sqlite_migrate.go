@@ -0,0 +1,132 @@
+// storage/sqlite_migrate.go
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration 表示一个数据库结构迁移步骤
+type migration struct {
+	version     int                 // 迁移后的版本号，按顺序递增
+	description string              // 迁移说明
+	apply       func(*sql.Tx) error // 在事务中执行迁移
+}
+
+// migrations 是按版本排序的迁移步骤，只能在末尾追加新步骤
+var migrations = []migration{
+	{1, "add priority column to tasks", addColumn("tasks", "priority", "INTEGER NOT NULL DEFAULT 5")},
+	{2, "add cron column to tasks", addColumn("tasks", "cron", "TEXT NOT NULL DEFAULT ''")},
+	{3, "add deleted_at column to tasks", addColumn("tasks", "deleted_at", "TIMESTAMP")},
+	{4, "add index on tasks status", createIndex("idx_tasks_status", "tasks", "status")},
+	{5, "add args column to tasks", addColumn("tasks", "args", "TEXT NOT NULL DEFAULT '[]'")},
+}
+
+// createIndex 返回创建索引的迁移函数，索引已存在时跳过
+func createIndex(name, table, columns string) func(*sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s(%s)`, name, table, columns))
+		return err
+	}
+}
+
+// addColumn 返回添加列的迁移函数，列已存在时跳过
+func addColumn(table, column, definition string) func(*sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		exists, err := columnExists(tx, table, column)
+		if err != nil || exists {
+			return err
+		}
+
+		_, err = tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+		return err
+	}
+}
+
+// columnExists 检查表中是否存在指定的列
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+
+	return false, rows.Err()
+}
+
+// migrate 依次执行尚未应用的迁移步骤并记录版本号
+func (s *SQLiteStorage) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version INTEGER PRIMARY KEY,
+			description TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		if err := s.applyMigration(m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration 在事务中执行单个迁移步骤
+func (s *SQLiteStorage) applyMigration(m migration) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.apply(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO schema_version (version, description, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.description, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SchemaVersion 返回数据库当前的结构版本号，未执行过迁移时为 0
+func (s *SQLiteStorage) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version)
+	return version, err
+}
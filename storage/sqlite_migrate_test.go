@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// createLegacyDB 创建一个没有版本表和新列的旧数据库，并写入一条任务
+func createLegacyDB(t *testing.T, path string) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE tasks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			content TEXT NOT NULL,
+			status TEXT NOT NULL,
+			interval INTEGER NOT NULL,
+			max_runs INTEGER NOT NULL,
+			retry_times INTEGER NOT NULL,
+			timeout INTEGER NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			last_run_at TIMESTAMP,
+			run_count INTEGER NOT NULL,
+			last_error TEXT,
+			description TEXT,
+			tags TEXT,
+			options TEXT
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO tasks (name, type, content, status, interval, max_runs, retry_times, timeout,
+			created_at, updated_at, run_count, last_error, description, tags, options)
+		VALUES ('legacy', 'lua', 'x = 1', 'idle', 0, 0, 0, 0, datetime('now'), datetime('now'), 0, '', '', '[]', '')
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert legacy task: %v", err)
+	}
+}
+
+// hasColumn 检查表中是否存在指定的列
+func hasColumn(t *testing.T, s *SQLiteStorage, table, column string) bool {
+	t.Helper()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	exists, err := columnExists(tx, table, column)
+	if err != nil {
+		t.Fatalf("Failed to inspect columns: %v", err)
+	}
+	return exists
+}
+
+// TestSchemaMigration 测试旧数据库升级到最新结构
+func TestSchemaMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	createLegacyDB(t, path)
+
+	s, err := NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+
+	for _, column := range []string{"priority", "cron"} {
+		if !hasColumn(t, s, "tasks", column) {
+			t.Errorf("Expected migration to add column %s", column)
+		}
+	}
+
+	latest := migrations[len(migrations)-1].version
+	if version, err := s.SchemaVersion(); err != nil || version != latest {
+		t.Errorf("Expected schema version %d, got %d (err: %v)", latest, version, err)
+	}
+
+	// 已有数据保持可读，新列使用默认值
+	task, err := s.GetTaskByName("legacy")
+	if err != nil {
+		t.Fatalf("Failed to read legacy task: %v", err)
+	}
+	var priority int
+	if err := s.db.QueryRow(`SELECT priority FROM tasks WHERE id = ?`, task.ID).Scan(&priority); err != nil || priority != 5 {
+		t.Errorf("Expected default priority 5, got %d (err: %v)", priority, err)
+	}
+	s.Close()
+
+	// 重新打开时不会重复执行迁移
+	s, err = NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer s.Close()
+
+	var applied int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&applied); err != nil || applied != len(migrations) {
+		t.Errorf("Expected %d recorded migrations, got %d (err: %v)", len(migrations), applied, err)
+	}
+}
+
+// TestSchemaMigrationFresh 测试新数据库直接升级到最新结构
+func TestSchemaMigrationFresh(t *testing.T) {
+	s := newTestStorage(t)
+
+	latest := migrations[len(migrations)-1].version
+	if version, err := s.SchemaVersion(); err != nil || version != latest {
+		t.Errorf("Expected schema version %d, got %d (err: %v)", latest, version, err)
+	}
+	if !hasColumn(t, s, "tasks", "cron") {
+		t.Error("Expected fresh database to have cron column")
+	}
+}
@@ -4,24 +4,24 @@ package storage
 import (
 	"database/sql"
 	"encoding/json"
-	"fmt"
 )
 
 // scanTask 扫描单行任务数据
 func (s *SQLiteStorage) scanTask(row *sql.Row) (*TaskInfo, error) {
 	var task TaskInfo
-	var tagsJSON string
-	var lastRunAtNull sql.NullTime
+	var tagsJSON, argsJSON string
+	var lastRunAtNull, deletedAtNull sql.NullTime
 
 	err := row.Scan(
 		&task.ID, &task.Name, &task.Type, &task.Content, &task.Status,
 		&task.Interval, &task.MaxRuns, &task.RetryTimes, &task.Timeout,
 		&task.CreatedAt, &task.UpdatedAt, &lastRunAtNull, &task.RunCount,
 		&task.LastError, &task.Description, &tagsJSON, &task.Options,
+		&task.Priority, &task.Cron, &deletedAtNull, &argsJSON,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("task not found")
+			return nil, ErrTaskNotFound
 		}
 		return nil, err
 	}
@@ -29,13 +29,21 @@ func (s *SQLiteStorage) scanTask(row *sql.Row) (*TaskInfo, error) {
 	if lastRunAtNull.Valid {
 		task.LastRunAt = lastRunAtNull.Time
 	}
+	if deletedAtNull.Valid {
+		task.DeletedAt = deletedAtNull.Time
+	}
 
-	// 解析标签
+	// 解析标签和参数
 	if tagsJSON != "" {
 		if err := json.Unmarshal([]byte(tagsJSON), &task.Tags); err != nil {
 			return nil, err
 		}
 	}
+	if argsJSON != "" && argsJSON != "[]" {
+		if err := json.Unmarshal([]byte(argsJSON), &task.Args); err != nil {
+			return nil, err
+		}
+	}
 
 	return &task, nil
 }
@@ -43,14 +51,15 @@ func (s *SQLiteStorage) scanTask(row *sql.Row) (*TaskInfo, error) {
 // scanTaskRows 扫描多行任务数据
 func (s *SQLiteStorage) scanTaskRows(rows *sql.Rows) (*TaskInfo, error) {
 	var task TaskInfo
-	var tagsJSON string
-	var lastRunAtNull sql.NullTime
+	var tagsJSON, argsJSON string
+	var lastRunAtNull, deletedAtNull sql.NullTime
 
 	err := rows.Scan(
 		&task.ID, &task.Name, &task.Type, &task.Content, &task.Status,
 		&task.Interval, &task.MaxRuns, &task.RetryTimes, &task.Timeout,
 		&task.CreatedAt, &task.UpdatedAt, &lastRunAtNull, &task.RunCount,
 		&task.LastError, &task.Description, &tagsJSON, &task.Options,
+		&task.Priority, &task.Cron, &deletedAtNull, &argsJSON,
 	)
 	if err != nil {
 		return nil, err
@@ -59,13 +68,21 @@ func (s *SQLiteStorage) scanTaskRows(rows *sql.Rows) (*TaskInfo, error) {
 	if lastRunAtNull.Valid {
 		task.LastRunAt = lastRunAtNull.Time
 	}
+	if deletedAtNull.Valid {
+		task.DeletedAt = deletedAtNull.Time
+	}
 
-	// 解析标签
+	// 解析标签和参数
 	if tagsJSON != "" {
 		if err := json.Unmarshal([]byte(tagsJSON), &task.Tags); err != nil {
 			return nil, err
 		}
 	}
+	if argsJSON != "" && argsJSON != "[]" {
+		if err := json.Unmarshal([]byte(argsJSON), &task.Args); err != nil {
+			return nil, err
+		}
+	}
 
 	return &task, nil
 }
@@ -0,0 +1,687 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestStorage 创建使用临时数据库的存储
+func newTestStorage(t *testing.T) *SQLiteStorage {
+	t.Helper()
+
+	s, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// newTestTask 创建用于测试的任务信息
+func newTestTask(name string, tags ...string) *TaskInfo {
+	return &TaskInfo{
+		Name:    name,
+		Type:    TaskTypeLua,
+		Content: "print('hello')",
+		Status:  TaskStatusIdle,
+		Tags:    tags,
+	}
+}
+
+// taskNames 返回任务名称列表
+func taskNames(tasks []*TaskInfo) []string {
+	names := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	return names
+}
+
+// tagRows 返回标签关联表中某个任务的所有标签
+func tagRows(t *testing.T, s *SQLiteStorage, taskID int64) []string {
+	t.Helper()
+
+	rows, err := s.db.Query(`SELECT tag FROM task_tags WHERE task_id = ? ORDER BY tag`, taskID)
+	if err != nil {
+		t.Fatalf("Failed to query task_tags: %v", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		rows.Scan(&tag)
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// TestSaveTaskTags 测试保存任务时维护标签关联表
+func TestSaveTaskTags(t *testing.T) {
+	s := newTestStorage(t)
+
+	task := newTestTask("backup", "daily", "db")
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	if tags := tagRows(t, s, task.ID); !reflect.DeepEqual(tags, []string{"daily", "db"}) {
+		t.Errorf("Expected tags [daily db], got %v", tags)
+	}
+
+	// 编辑任务时更新标签
+	task.Tags = []string{"weekly", "db"}
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	if tags := tagRows(t, s, task.ID); !reflect.DeepEqual(tags, []string{"db", "weekly"}) {
+		t.Errorf("Expected tags [db weekly] after update, got %v", tags)
+	}
+
+	// 永久删除任务时清理标签
+	if err := s.PurgeTask(task.ID); err != nil {
+		t.Fatalf("Failed to purge task: %v", err)
+	}
+	if tags := tagRows(t, s, task.ID); len(tags) != 0 {
+		t.Errorf("Expected no tags after purge, got %v", tags)
+	}
+}
+
+// TestGetTasksByTag 测试按标签查询任务
+func TestGetTasksByTag(t *testing.T) {
+	s := newTestStorage(t)
+
+	for _, task := range []*TaskInfo{
+		newTestTask("a", "backup", "daily"),
+		newTestTask("b", "backup"),
+		newTestTask("c", "daily"),
+		newTestTask("d"),
+	} {
+		if err := s.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	tasks, err := s.GetTasksByTag("backup")
+	if err != nil {
+		t.Fatalf("Failed to query tasks by tag: %v", err)
+	}
+	if names := taskNames(tasks); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("Expected tasks [a b] for tag backup, got %v", names)
+	}
+
+	tasks, err = s.GetTasksByTag("missing")
+	if err != nil {
+		t.Fatalf("Failed to query tasks by tag: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("Expected no tasks for missing tag, got %v", taskNames(tasks))
+	}
+
+	tags, err := s.ListTags()
+	if err != nil {
+		t.Fatalf("Failed to list tags: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"backup", "daily"}) {
+		t.Errorf("Expected tags [backup daily], got %v", tags)
+	}
+}
+
+// TestListTasksByTag 测试标签重叠时按标签列出任务
+func TestListTasksByTag(t *testing.T) {
+	s := newTestStorage(t)
+
+	tasks := []*TaskInfo{
+		newTestTask("db-backup", "backup", "db", "nightly"),
+		newTestTask("file-backup", "backup", "files"),
+		newTestTask("db-vacuum", "db", "nightly"),
+		newTestTask("report", "nightly"),
+	}
+	for _, task := range tasks {
+		if err := s.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+
+	expected := map[string][]string{
+		"backup":  {"db-backup", "file-backup"},
+		"db":      {"db-backup", "db-vacuum"},
+		"nightly": {"db-backup", "db-vacuum", "report"},
+		"files":   {"file-backup"},
+	}
+	for tag, names := range expected {
+		result, err := s.ListTasksByTag(tag)
+		if err != nil {
+			t.Fatalf("ListTasksByTag(%q) failed: %v", tag, err)
+		}
+		if got := taskNames(result); !reflect.DeepEqual(got, names) {
+			t.Errorf("ListTasksByTag(%q) = %v, expected %v", tag, got, names)
+		}
+	}
+
+	// 修改标签后查询结果随之更新，Tags 字段保持可用
+	tasks[3].Tags = []string{"backup"}
+	if err := s.SaveTask(tasks[3]); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	result, err := s.ListTasksByTag("backup")
+	if err != nil {
+		t.Fatalf("ListTasksByTag failed: %v", err)
+	}
+	if got := taskNames(result); !reflect.DeepEqual(got, []string{"db-backup", "file-backup", "report"}) {
+		t.Errorf("Expected report to be tagged backup, got %v", got)
+	}
+	if !reflect.DeepEqual(result[2].Tags, []string{"backup"}) {
+		t.Errorf("Expected Tags field [backup], got %v", result[2].Tags)
+	}
+}
+
+// TestMigrateTags 测试从 JSON 列迁移已有标签
+func TestMigrateTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	s, err := NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	task := newTestTask("legacy", "old", "tag")
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	// 模拟旧数据库：标签只存在于 JSON 列中
+	if _, err := s.db.Exec(`DELETE FROM task_tags`); err != nil {
+		t.Fatalf("Failed to clear task_tags: %v", err)
+	}
+	s.Close()
+
+	s, err = NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen storage: %v", err)
+	}
+	defer s.Close()
+
+	if tags := tagRows(t, s, task.ID); !reflect.DeepEqual(tags, []string{"old", "tag"}) {
+		t.Errorf("Expected migrated tags [old tag], got %v", tags)
+	}
+
+	// 再次迁移应该是幂等的
+	if err := s.migrateTags(); err != nil {
+		t.Fatalf("Failed to re-run migration: %v", err)
+	}
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM task_tags`).Scan(&count); err != nil && err != sql.ErrNoRows {
+		t.Fatalf("Failed to count task_tags: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 tag rows after re-running migration, got %d", count)
+	}
+}
+
+// TestSaveTaskWithID 测试按指定ID保存不存在的任务
+func TestSaveTaskWithID(t *testing.T) {
+	s := newTestStorage(t)
+
+	task := newTestTask("imported", "a")
+	task.ID = 42
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	got, err := s.GetTask(42)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if got.Name != "imported" || len(got.Tags) != 1 {
+		t.Errorf("Unexpected task: %+v", got)
+	}
+
+	// 之后创建的任务ID应继续递增
+	next := newTestTask("next")
+	if err := s.SaveTask(next); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+	if next.ID <= 42 {
+		t.Errorf("Expected new task ID to be greater than 42, got %d", next.ID)
+	}
+}
+
+// TestSaveTaskPriorityCron 测试优先级和 cron 表达式在各存储实现中的读写
+func TestSaveTaskPriorityCron(t *testing.T) {
+	stores := map[string]Storage{
+		"sqlite": newTestStorage(t),
+		"memory": NewMemoryStorage(),
+	}
+
+	for name, s := range stores {
+		task := newTestTask("nightly")
+		task.Priority = 8
+		task.Cron = "0 2 * * *"
+		if err := s.SaveTask(task); err != nil {
+			t.Fatalf("[%s] Failed to save task: %v", name, err)
+		}
+
+		got, err := s.GetTask(task.ID)
+		if err != nil {
+			t.Fatalf("[%s] Failed to get task: %v", name, err)
+		}
+		if got.Priority != 8 || got.Cron != "0 2 * * *" {
+			t.Errorf("[%s] Expected priority 8 and cron %q, got %d and %q", name, "0 2 * * *", got.Priority, got.Cron)
+		}
+
+		// 更新后清除 cron
+		task.Priority = 3
+		task.Cron = ""
+		if err := s.SaveTask(task); err != nil {
+			t.Fatalf("[%s] Failed to update task: %v", name, err)
+		}
+
+		tasks, err := s.ListTasks()
+		if err != nil {
+			t.Fatalf("[%s] Failed to list tasks: %v", name, err)
+		}
+		if len(tasks) != 1 || tasks[0].Priority != 3 || tasks[0].Cron != "" {
+			t.Errorf("[%s] Unexpected tasks after update: %+v", name, tasks)
+		}
+	}
+}
+
+// seedTasks 保存 n 个任务，偶数为 Lua 空闲任务，奇数为 Shell 运行中任务
+func seedTasks(t *testing.T, s *SQLiteStorage, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		task := newTestTask(fmt.Sprintf("task-%02d", i))
+		if i%2 == 1 {
+			task.Type = TaskTypeShell
+			task.Status = TaskStatusRunning
+		}
+		if err := s.SaveTask(task); err != nil {
+			t.Fatalf("Failed to save task: %v", err)
+		}
+	}
+}
+
+// TestListTasksFilteredPagination 测试分页边界
+func TestListTasksFilteredPagination(t *testing.T) {
+	s := newTestStorage(t)
+	seedTasks(t, s, 50)
+
+	tests := []struct {
+		opts  ListOptions
+		count int
+		first string
+	}{
+		{ListOptions{}, 50, "task-00"},
+		{ListOptions{Limit: 20}, 20, "task-00"},
+		{ListOptions{Limit: 20, Offset: 40}, 10, "task-40"},
+		{ListOptions{Offset: 45}, 5, "task-45"},
+		{ListOptions{Limit: 20, Offset: 50}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		tasks, err := s.ListTasksFiltered(tt.opts)
+		if err != nil {
+			t.Fatalf("ListTasksFiltered(%+v) failed: %v", tt.opts, err)
+		}
+		if len(tasks) != tt.count {
+			t.Errorf("ListTasksFiltered(%+v) returned %d tasks, expected %d", tt.opts, len(tasks), tt.count)
+			continue
+		}
+		if tt.count > 0 && tasks[0].Name != tt.first {
+			t.Errorf("ListTasksFiltered(%+v) first task = %s, expected %s", tt.opts, tasks[0].Name, tt.first)
+		}
+	}
+
+	// 分页不影响总数
+	total, err := s.CountTasks(ListOptions{Limit: 5, Offset: 10})
+	if err != nil {
+		t.Fatalf("CountTasks failed: %v", err)
+	}
+	if total != 50 {
+		t.Errorf("Expected total count 50, got %d", total)
+	}
+}
+
+// TestListTasksFilteredConditions 测试按状态、类型和名称前缀过滤
+func TestListTasksFilteredConditions(t *testing.T) {
+	s := newTestStorage(t)
+	seedTasks(t, s, 50)
+
+	// 名称中包含 LIKE 通配符的任务
+	if err := s.SaveTask(newTestTask("task_%special")); err != nil {
+		t.Fatalf("Failed to save task: %v", err)
+	}
+
+	tests := []struct {
+		opts  ListOptions
+		count int
+	}{
+		{ListOptions{Status: TaskStatusRunning}, 25},
+		{ListOptions{Status: TaskStatusIdle}, 26},
+		{ListOptions{Type: TaskTypeShell, Status: TaskStatusRunning}, 25},
+		{ListOptions{Type: TaskTypeShell, Status: TaskStatusIdle}, 0},
+		{ListOptions{NamePrefix: "task-1"}, 10},
+		{ListOptions{NamePrefix: "task_%"}, 1},
+		{ListOptions{NamePrefix: "x' OR '1'='1"}, 0},
+	}
+
+	for _, tt := range tests {
+		count, err := s.CountTasks(tt.opts)
+		if err != nil {
+			t.Fatalf("CountTasks(%+v) failed: %v", tt.opts, err)
+		}
+		if count != tt.count {
+			t.Errorf("CountTasks(%+v) = %d, expected %d", tt.opts, count, tt.count)
+		}
+
+		tasks, err := s.ListTasksFiltered(tt.opts)
+		if err != nil {
+			t.Fatalf("ListTasksFiltered(%+v) failed: %v", tt.opts, err)
+		}
+		if len(tasks) != tt.count {
+			t.Errorf("ListTasksFiltered(%+v) returned %d tasks, expected %d", tt.opts, len(tasks), tt.count)
+		}
+	}
+
+	// 过滤和分页组合
+	tasks, err := s.ListTasksFiltered(ListOptions{Status: TaskStatusRunning, Limit: 10, Offset: 20})
+	if err != nil {
+		t.Fatalf("ListTasksFiltered failed: %v", err)
+	}
+	if len(tasks) != 5 || tasks[0].Name != "task-41" {
+		t.Errorf("Expected 5 running tasks starting at task-41, got %v", taskNames(tasks))
+	}
+}
+
+// TestSaveTasksBatch 测试在一个事务中批量保存任务
+func TestSaveTasksBatch(t *testing.T) {
+	s := newTestStorage(t)
+
+	tasks := make([]*TaskInfo, 100)
+	for i := range tasks {
+		tasks[i] = newTestTask(fmt.Sprintf("batch-%03d", i), "batch")
+	}
+	if err := s.SaveTasks(tasks); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+
+	seen := make(map[int64]bool)
+	for _, task := range tasks {
+		if task.ID == 0 || seen[task.ID] {
+			t.Fatalf("Expected unique assigned ID, got %d for %s", task.ID, task.Name)
+		}
+		seen[task.ID] = true
+	}
+
+	saved, err := s.ListTasksByTag("batch")
+	if err != nil {
+		t.Fatalf("ListTasksByTag failed: %v", err)
+	}
+	if len(saved) != 100 {
+		t.Fatalf("Expected 100 saved tasks, got %d", len(saved))
+	}
+
+	// 更新已有任务和创建新任务可以在同一批中进行
+	tasks[0].Description = "updated"
+	if err := s.SaveTasks([]*TaskInfo{tasks[0], newTestTask("batch-new")}); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+	got, err := s.GetTask(tasks[0].ID)
+	if err != nil || got.Description != "updated" {
+		t.Errorf("Expected updated description, got %+v, %v", got, err)
+	}
+}
+
+// TestSaveTasksRollback 测试批量保存失败时整批回滚
+func TestSaveTasksRollback(t *testing.T) {
+	s := newTestStorage(t)
+
+	existing := newTestTask("existing")
+	if err := s.SaveTask(existing); err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+
+	existing.Description = "changed"
+	first := newTestTask("first", "rollback")
+	batch := []*TaskInfo{first, existing, nil, newTestTask("last", "rollback")}
+
+	if err := s.SaveTasks(batch); err == nil {
+		t.Fatal("Expected SaveTasks to fail on a nil task")
+	}
+
+	tasks, err := s.ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if names := taskNames(tasks); !reflect.DeepEqual(names, []string{"existing"}) {
+		t.Errorf("Expected only the existing task after rollback, got %v", names)
+	}
+	if got, _ := s.GetTask(existing.ID); got.Description != "" {
+		t.Errorf("Expected update to be rolled back, got description %q", got.Description)
+	}
+	if tagged, _ := s.ListTasksByTag("rollback"); len(tagged) != 0 {
+		t.Errorf("Expected tags to be rolled back, got %v", taskNames(tagged))
+	}
+	if first.ID != 0 {
+		t.Errorf("Expected assigned ID to be reset after rollback, got %d", first.ID)
+	}
+}
+
+// TestSoftDeleteRestore 测试软删除的任务被隐藏并可以恢复
+func TestSoftDeleteRestore(t *testing.T) {
+	s := newTestStorage(t)
+
+	task := newTestTask("report", "daily")
+	other := newTestTask("other", "daily")
+	if err := s.SaveTasks([]*TaskInfo{task, other}); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+
+	if err := s.DeleteTask(task.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	// 软删除的任务不出现在查询结果中
+	if _, err := s.GetTask(task.ID); err == nil {
+		t.Error("Expected GetTask to hide the deleted task")
+	}
+	if _, err := s.GetTaskByName("report"); err == nil {
+		t.Error("Expected GetTaskByName to hide the deleted task")
+	}
+	if tasks, _ := s.ListTasks(); !reflect.DeepEqual(taskNames(tasks), []string{"other"}) {
+		t.Errorf("Expected only other in ListTasks, got %v", taskNames(tasks))
+	}
+	if tasks, _ := s.ListTasksByTag("daily"); !reflect.DeepEqual(taskNames(tasks), []string{"other"}) {
+		t.Errorf("Expected only other in ListTasksByTag, got %v", taskNames(tasks))
+	}
+	if count, _ := s.CountTasks(ListOptions{}); count != 1 {
+		t.Errorf("Expected count 1, got %d", count)
+	}
+
+	deleted, err := s.ListDeletedTasks()
+	if err != nil {
+		t.Fatalf("ListDeletedTasks failed: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != task.ID || deleted[0].DeletedAt.IsZero() {
+		t.Fatalf("Expected report in deleted tasks, got %+v", deleted)
+	}
+
+	if err := s.RestoreTask(task.ID); err != nil {
+		t.Fatalf("RestoreTask failed: %v", err)
+	}
+	restored, err := s.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Expected restored task to be visible, got %v", err)
+	}
+	if !restored.DeletedAt.IsZero() || !reflect.DeepEqual(restored.Tags, []string{"daily"}) {
+		t.Errorf("Expected restored task with its tags, got %+v", restored)
+	}
+	if deleted, _ := s.ListDeletedTasks(); len(deleted) != 0 {
+		t.Errorf("Expected no deleted tasks after restore, got %v", taskNames(deleted))
+	}
+
+	// 未被删除的任务不能恢复
+	if err := s.RestoreTask(task.ID); err == nil {
+		t.Error("Expected RestoreTask to fail for a task that is not deleted")
+	}
+}
+
+// TestPurgeTask 测试永久删除任务
+func TestPurgeTask(t *testing.T) {
+	s := newTestStorage(t)
+
+	task := newTestTask("temp", "scratch")
+	if err := s.SaveTask(task); err != nil {
+		t.Fatalf("SaveTask failed: %v", err)
+	}
+	if err := s.DeleteTask(task.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if err := s.PurgeTask(task.ID); err != nil {
+		t.Fatalf("PurgeTask failed: %v", err)
+	}
+
+	if deleted, _ := s.ListDeletedTasks(); len(deleted) != 0 {
+		t.Errorf("Expected purged task to be gone, got %v", taskNames(deleted))
+	}
+	if err := s.RestoreTask(task.ID); err == nil {
+		t.Error("Expected RestoreTask to fail after purge")
+	}
+	if tags := tagRows(t, s, task.ID); len(tags) != 0 {
+		t.Errorf("Expected tags to be purged, got %v", tags)
+	}
+}
+
+// TestGetTasksByStatus 测试按状态查询任务
+func TestGetTasksByStatus(t *testing.T) {
+	s := newTestStorage(t)
+
+	statuses := []TaskStatus{TaskStatusRunning, TaskStatusCompleted, TaskStatusRunning, TaskStatusFailed, TaskStatusRunning}
+	tasks := make([]*TaskInfo, len(statuses))
+	for i, status := range statuses {
+		tasks[i] = newTestTask(fmt.Sprintf("task-%d", i))
+		tasks[i].Status = status
+	}
+	if err := s.SaveTasks(tasks); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+
+	// 软删除的任务不返回
+	if err := s.DeleteTask(tasks[4].ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	running, err := s.GetTasksByStatus(TaskStatusRunning)
+	if err != nil {
+		t.Fatalf("GetTasksByStatus failed: %v", err)
+	}
+	if names := taskNames(running); !reflect.DeepEqual(names, []string{"task-0", "task-2"}) {
+		t.Errorf("Expected [task-0 task-2], got %v", names)
+	}
+	for _, task := range running {
+		if task.Status != TaskStatusRunning {
+			t.Errorf("Expected only running tasks, got %s with status %s", task.Name, task.Status)
+		}
+	}
+
+	if paused, _ := s.GetTasksByStatus(TaskStatusPaused); len(paused) != 0 {
+		t.Errorf("Expected no paused tasks, got %v", taskNames(paused))
+	}
+
+	// 查询使用状态索引
+	var detail string
+	rows, err := s.db.Query(`EXPLAIN QUERY PLAN SELECT id FROM tasks WHERE status = ?`, TaskStatusRunning)
+	if err != nil {
+		t.Fatalf("EXPLAIN failed: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, parent, notUsed int
+		var line string
+		rows.Scan(&id, &parent, &notUsed, &line)
+		detail += line
+	}
+	if !strings.Contains(detail, "idx_tasks_status") {
+		t.Errorf("Expected status query to use idx_tasks_status, got %q", detail)
+	}
+}
+
+// TestSQLiteConcurrentUpdates 测试并发更新任务时不会出现数据库锁定错误
+func TestSQLiteConcurrentUpdates(t *testing.T) {
+	s := newTestStorage(t)
+
+	var mode string
+	if err := s.db.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil || !strings.EqualFold(mode, "wal") {
+		t.Errorf("Expected WAL journal mode, got %q (err: %v)", mode, err)
+	}
+
+	tasks := make([]*TaskInfo, 10)
+	for i := range tasks {
+		tasks[i] = newTestTask(fmt.Sprintf("concurrent-%d", i))
+	}
+	if err := s.SaveTasks(tasks); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+
+	statuses := []TaskStatus{TaskStatusRunning, TaskStatusPaused, TaskStatusCompleted}
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			task := tasks[i%len(tasks)]
+			if err := s.UpdateTaskStatus(task.ID, statuses[i%len(statuses)]); err != nil {
+				errs <- err
+				return
+			}
+			if err := s.UpdateTaskRunInfo(task.ID, i, time.Now(), ""); err != nil {
+				errs <- err
+				return
+			}
+			if err := s.SaveTask(newTestTask(fmt.Sprintf("new-%d", i), "concurrent")); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Concurrent update failed: %v", err)
+	}
+}
+
+// TestSaveTaskArgs 测试保存和读取任务参数
+func TestSaveTaskArgs(t *testing.T) {
+	s := newTestStorage(t)
+
+	task := newTestTask("with-args")
+	task.Args = []string{"--env", "prod", "a b"}
+	noArgs := newTestTask("no-args")
+	if err := s.SaveTasks([]*TaskInfo{task, noArgs}); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+
+	got, err := s.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.Args, task.Args) {
+		t.Errorf("Expected args %v, got %v", task.Args, got.Args)
+	}
+
+	if got, _ := s.GetTask(noArgs.ID); got.Args != nil {
+		t.Errorf("Expected no args, got %v", got.Args)
+	}
+}
@@ -0,0 +1,41 @@
+// storage/storage.go
+package storage
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTaskNotFound 表示任务不存在
+var ErrTaskNotFound = errors.New("task not found")
+
+// Storage 定义任务存储后端需要实现的接口
+type Storage interface {
+	// SaveTask 保存任务，ID 为 0 时创建新任务并回填 ID；
+	// ID 非零但任务不存在时按该 ID 创建，以便在存储之间迁移
+	SaveTask(task *TaskInfo) error
+
+	// GetTask 根据ID获取任务，不存在时返回 ErrTaskNotFound
+	GetTask(id int64) (*TaskInfo, error)
+
+	// GetTaskByName 根据名称获取任务，不存在时返回 ErrTaskNotFound
+	GetTaskByName(name string) (*TaskInfo, error)
+
+	// ListTasks 按ID顺序列出所有任务
+	ListTasks() ([]*TaskInfo, error)
+
+	// GetTasksByStatus 按ID顺序列出指定状态的任务
+	GetTasksByStatus(status TaskStatus) ([]*TaskInfo, error)
+
+	// DeleteTask 删除任务
+	DeleteTask(id int64) error
+
+	// UpdateTaskStatus 更新任务状态
+	UpdateTaskStatus(id int64, status TaskStatus) error
+
+	// UpdateTaskRunInfo 更新任务运行信息
+	UpdateTaskRunInfo(id int64, runCount int, lastRunAt time.Time, lastError string) error
+}
+
+// 确保 SQLiteStorage 实现了 Storage 接口
+var _ Storage = (*SQLiteStorage)(nil)
@@ -26,6 +26,7 @@ const (
 	TaskStatusCompleted  TaskStatus = "completed"  // 已完成
 	TaskStatusFailed     TaskStatus = "failed"     // 失败
 	TaskStatusCancelled  TaskStatus = "cancelled"  // 已取消
+	TaskStatusDisabled   TaskStatus = "disabled"   // 已禁用
 )
 
 // TaskInfo 表示任务信息
@@ -47,4 +48,17 @@ type TaskInfo struct {
 	Description string     `json:"description"`  // 任务描述
 	Tags        []string   `json:"tags"`         // 标签
 	Options     string     `json:"options"`      // 其他选项（JSON格式）
+	Priority    int        `json:"priority"`     // 优先级（1-10），0 表示使用默认优先级
+	Cron        string     `json:"cron"`         // cron 表达式，为空表示不使用 cron 调度
+	DeletedAt   time.Time  `json:"deleted_at"`   // 软删除时间，零值表示未删除
+	Args        []string   `json:"args"`         // 任务参数，Lua 脚本通过全局表 arg 读取，Shell 命令作为位置参数
+}
+
+// ListOptions 表示任务列表的过滤和分页条件，零值字段不参与过滤
+type ListOptions struct {
+	Limit      int        // 最多返回的任务数，0 表示不限制
+	Offset     int        // 跳过的任务数
+	Status     TaskStatus // 按状态过滤
+	Type       TaskType   // 按类型过滤
+	NamePrefix string     // 按名称前缀过滤
 }